@@ -44,10 +44,13 @@ func (r *Runner) createLogger(_logger zerolog.Logger, uploads *sync.WaitGroup, w
 
 		logger.Debug().Msg("log stream opened")
 
-		logStream := log.NewLineWriter(r.client, step.UUID, secrets...)
+		logStream := log.NewLimitedLineWriter(r.client, step.UUID, r.logLimit, secrets...)
 		if err := log.CopyLineByLine(logStream, rc, pipeline.MaxLogLineLength); err != nil {
 			logger.Error().Err(err).Msg("copy limited logStream part")
 		}
+		if err := logStream.Close(); err != nil {
+			logger.Error().Err(err).Msg("close logStream")
+		}
 
 		logger.Debug().Msg("log stream copied, close ...")
 		uploads.Done()
@@ -479,12 +479,17 @@ func (c *client) sendLogs(ctx context.Context, entries []*proto.LogEntry) error
 	return nil
 }
 
-func (c *client) RegisterAgent(ctx context.Context, platform, backend, version string, capacity int) (int64, error) {
+func (c *client) RegisterAgent(ctx context.Context, info rpc.AgentInfo) (int64, error) {
 	req := new(proto.RegisterAgentRequest)
-	req.Platform = platform
-	req.Backend = backend
-	req.Version = version
-	req.Capacity = int32(capacity)
+	req.Platform = info.Platform
+	req.Backend = info.Backend
+	req.Version = info.Version
+	req.Capacity = int32(info.Capacity)
+	req.Resources = &proto.Resources{
+		Cpu:    info.Resources.CPU,
+		Memory: info.Resources.Memory,
+		Disk:   info.Resources.Disk,
+	}
 
 	res, err := c.client.RegisterAgent(ctx, req)
 	return res.GetAgentId(), err
@@ -0,0 +1,112 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package org
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/docker/go-units"
+	"github.com/urfave/cli/v3"
+
+	"go.woodpecker-ci.org/woodpecker/v2/cli/internal"
+	"go.woodpecker-ci.org/woodpecker/v2/woodpecker-go/woodpecker"
+)
+
+var orgUpdateCmd = &cli.Command{
+	Name:      "update",
+	Usage:     "update an organization (requires admin rights)",
+	ArgsUsage: "<org-id|org-full-name>",
+	Action:    orgUpdate,
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:  "priority",
+			Usage: "organization priority added to the priority of its workflows in the queue",
+		},
+		&cli.IntFlag{
+			Name:  "weight",
+			Usage: "organization weight used by fair-share scheduling",
+		},
+		&cli.IntFlag{
+			Name:  "max-concurrent",
+			Usage: "maximum number of concurrently running workflows of the organization (0 for unlimited)",
+		},
+		&cli.StringFlag{
+			Name:  "log-quota",
+			Usage: "maximum size of the stored logs of all repositories of the organization, e.g. 100GiB (0 for the server default)",
+		},
+	},
+}
+
+func orgUpdate(ctx context.Context, c *cli.Command) error {
+	client, err := internal.NewClient(ctx, c)
+	if err != nil {
+		return err
+	}
+	orgID, err := parseOrg(client, c.Args().First())
+	if err != nil {
+		return err
+	}
+
+	patch := new(woodpecker.OrgPatch)
+	if c.IsSet("priority") {
+		priority := int(c.Int("priority"))
+		patch.Priority = &priority
+	}
+	if c.IsSet("weight") {
+		weight := int(c.Int("weight"))
+		patch.Weight = &weight
+	}
+	if c.IsSet("max-concurrent") {
+		maxConcurrent := int(c.Int("max-concurrent"))
+		patch.MaxConcurrent = &maxConcurrent
+	}
+	if c.IsSet("log-quota") {
+		logQuota, err := units.RAMInBytes(c.String("log-quota"))
+		if err != nil {
+			return fmt.Errorf("invalid log quota: %w", err)
+		}
+		patch.LogQuota = &logQuota
+	}
+
+	org, err := client.OrgUpdate(orgID, patch)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Successfully updated organization %s\n", org.Name)
+	return nil
+}
+
+func parseOrg(client woodpecker.Client, orgIDOrName string) (int64, error) {
+	if orgIDOrName == "" {
+		return -1, fmt.Errorf("missing organization id or name")
+	}
+
+	if orgID, err := strconv.ParseInt(orgIDOrName, 10, 64); err == nil {
+		return orgID, nil
+	}
+
+	org, err := client.OrgLookup(orgIDOrName)
+	if err != nil {
+		return -1, err
+	}
+
+	return org.ID, nil
+}
@@ -0,0 +1,107 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/template"
+
+	"github.com/urfave/cli/v3"
+
+	"go.woodpecker-ci.org/woodpecker/v2/cli/common"
+	"go.woodpecker-ci.org/woodpecker/v2/cli/internal"
+)
+
+var pipelineExplainCmd = &cli.Command{
+	Name:      "explain",
+	Usage:     "explain why the workflows of a pipeline are still pending",
+	ArgsUsage: "<repo-id|repo-full-name> <pipeline>",
+	Action:    pipelineExplain,
+	Flags:     []cli.Flag{common.FormatFlag(tmplPipelineExplain)},
+}
+
+func pipelineExplain(ctx context.Context, c *cli.Command) error {
+	repoIDOrFullName := c.Args().First()
+	client, err := internal.NewClient(ctx, c)
+	if err != nil {
+		return err
+	}
+	repoID, err := internal.ParseRepo(client, repoIDOrFullName)
+	if err != nil {
+		return fmt.Errorf("invalid repo '%s': %w", repoIDOrFullName, err)
+	}
+
+	pipelineArg := c.Args().Get(1)
+	var number int64
+
+	if pipelineArg == "last" || len(pipelineArg) == 0 {
+		// Fetch the pipeline number from the last pipeline
+		pipeline, err := client.PipelineLast(repoID, "")
+		if err != nil {
+			return err
+		}
+
+		number = pipeline.Number
+	} else {
+		number, err = strconv.ParseInt(pipelineArg, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid pipeline '%s': %w", pipelineArg, err)
+		}
+	}
+
+	explanations, err := client.PipelineExplain(repoID, number)
+	if err != nil {
+		return err
+	}
+
+	if len(explanations) == 0 {
+		fmt.Println("there are no pending workflows")
+		return nil
+	}
+
+	tmpl, err := template.New("_").Parse(c.String("format") + "\n")
+	if err != nil {
+		return err
+	}
+
+	for _, explanation := range explanations {
+		if err := tmpl.Execute(os.Stdout, explanation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// template for pipeline explain information.
+var tmplPipelineExplain = "\x1b[33m{{ .Workflow }} (#{{ .TaskID }}):\x1b[0m" + `
+State: {{ .State }}
+{{- range .Reasons }}
+Reason: {{ . }}
+{{- end }}
+{{- if .WaitingFor }}
+Waiting for: {{ range $i, $w := .WaitingFor }}{{ if $i }}, {{ end }}{{ $w }}{{ end }}
+{{- end }}
+Agents:
+{{- range .Agents }}
+  {{ .AgentName }} (#{{ .AgentID }}): {{ if .Eligible }}eligible{{ else }}rejected{{ end }}{{ if .Reason }}, {{ .Reason }}{{ end }}
+{{- else }} none connected
+{{- end }}
+`
@@ -17,8 +17,10 @@ package pipeline
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
+	"strings"
 	"text/template"
 
 	"github.com/urfave/cli/v3"
@@ -33,6 +35,35 @@ var pipelineLogsCmd = &cli.Command{
 	ArgsUsage: "<repo-id|repo-full-name> <pipeline> [step-id|step-name]",
 	// TODO: for v3.0 do `ArgsUsage: "<repo-id|repo-full-name> <pipeline> [step-number|step-name]",`
 	Action: pipelineLogs,
+	Commands: []*cli.Command{
+		pipelineLogsSearchCmd,
+	},
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "lines",
+			Usage: "only show the lines in the range, e.g. '100-200' or '100-'",
+		},
+		&cli.IntFlag{
+			Name:  "offset",
+			Usage: "skip the first log entries",
+		},
+		&cli.IntFlag{
+			Name:  "limit",
+			Usage: "show at most this number of log entries",
+		},
+		&cli.StringFlag{
+			Name:  "archive",
+			Usage: "download the logs of all steps as archive to the file, a tar.gz or zip archive depending on its extension ('-' writes a tar.gz archive to stdout)",
+		},
+		&cli.BoolFlag{
+			Name:  "sections",
+			Usage: "only list the sections of the logs with their durations",
+		},
+		&cli.BoolFlag{
+			Name:  "strip-ansi",
+			Usage: "remove ANSI escape sequences like colors from the logs in the archive",
+		},
+	},
 }
 
 func pipelineLogs(ctx context.Context, c *cli.Command) error {
@@ -58,19 +89,79 @@ func pipelineLogs(ctx context.Context, c *cli.Command) error {
 		return fmt.Errorf("invalid pipeline '%s': %w", pipelineArg, err)
 	}
 
+	if archive := c.String("archive"); archive != "" {
+		return downloadLogArchive(client, repoID, number, archive, c.Bool("strip-ansi"))
+	}
+
+	logRange, err := parseLogRange(c)
+	if err != nil {
+		return err
+	}
+
 	stepArg := c.Args().Get(2) //nolint:mnd
 	if len(stepArg) == 0 {
-		return showPipelineLog(client, repoID, number)
+		return showPipelineLog(client, repoID, number, logRange, c.Bool("sections"))
 	}
 
 	step, err := internal.ParseStep(client, repoID, number, stepArg)
 	if err != nil {
 		return fmt.Errorf("invalid step '%s': %w", stepArg, err)
 	}
-	return showStepLog(client, repoID, number, step)
+	return showStepLog(client, repoID, number, step, logRange, c.Bool("sections"))
+}
+
+func parseLogRange(c *cli.Command) (woodpecker.LogRange, error) {
+	r := woodpecker.LogRange{
+		Offset: int(c.Int("offset")),
+		Limit:  int(c.Int("limit")),
+	}
+
+	lines := c.String("lines")
+	if lines == "" {
+		return r, nil
+	}
+	from, to, _ := strings.Cut(lines, "-")
+	var err error
+	if r.FromLine, err = strconv.Atoi(from); err != nil {
+		return r, fmt.Errorf("invalid line range '%s'", lines)
+	}
+	if to != "" {
+		if r.ToLine, err = strconv.Atoi(to); err != nil || r.ToLine < r.FromLine {
+			return r, fmt.Errorf("invalid line range '%s'", lines)
+		}
+	}
+	return r, nil
 }
 
-func showPipelineLog(client woodpecker.Client, repoID, number int64) error {
+func downloadLogArchive(client woodpecker.Client, repoID, number int64, file string, stripANSI bool) error {
+	format := "tar.gz"
+	if strings.HasSuffix(file, ".zip") {
+		format = "zip"
+	}
+
+	archive, err := client.PipelineLogArchive(repoID, number, format, stripANSI)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	if file == "-" {
+		_, err = io.Copy(os.Stdout, archive)
+		return err
+	}
+
+	out, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, archive); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func showPipelineLog(client woodpecker.Client, repoID, number int64, logRange woodpecker.LogRange, sections bool) error {
 	pipeline, err := client.Pipeline(repoID, number)
 	if err != nil {
 		return err
@@ -86,7 +177,7 @@ func showPipelineLog(client woodpecker.Client, repoID, number int64) error {
 			if err := tmpl.Execute(os.Stdout, map[string]any{"workflow": workflow, "step": step}); err != nil {
 				return err
 			}
-			err := showStepLog(client, repoID, number, step.ID)
+			err := showStepLog(client, repoID, number, step.ID, logRange, sections)
 			if err != nil {
 				return err
 			}
@@ -96,18 +187,84 @@ func showPipelineLog(client woodpecker.Client, repoID, number int64) error {
 	return nil
 }
 
-func showStepLog(client woodpecker.Client, repoID, number, step int64) error {
-	logs, err := client.StepLogEntries(repoID, number, step)
+func showStepLog(client woodpecker.Client, repoID, number, step int64, logRange woodpecker.LogRange, sections bool) error {
+	logs, err := client.StepLogEntriesRange(repoID, number, step, logRange)
 	if err != nil {
 		return err
 	}
 
+	if sections {
+		for _, section := range logSections(logs) {
+			fmt.Printf("%s%s (line %d, %s)\n", strings.Repeat("  ", section.Depth), section.Name, section.Line+1, section.duration())
+		}
+		return nil
+	}
+
+	var open []*logSection
 	for _, log := range logs {
-		fmt.Println(string(log.Data))
+		switch log.Type {
+		case woodpecker.LogEntrySectionStart:
+			open = append(open, &logSection{Name: string(log.Data), Started: log.Time})
+			fmt.Printf("\x1b[1m> %s\x1b[0m\n", log.Data)
+		case woodpecker.LogEntrySectionEnd:
+			duration := ""
+			if len(open) > 0 {
+				duration = fmt.Sprintf(" (%ds)", log.Time-open[len(open)-1].Started)
+				open = open[:len(open)-1]
+			}
+			fmt.Printf("\x1b[1m< %s%s\x1b[0m\n", log.Data, duration)
+		default:
+			fmt.Println(string(log.Data))
+		}
 	}
 
 	return nil
 }
 
+// logSection is a section of the logs of a step marked by section start and end entries.
+type logSection struct {
+	Name     string
+	Depth    int
+	Line     int
+	Started  int64
+	Finished int64
+	Open     bool
+}
+
+func (s *logSection) duration() string {
+	if s.Open {
+		return "not finished"
+	}
+	return fmt.Sprintf("%ds", s.Finished-s.Started)
+}
+
+// logSections returns the sections of the log entries in the order they were started.
+func logSections(logs []*woodpecker.LogEntry) []*logSection {
+	var sections, open []*logSection
+	for _, log := range logs {
+		switch log.Type {
+		case woodpecker.LogEntrySectionStart:
+			section := &logSection{
+				Name:    string(log.Data),
+				Depth:   len(open),
+				Line:    log.Line,
+				Started: log.Time,
+				Open:    true,
+			}
+			sections = append(sections, section)
+			open = append(open, section)
+		case woodpecker.LogEntrySectionEnd:
+			if len(open) == 0 {
+				continue
+			}
+			section := open[len(open)-1]
+			section.Finished = log.Time
+			section.Open = false
+			open = open[:len(open)-1]
+		}
+	}
+	return sections
+}
+
 // template for pipeline ps information.
 var tmplPipelineLogs = "\x1b[33m{{ .workflow.Name }} > {{ .step.Name }} (#{{ .step.PID }}):\x1b[0m"
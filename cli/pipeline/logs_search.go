@@ -0,0 +1,131 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/template"
+
+	"github.com/urfave/cli/v3"
+
+	"go.woodpecker-ci.org/woodpecker/v2/cli/common"
+	"go.woodpecker-ci.org/woodpecker/v2/cli/internal"
+	"go.woodpecker-ci.org/woodpecker/v2/woodpecker-go/woodpecker"
+)
+
+var pipelineLogsSearchCmd = &cli.Command{
+	Name:      "search",
+	Usage:     "search the pipeline logs of a repository",
+	ArgsUsage: "<repo-id|repo-full-name> <query>",
+	Action:    pipelineLogsSearch,
+	Flags: []cli.Flag{
+		common.FormatFlag(tmplPipelineLogsSearch),
+		&cli.BoolFlag{
+			Name:  "regex",
+			Usage: "search for a regular expression",
+		},
+		&cli.IntFlag{
+			Name:  "days",
+			Usage: "only search the pipelines of the last days",
+			Value: 7, //nolint:mnd
+		},
+		&cli.IntFlag{
+			Name:  "context",
+			Usage: "number of lines to show before and after a match",
+			Value: 2, //nolint:mnd
+		},
+		&cli.IntFlag{
+			Name:  "limit",
+			Usage: "maximum number of matches",
+			Value: 100, //nolint:mnd
+		},
+		&cli.IntFlag{
+			Name:  "start",
+			Usage: "continue the search at this pipeline number",
+		},
+	},
+}
+
+func pipelineLogsSearch(ctx context.Context, c *cli.Command) error {
+	repoIDOrFullName := c.Args().First()
+	client, err := internal.NewClient(ctx, c)
+	if err != nil {
+		return err
+	}
+	repoID, err := internal.ParseRepo(client, repoIDOrFullName)
+	if err != nil {
+		return fmt.Errorf("invalid repo '%s': %w", repoIDOrFullName, err)
+	}
+
+	query := c.Args().Get(1)
+	if len(query) == 0 {
+		return fmt.Errorf("missing required argument query")
+	}
+
+	results, err := client.LogSearch(repoID, woodpecker.LogSearchOptions{
+		Query:   query,
+		Regex:   c.Bool("regex"),
+		Days:    int(c.Int("days")),
+		Context: int(c.Int("context")),
+		Limit:   int(c.Int("limit")),
+		Start:   c.Int("start"),
+	})
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := template.New("_").Funcs(template.FuncMap{
+		"add": func(a, b int) int { return a + b },
+		"sub": func(a, b int) int { return a - b },
+	}).Parse(c.String("format") + "\n")
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results.Pipelines {
+		if err := tmpl.Execute(os.Stdout, result); err != nil {
+			return err
+		}
+	}
+	if results.Next != 0 {
+		fmt.Fprintf(os.Stderr, "search stopped early, continue with --start %d\n", results.Next)
+	}
+	return nil
+}
+
+// template for pipeline log search results, lines around a match are
+// marked with '-' like grep does.
+var tmplPipelineLogsSearch = "\x1b[33m#{{ .Number }} ({{ .Event }} on {{ .Branch }}, {{ .Status }}):\x1b[0m" + `
+{{- range $w := .Workflows }}
+{{- range $s := .Steps }}
+  {{ $w.Name }} > {{ $s.Name }} (#{{ $s.ID }}):
+{{- range .Matches }}
+{{- $m := . }}
+{{- range $i, $l := .Before }}
+    {{ sub $m.Line (sub (len $m.Before) $i) }}- {{ $l }}
+{{- end }}
+    {{ .Line }}: {{ .Text }}
+{{- range $i, $l := .After }}
+    {{ add $m.Line (add $i 1) }}- {{ $l }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- end }}
+`
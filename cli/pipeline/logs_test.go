@@ -0,0 +1,28 @@
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.woodpecker-ci.org/woodpecker/v2/woodpecker-go/woodpecker"
+)
+
+func TestLogSections(t *testing.T) {
+	sections := logSections([]*woodpecker.LogEntry{
+		{Line: 0, Time: 1, Type: woodpecker.LogEntrySectionStart, Data: []byte("build")},
+		{Line: 1, Time: 2, Data: []byte("go build")},
+		{Line: 2, Time: 3, Type: woodpecker.LogEntrySectionStart, Data: []byte("test")},
+		{Line: 3, Time: 7, Type: woodpecker.LogEntrySectionEnd, Data: []byte("test")},
+		{Line: 4, Time: 9, Type: woodpecker.LogEntrySectionEnd, Data: []byte("build")},
+		{Line: 5, Time: 9, Type: woodpecker.LogEntrySectionStart, Data: []byte("deploy")},
+	})
+
+	assert.Equal(t, []*logSection{
+		{Name: "build", Depth: 0, Line: 0, Started: 1, Finished: 9},
+		{Name: "test", Depth: 1, Line: 2, Started: 3, Finished: 7},
+		{Name: "deploy", Depth: 0, Line: 5, Started: 9, Open: true},
+	}, sections)
+	assert.Equal(t, "8s", sections[0].duration())
+	assert.Equal(t, "not finished", sections[2].duration())
+}
@@ -40,6 +40,7 @@ var Command = &cli.Command{
 		pipelineApproveCmd,
 		pipelineDeclineCmd,
 		pipelineQueueCmd,
+		pipelineExplainCmd,
 		pipelineKillCmd,
 		pipelinePsCmd,
 		pipelineCreateCmd,
@@ -19,10 +19,12 @@ import (
 	"os"
 	"text/template"
 
+	"github.com/docker/go-units"
 	"github.com/urfave/cli/v3"
 
 	"go.woodpecker-ci.org/woodpecker/v2/cli/common"
 	"go.woodpecker-ci.org/woodpecker/v2/cli/internal"
+	"go.woodpecker-ci.org/woodpecker/v2/woodpecker-go/woodpecker"
 )
 
 var repoInfoCmd = &cli.Command{
@@ -48,12 +50,25 @@ func repoInfo(ctx context.Context, c *cli.Command) error {
 	if err != nil {
 		return err
 	}
+	usage, err := client.RepoUsage(repoID)
+	if err != nil {
+		return err
+	}
 
-	tmpl, err := template.New("_").Parse(c.String("format"))
+	tmpl, err := template.New("_").Funcs(template.FuncMap{
+		"bytes": func(size int64) string {
+			return units.BytesSize(float64(size))
+		},
+	}).Parse(c.String("format"))
 	if err != nil {
 		return err
 	}
-	return tmpl.Execute(os.Stdout, repo)
+	return tmpl.Execute(os.Stdout, repoInfoData{Repo: repo, Usage: usage})
+}
+
+type repoInfoData struct {
+	*woodpecker.Repo
+	Usage *woodpecker.StorageUsage
 }
 
 // tTemplate for repo information.
@@ -68,4 +83,10 @@ Gated: {{ .IsGated }}
 Require approval for: {{ .RequireApproval }}
 Clone url: {{ .Clone }}
 Allow pull-requests: {{ .AllowPullRequests }}
+Priority: {{ .Priority }}
+Max concurrent: {{ .MaxConcurrent }}
+Max pending age: {{ .MaxPendingAge }}m
+Retention keep per branch: {{ .RetentionKeepPerBranch }}
+Retention days: {{ .RetentionDays }}
+Log usage: {{ bytes .Usage.LogBytes }}{{ if .Usage.LogQuota }} of {{ bytes .Usage.LogQuota }}{{ if .Usage.Exceeded }} (exceeded){{ else if .Usage.Warning }} (warning){{ end }}{{ end }}
 `
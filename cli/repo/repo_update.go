@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/docker/go-units"
 	"github.com/urfave/cli/v3"
 
 	"go.woodpecker-ci.org/woodpecker/v2/cli/internal"
@@ -63,6 +64,30 @@ var repoUpdateCmd = &cli.Command{
 			Name:  "unsafe",
 			Usage: "validate updating the pipeline-counter is unsafe",
 		},
+		&cli.IntFlag{
+			Name:  "priority",
+			Usage: "repository priority added to the priority of its workflows in the queue (requires admin rights)",
+		},
+		&cli.IntFlag{
+			Name:  "max-concurrent",
+			Usage: "maximum number of concurrently running workflows of the repository (0 for unlimited)",
+		},
+		&cli.DurationFlag{
+			Name:  "max-pending-age",
+			Usage: "maximum time a workflow waits for an agent before it fails, in whole minutes (0 for the server default)",
+		},
+		&cli.IntFlag{
+			Name:  "retention-keep-per-branch",
+			Usage: "number of latest pipelines per branch to keep, older ones are deleted (0 for the server default)",
+		},
+		&cli.IntFlag{
+			Name:  "retention-days",
+			Usage: "delete pipelines older than this number of days (0 for the server default)",
+		},
+		&cli.StringFlag{
+			Name:  "log-quota",
+			Usage: "maximum size of the stored logs, e.g. 10GiB (0 for the server default, requires admin rights)",
+		},
 	},
 }
 
@@ -86,6 +111,11 @@ func repoUpdate(ctx context.Context, c *cli.Command) error {
 		requireApproval = c.String("require-approval")
 		pipelineCounter = int(c.Int("pipeline-counter"))
 		unsafe          = c.Bool("unsafe")
+		priority        = int(c.Int("priority"))
+		maxConcurrent   = int(c.Int("max-concurrent"))
+		maxPendingAge   = c.Duration("max-pending-age")
+		keepPerBranch   = int(c.Int("retention-keep-per-branch"))
+		retentionDays   = c.Int("retention-days")
 	)
 
 	patch := new(woodpecker.RepoPatch)
@@ -136,6 +166,32 @@ func repoUpdate(ctx context.Context, c *cli.Command) error {
 	if c.IsSet("pipeline-counter") && unsafe {
 		patch.PipelineCounter = &pipelineCounter
 	}
+	if c.IsSet("priority") {
+		patch.Priority = &priority
+	}
+	if c.IsSet("max-concurrent") {
+		patch.MaxConcurrent = &maxConcurrent
+	}
+	if c.IsSet("max-pending-age") {
+		if maxPendingAge < 0 || (maxPendingAge > 0 && maxPendingAge < time.Minute) {
+			return fmt.Errorf("invalid max pending age: '%s' must be 0 or at least one minute", maxPendingAge)
+		}
+		v := int64(maxPendingAge / time.Minute)
+		patch.MaxPendingAge = &v
+	}
+	if c.IsSet("retention-keep-per-branch") {
+		patch.RetentionKeepPerBranch = &keepPerBranch
+	}
+	if c.IsSet("retention-days") {
+		patch.RetentionDays = &retentionDays
+	}
+	if c.IsSet("log-quota") {
+		logQuota, err := units.RAMInBytes(c.String("log-quota"))
+		if err != nil {
+			return fmt.Errorf("invalid log quota: %w", err)
+		}
+		patch.LogQuota = &logQuota
+	}
 
 	repo, err := client.RepoPatch(repoID, patch)
 	if err != nil {
@@ -35,6 +35,7 @@ var Command = &cli.Command{
 		secretUpdateCmd,
 		secretInfoCmd,
 		secretListCmd,
+		secretUnusedCmd,
 	},
 }
 
@@ -18,6 +18,7 @@ import (
 	"context"
 	"html/template"
 	"os"
+	"strconv"
 
 	"github.com/urfave/cli/v3"
 
@@ -42,7 +43,7 @@ var secretInfoCmd = &cli.Command{
 			Name:  "name",
 			Usage: "secret name",
 		},
-		common.FormatFlag(tmplSecretList, true),
+		common.FormatFlag(tmplSecretInfo, true),
 	},
 }
 
@@ -80,9 +81,32 @@ func secretInfo(ctx context.Context, c *cli.Command) error {
 		}
 	}
 
+	data := secretInfoData{Secret: secret}
+	if secret.LastUsedRepoID != 0 {
+		data.LastUsedRepo = strconv.FormatInt(secret.LastUsedRepoID, 10)
+		// the repo might be deleted meanwhile
+		if repo, err := client.Repo(secret.LastUsedRepoID); err == nil {
+			data.LastUsedRepo = repo.FullName
+		}
+	}
+
 	tmpl, err := template.New("_").Funcs(secretFuncMap).Parse(format)
 	if err != nil {
 		return err
 	}
-	return tmpl.Execute(os.Stdout, secret)
+	return tmpl.Execute(os.Stdout, data)
 }
+
+type secretInfoData struct {
+	*woodpecker.Secret
+	LastUsedRepo string
+}
+
+// Template for secret information.
+var tmplSecretInfo = tmplSecretList + `Usage count: {{ .UsageCount }}
+{{- if .LastUsedAt }}
+Last used: {{ time .LastUsedAt }} by {{ .LastUsedRepo }}#{{ .LastUsedPipeline }}
+{{- else }}
+Last used: never
+{{- end }}
+`
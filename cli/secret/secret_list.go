@@ -19,6 +19,7 @@ import (
 	"html/template"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/urfave/cli/v3"
 
@@ -51,30 +52,11 @@ func secretList(ctx context.Context, c *cli.Command) error {
 		return err
 	}
 
-	global, orgID, repoID, err := parseTargetArgs(client, c)
+	list, err := listSecrets(client, c)
 	if err != nil {
 		return err
 	}
 
-	var list []*woodpecker.Secret
-	switch {
-	case global:
-		list, err = client.GlobalSecretList()
-		if err != nil {
-			return err
-		}
-	case orgID != -1:
-		list, err = client.OrgSecretList(orgID)
-		if err != nil {
-			return err
-		}
-	default:
-		list, err = client.SecretList(repoID)
-		if err != nil {
-			return err
-		}
-	}
-
 	tmpl, err := template.New("_").Funcs(secretFuncMap).Parse(format)
 	if err != nil {
 		return err
@@ -87,6 +69,23 @@ func secretList(ctx context.Context, c *cli.Command) error {
 	return nil
 }
 
+// listSecrets lists the secrets of the global, organization or repository target of the command.
+func listSecrets(client woodpecker.Client, c *cli.Command) ([]*woodpecker.Secret, error) {
+	global, orgID, repoID, err := parseTargetArgs(client, c)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case global:
+		return client.GlobalSecretList()
+	case orgID != -1:
+		return client.OrgSecretList(orgID)
+	default:
+		return client.SecretList(repoID)
+	}
+}
+
 // Template for secret list items.
 var tmplSecretList = "\x1b[33m{{ .Name }} \x1b[0m" + `
 Events: {{ list .Events }}
@@ -101,4 +100,7 @@ var secretFuncMap = template.FuncMap{
 	"list": func(s []string) string {
 		return strings.Join(s, ", ")
 	},
+	"time": func(unix int64) string {
+		return time.Unix(unix, 0).Format(time.RFC3339)
+	},
 }
@@ -0,0 +1,95 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package secret
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"os"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"go.woodpecker-ci.org/woodpecker/v2/cli/common"
+	"go.woodpecker-ci.org/woodpecker/v2/cli/internal"
+)
+
+var secretUnusedCmd = &cli.Command{
+	Name:      "unused",
+	Usage:     "list secrets not used by any pipeline for a number of days",
+	ArgsUsage: "[repo-id|repo-full-name]",
+	Action:    secretUnused,
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "global",
+			Usage: "global secret",
+		},
+		common.OrgFlag,
+		common.RepoFlag,
+		&cli.IntFlag{
+			Name:  "days",
+			Usage: "list secrets not used within this number of days",
+			Value: 30,
+		},
+		common.FormatFlag(tmplSecretUnused, true),
+	},
+}
+
+func secretUnused(ctx context.Context, c *cli.Command) error {
+	format := c.String("format") + "\n"
+
+	days := c.Int("days")
+	if days < 1 {
+		return fmt.Errorf("days must be at least 1")
+	}
+
+	client, err := internal.NewClient(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	list, err := listSecrets(client, c)
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := template.New("_").Funcs(secretFuncMap).Parse(format)
+	if err != nil {
+		return err
+	}
+	cutoff := time.Now().AddDate(0, 0, -int(days)).Unix()
+	for _, secret := range list {
+		if secret.LastUsedAt >= cutoff {
+			continue
+		}
+		if err := tmpl.Execute(os.Stdout, secret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Template for unused secrets.
+var tmplSecretUnused = "\x1b[33m{{ .Name }} \x1b[0m" + `
+{{- if .LastUsedAt }}
+Last used: {{ time .LastUsedAt }}
+{{- else }}
+Last used: never
+{{- end }}
+`
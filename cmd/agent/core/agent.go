@@ -26,6 +26,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/docker/go-units"
 	"github.com/rs/zerolog/log"
 	"github.com/urfave/cli/v3"
 	"golang.org/x/sync/errgroup"
@@ -41,6 +42,7 @@ import (
 	agent_rpc "go.woodpecker-ci.org/woodpecker/v2/agent/rpc"
 	"go.woodpecker-ci.org/woodpecker/v2/pipeline/backend"
 	"go.woodpecker-ci.org/woodpecker/v2/pipeline/backend/types"
+	pipeline_log "go.woodpecker-ci.org/woodpecker/v2/pipeline/log"
 	"go.woodpecker-ci.org/woodpecker/v2/pipeline/rpc"
 	"go.woodpecker-ci.org/woodpecker/v2/shared/logger"
 	"go.woodpecker-ci.org/woodpecker/v2/shared/utils"
@@ -196,8 +198,24 @@ func run(ctx context.Context, c *cli.Command, backends []types.Backend) error {
 	}
 	log.Debug().Msgf("loaded %s backend engine", backendEngine.Name())
 
+	resources, err := agentResources(c.Float("resources-cpu"), c.String("resources-memory"), c.String("resources-disk"))
+	if err != nil {
+		return err
+	}
+
+	logLimit, err := stepLogLimit(c.String("log-step-max-size"), int(c.Int("log-step-max-lines")))
+	if err != nil {
+		return err
+	}
+
 	maxWorkflows := int(c.Int("max-workflows"))
-	agentConfig.AgentID, err = client.RegisterAgent(grpcCtx, engInfo.Platform, backendEngine.Name(), version.String(), maxWorkflows) //nolint:contextcheck
+	agentConfig.AgentID, err = client.RegisterAgent(grpcCtx, rpc.AgentInfo{ //nolint:contextcheck
+		Platform:  engInfo.Platform,
+		Backend:   backendEngine.Name(),
+		Version:   version.String(),
+		Capacity:  maxWorkflows,
+		Resources: resources,
+	})
 	if err != nil {
 		return err
 	}
@@ -265,7 +283,7 @@ func run(ctx context.Context, c *cli.Command, backends []types.Backend) error {
 	for i := 0; i < maxWorkflows; i++ {
 		i := i
 		serviceWaitingGroup.Go(func() error {
-			runner := agent.NewRunner(client, filter, hostname, counter, &backendEngine)
+			runner := agent.NewRunner(client, filter, hostname, counter, &backendEngine, logLimit)
 			log.Debug().Msgf("created new runner %d", i)
 
 			for {
@@ -329,3 +347,37 @@ func stringSliceAddToMap(sl []string, m map[string]string) error {
 	}
 	return nil
 }
+
+// agentResources parses the resources the agent can allocate to workflows,
+// memory and disk can be given in human readable units like "16GiB".
+func agentResources(cpu float64, memory, disk string) (rpc.Resources, error) {
+	resources := rpc.Resources{
+		CPU: int64(cpu * 1000), //nolint:mnd
+	}
+
+	var err error
+	if memory != "" {
+		if resources.Memory, err = units.RAMInBytes(memory); err != nil {
+			return resources, fmt.Errorf("invalid memory resources '%s': %w", memory, err)
+		}
+	}
+	if disk != "" {
+		if resources.Disk, err = units.RAMInBytes(disk); err != nil {
+			return resources, fmt.Errorf("invalid disk resources '%s': %w", disk, err)
+		}
+	}
+	return resources, nil
+}
+
+// stepLogLimit parses the limit for the log output of a single step,
+// the size can be given in human readable units like "10MiB".
+func stepLogLimit(size string, lines int) (pipeline_log.Limit, error) {
+	limit := pipeline_log.Limit{Lines: lines}
+	if size != "" {
+		var err error
+		if limit.Bytes, err = units.RAMInBytes(size); err != nil {
+			return limit, fmt.Errorf("invalid step log size '%s': %w", size, err)
+		}
+	}
+	return limit, nil
+}
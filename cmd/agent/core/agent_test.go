@@ -73,3 +73,18 @@ func TestStringSliceAddToMap(t *testing.T) {
 		})
 	}
 }
+
+func TestAgentResources(t *testing.T) {
+	resources, err := agentResources(1.5, "2GiB", "10g")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1500, resources.CPU)
+	assert.EqualValues(t, 2*1024*1024*1024, resources.Memory)
+	assert.EqualValues(t, 10*1024*1024*1024, resources.Disk)
+
+	resources, err = agentResources(0, "", "")
+	assert.NoError(t, err)
+	assert.Zero(t, resources)
+
+	_, err = agentResources(0, "lots", "")
+	assert.Error(t, err)
+}
@@ -69,6 +69,31 @@ var flags = []cli.Flag{
 		Usage:   "agent parallel workflows",
 		Value:   1,
 	},
+	&cli.FloatFlag{
+		Sources: cli.EnvVars("WOODPECKER_RESOURCES_CPU"),
+		Name:    "resources-cpu",
+		Usage:   "CPU cores the agent can allocate to workflows (0 for unlimited)",
+	},
+	&cli.StringFlag{
+		Sources: cli.EnvVars("WOODPECKER_RESOURCES_MEMORY"),
+		Name:    "resources-memory",
+		Usage:   "memory the agent can allocate to workflows, e.g. 16GiB (empty for unlimited)",
+	},
+	&cli.StringFlag{
+		Sources: cli.EnvVars("WOODPECKER_RESOURCES_DISK"),
+		Name:    "resources-disk",
+		Usage:   "disk space the agent can allocate to workflows, e.g. 100GiB (empty for unlimited)",
+	},
+	&cli.StringFlag{
+		Sources: cli.EnvVars("WOODPECKER_LOG_STEP_MAX_SIZE"),
+		Name:    "log-step-max-size",
+		Usage:   "maximum size of the logs of a single step, e.g. 10MiB, output exceeding it is dropped (empty for unlimited)",
+	},
+	&cli.IntFlag{
+		Sources: cli.EnvVars("WOODPECKER_LOG_STEP_MAX_LINES"),
+		Name:    "log-step-max-lines",
+		Usage:   "maximum number of log lines of a single step, output exceeding it is dropped (0 for unlimited)",
+	},
 	&cli.BoolFlag{
 		Sources: cli.EnvVars("WOODPECKER_HEALTHCHECK"),
 		Name:    "healthcheck",
@@ -21,6 +21,7 @@ import (
 
 	"github.com/urfave/cli/v3"
 
+	"go.woodpecker-ci.org/woodpecker/v2/server/queue"
 	"go.woodpecker-ci.org/woodpecker/v2/shared/constant"
 	"go.woodpecker-ci.org/woodpecker/v2/shared/logger"
 )
@@ -164,6 +165,59 @@ var flags = append([]cli.Flag{
 		Usage:   "The maximum time in minutes you can set in the repo settings before a pipeline gets killed",
 		Value:   120,
 	},
+	&cli.DurationFlag{
+		Sources: cli.EnvVars("WOODPECKER_QUEUE_PRIORITY_AGING"),
+		Name:    "queue-priority-aging",
+		Usage:   "time a pending workflow has to wait to gain one priority level, 0 disables aging",
+		Value:   queue.DefaultPriorityAging,
+	},
+	&cli.BoolFlag{
+		Sources: cli.EnvVars("WOODPECKER_QUEUE_FAIR_SHARE"),
+		Name:    "queue-fair-share",
+		Usage:   "balance running workflows across organizations and repositories, weighted by the organization weight",
+	},
+	&cli.BoolFlag{
+		Sources: cli.EnvVars("WOODPECKER_QUEUE_SHARED"),
+		Name:    "queue-shared",
+		Usage:   "share the queue with other servers through the database, so multiple servers can run behind a load balancer",
+	},
+	&cli.StringFlag{
+		Sources: cli.EnvVars("WOODPECKER_PUBSUB"),
+		Name:    "pubsub",
+		Usage:   "pubsub backend used to share events and logs between servers (memory, postgres, redis)",
+		Value:   "memory",
+	},
+	&cli.StringFlag{
+		Sources: cli.EnvVars("WOODPECKER_PUBSUB_CHANNEL"),
+		Name:    "pubsub-channel",
+		Usage:   "channel name used by the pubsub backend",
+		Value:   "woodpecker",
+	},
+	&cli.StringFlag{
+		Sources: cli.NewValueSourceChain(
+			cli.File(os.Getenv("WOODPECKER_PUBSUB_POSTGRES_DATASOURCE_FILE")),
+			cli.EnvVar("WOODPECKER_PUBSUB_POSTGRES_DATASOURCE")),
+		Name:  "pubsub-postgres-datasource",
+		Usage: "postgres connection string of the pubsub backend, defaults to the database datasource if the postgres driver is used",
+	},
+	&cli.StringFlag{
+		Sources: cli.EnvVars("WOODPECKER_PUBSUB_REDIS_ADDR"),
+		Name:    "pubsub-redis-addr",
+		Usage:   "address (host:port or redis:// url) of the redis server used by the pubsub backend",
+		Value:   "localhost:6379",
+	},
+	&cli.StringFlag{
+		Sources: cli.NewValueSourceChain(
+			cli.File(os.Getenv("WOODPECKER_PUBSUB_REDIS_PASSWORD_FILE")),
+			cli.EnvVar("WOODPECKER_PUBSUB_REDIS_PASSWORD")),
+		Name:  "pubsub-redis-password",
+		Usage: "password of the redis server used by the pubsub backend",
+	},
+	&cli.BoolFlag{
+		Sources: cli.EnvVars("WOODPECKER_PUBSUB_REDIS_TLS"),
+		Name:    "pubsub-redis-tls",
+		Usage:   "connect to the redis server used by the pubsub backend with TLS",
+	},
 	&cli.DurationFlag{
 		Sources: cli.EnvVars("WOODPECKER_SESSION_EXPIRES"),
 		Name:    "session-expires",
@@ -208,6 +262,11 @@ var flags = append([]cli.Flag{
 		Name:    "config-service-endpoint",
 		Usage:   "url used for calling configuration service endpoint",
 	},
+	&cli.StringFlag{
+		Sources: cli.EnvVars("WOODPECKER_SECRET_SERVICE_ENDPOINT"),
+		Name:    "secret-service-endpoint",
+		Usage:   "url used for calling secret service endpoint",
+	},
 	&cli.StringFlag{
 		Sources: cli.EnvVars("WOODPECKER_DATABASE_DRIVER"),
 		Name:    "driver",
@@ -259,7 +318,7 @@ var flags = append([]cli.Flag{
 	&cli.StringFlag{
 		Sources: cli.EnvVars("WOODPECKER_LOG_STORE"),
 		Name:    "log-store",
-		Usage:   "log store to use ('database' or 'file')",
+		Usage:   "log store to use ('database', 'file' or 's3')",
 		Value:   "database",
 	},
 	&cli.StringFlag{
@@ -267,6 +326,85 @@ var flags = append([]cli.Flag{
 		Name:    "log-store-file-path",
 		Usage:   "directory used for file based log storage",
 	},
+	&cli.BoolFlag{
+		Sources: cli.EnvVars("WOODPECKER_LOG_SEARCH_INDEX"),
+		Name:    "log-search-index",
+		Usage:   "keep a search index of the logs of finished steps to speed up log searches (database and file log store)",
+	},
+	&cli.StringFlag{
+		Sources: cli.EnvVars("WOODPECKER_LOG_STEP_MAX_SIZE"),
+		Name:    "log-step-max-size",
+		Usage:   "maximum size of the stored logs of a single step, e.g. 10MiB, output exceeding it is dropped (empty for unlimited)",
+	},
+	&cli.IntFlag{
+		Sources: cli.EnvVars("WOODPECKER_LOG_STEP_MAX_LINES"),
+		Name:    "log-step-max-lines",
+		Usage:   "maximum number of stored log lines of a single step, output exceeding it is dropped (0 for unlimited)",
+	},
+	&cli.StringFlag{
+		Sources: cli.EnvVars("WOODPECKER_LOG_QUOTA_REPO"),
+		Name:    "log-quota-repo",
+		Usage:   "maximum size of the stored logs of a repo, e.g. 10GiB, unless the repo has its own quota (empty for unlimited)",
+	},
+	&cli.StringFlag{
+		Sources: cli.EnvVars("WOODPECKER_LOG_QUOTA_ORG"),
+		Name:    "log-quota-org",
+		Usage:   "maximum size of the stored logs of all repos of an org, e.g. 100GiB, unless the org has its own quota (empty for unlimited)",
+	},
+	&cli.IntFlag{
+		Sources: cli.EnvVars("WOODPECKER_LOG_QUOTA_WARN_PERCENT"),
+		Name:    "log-quota-warn-percent",
+		Usage:   "percentage of a log quota from which new pipelines show a warning",
+		Value:   80,
+	},
+	&cli.StringFlag{
+		Sources: cli.EnvVars("WOODPECKER_LOG_QUOTA_ACTION"),
+		Name:    "log-quota-action",
+		Usage:   "what happens when a log quota is exceeded: 'block' new pipelines or 'purge' the oldest logs",
+		Value:   "block",
+	},
+	&cli.StringFlag{
+		Sources: cli.EnvVars("WOODPECKER_LOG_STORE_S3_ENDPOINT"),
+		Name:    "log-store-s3-endpoint",
+		Usage:   "endpoint of the S3 compatible storage used for log storage",
+		Value:   "https://s3.amazonaws.com",
+	},
+	&cli.StringFlag{
+		Sources: cli.EnvVars("WOODPECKER_LOG_STORE_S3_BUCKET"),
+		Name:    "log-store-s3-bucket",
+		Usage:   "bucket used for S3 based log storage",
+	},
+	&cli.StringFlag{
+		Sources: cli.EnvVars("WOODPECKER_LOG_STORE_S3_PREFIX"),
+		Name:    "log-store-s3-prefix",
+		Usage:   "prefix of the objects used for S3 based log storage",
+	},
+	&cli.StringFlag{
+		Sources: cli.EnvVars("WOODPECKER_LOG_STORE_S3_REGION"),
+		Name:    "log-store-s3-region",
+		Usage:   "region of the bucket used for S3 based log storage",
+		Value:   "us-east-1",
+	},
+	&cli.StringFlag{
+		Sources: cli.NewValueSourceChain(
+			cli.File(os.Getenv("WOODPECKER_LOG_STORE_S3_ACCESS_KEY_FILE")),
+			cli.EnvVar("WOODPECKER_LOG_STORE_S3_ACCESS_KEY")),
+		Name:  "log-store-s3-access-key",
+		Usage: "access key used for S3 based log storage",
+	},
+	&cli.StringFlag{
+		Sources: cli.NewValueSourceChain(
+			cli.File(os.Getenv("WOODPECKER_LOG_STORE_S3_SECRET_KEY_FILE")),
+			cli.EnvVar("WOODPECKER_LOG_STORE_S3_SECRET_KEY")),
+		Name:  "log-store-s3-secret-key",
+		Usage: "secret key used for S3 based log storage",
+	},
+	&cli.BoolFlag{
+		Sources: cli.EnvVars("WOODPECKER_LOG_STORE_S3_PATH_STYLE"),
+		Name:    "log-store-s3-path-style",
+		Usage:   "address the bucket in the path instead of the host name, required by most S3 compatible storages like MinIO",
+		Value:   true,
+	},
 	//
 	// backend options for pipeline compiler
 	//
@@ -520,6 +658,28 @@ var flags = append([]cli.Flag{
 		Name:  "secrets-encryption-aes-key",
 		Usage: "secrets encryption AES key",
 	},
+	&cli.StringSliceFlag{
+		Sources: cli.NewValueSourceChain(
+			cli.File(os.Getenv("WOODPECKER_SECRETS_ENCRYPTION_AES_OLD_KEYS_FILE")),
+			cli.EnvVar("WOODPECKER_SECRETS_ENCRYPTION_AES_OLD_KEYS")),
+		Name:  "secrets-encryption-aes-old-keys",
+		Usage: "previous secrets encryption AES keys, only used to decrypt secrets not yet re-encrypted with the current key",
+	},
+	&cli.StringFlag{
+		Sources: cli.EnvVars("WOODPECKER_SECRETS_ENCRYPTION_TINK_KEYSET_FILE"),
+		Name:    "secrets-encryption-tink-keyset-file",
+		Usage:   "path to a Tink AEAD keyset in JSON format used to encrypt secrets",
+	},
+	&cli.StringFlag{
+		Sources: cli.EnvVars("WOODPECKER_SECRETS_ENCRYPTION_AGE_IDENTITY_FILE"),
+		Name:    "secrets-encryption-age-identity-file",
+		Usage:   "path to an age identity file used to encrypt secrets, the first identity encrypts",
+	},
+	&cli.StringFlag{
+		Sources: cli.EnvVars("WOODPECKER_SECRETS_ENCRYPTION_KMS_ENDPOINT"),
+		Name:    "secrets-encryption-kms-endpoint",
+		Usage:   "url of a key management service extension used to encrypt secrets",
+	},
 	//
 	// maintenance
 	//
@@ -535,6 +695,23 @@ var flags = append([]cli.Flag{
 		Usage:   "pipeline logs created more than OLDER_THAN ago is subject to deletion",
 		Value:   "",
 	},
+	&cli.StringFlag{
+		Sources: cli.EnvVars("WOODPECKER_MAINTENANCE_CLEANUP_PIPELINES_OLDER_THAN"),
+		Name:    "maintenance-cleanup-pipelines-older-than",
+		Usage:   "pipeline created more than OLDER_THAN ago is subject to deletion, unless the repo sets its own retention",
+		Value:   "",
+	},
+	&cli.IntFlag{
+		Sources: cli.EnvVars("WOODPECKER_MAINTENANCE_CLEANUP_PIPELINES_KEEP_PER_BRANCH"),
+		Name:    "maintenance-cleanup-pipelines-keep-per-branch",
+		Usage:   "number of most recent pipelines kept per branch, unless the repo sets its own retention",
+	},
+	&cli.StringFlag{
+		Sources: cli.EnvVars("WOODPECKER_MAINTENANCE_EXPIRE_PENDING_WORKFLOWS_OLDER_THAN"),
+		Name:    "maintenance-expire-pending-workflows-older-than",
+		Usage:   "pending workflow no agent picked up for more than OLDER_THAN fails, unless the repo sets its own maximum pending age",
+		Value:   "",
+	},
 }, logger.GlobalLoggerFlags...)
 
 // If woodpecker is running inside a container the default value for
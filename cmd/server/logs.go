@@ -0,0 +1,173 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v3"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	logService "go.woodpecker-ci.org/woodpecker/v2/server/services/log"
+	"go.woodpecker-ci.org/woodpecker/v2/shared/logger"
+)
+
+var logStores = []string{"database", "file", "s3"}
+
+var logsCmd = &cli.Command{
+	Name:  "logs",
+	Usage: "manage the stored pipeline logs",
+	Commands: []*cli.Command{
+		{
+			Name:  "migrate",
+			Usage: "copy the logs of all steps from one log store to another, the server should be stopped",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "from",
+					Usage:    "log store to copy the logs from ('database', 'file' or 's3')",
+					Required: true,
+				},
+				&cli.StringFlag{
+					Name:     "to",
+					Usage:    "log store to copy the logs to ('database', 'file' or 's3')",
+					Required: true,
+				},
+				&cli.IntFlag{
+					Name:  "after-step",
+					Usage: "resume the migration after the step with this id",
+				},
+				&cli.BoolFlag{
+					Name:  "delete-source",
+					Usage: "delete the logs from the source log store once they are copied",
+				},
+			},
+			Action: migrateLogs,
+		},
+		{
+			Name:  "backfill-size",
+			Usage: "count the log size of finished steps stored before log sizes were tracked",
+			Flags: []cli.Flag{
+				&cli.IntFlag{
+					Name:  "after-step",
+					Usage: "resume the backfill after the step with this id",
+				},
+			},
+			Action: backfillLogSizes,
+		},
+	},
+}
+
+func migrateLogs(ctx context.Context, c *cli.Command) error {
+	if err := logger.SetupGlobalLogger(ctx, c, true); err != nil {
+		return err
+	}
+
+	fromName, toName := c.String("from"), c.String("to")
+	for _, name := range []string{fromName, toName} {
+		if !slices.Contains(logStores, name) {
+			return fmt.Errorf("unknown log store '%s'", name)
+		}
+	}
+	if fromName == toName {
+		return fmt.Errorf("source and destination log store must differ")
+	}
+
+	_store, err := setupStore(ctx, c)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := _store.Close(); err != nil {
+			log.Error().Err(err).Msg("could not close store")
+		}
+	}()
+
+	from, err := setupLogStoreByName(ctx, c, _store, fromName)
+	if err != nil {
+		return fmt.Errorf("could not setup log store '%s': %w", fromName, err)
+	}
+	to, err := setupLogStoreByName(ctx, c, _store, toName)
+	if err != nil {
+		return fmt.Errorf("could not setup log store '%s': %w", toName, err)
+	}
+
+	steps, entries := 0, 0
+	lastStepID := c.Int("after-step")
+	err = logService.Migrate(ctx, _store, from, to, logService.MigrateOptions{
+		AfterStepID:  lastStepID,
+		DeleteSource: c.Bool("delete-source"),
+		Progress: func(step *model.Step, n int) {
+			lastStepID = step.ID
+			if n > 0 {
+				steps++
+				entries += n
+				log.Debug().Msgf("migrated %d log entries of step %d", n, step.ID)
+			}
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("log migration stopped, resume it with --after-step %d: %w", lastStepID, err)
+	}
+
+	log.Info().Msgf("migrated %d log entries of %d steps from %s to %s", entries, steps, fromName, toName)
+	return nil
+}
+
+func backfillLogSizes(ctx context.Context, c *cli.Command) error {
+	if err := logger.SetupGlobalLogger(ctx, c, true); err != nil {
+		return err
+	}
+
+	_store, err := setupStore(ctx, c)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := _store.Close(); err != nil {
+			log.Error().Err(err).Msg("could not close store")
+		}
+	}()
+
+	logStore, err := setupLogStore(ctx, c, _store)
+	if err != nil {
+		return fmt.Errorf("could not setup log store: %w", err)
+	}
+
+	steps, size := 0, int64(0)
+	lastStepID := c.Int("after-step")
+	err = logService.BackfillSizes(ctx, _store, logStore, logService.BackfillOptions{
+		AfterStepID: lastStepID,
+		Progress: func(step *model.Step, n int64) {
+			lastStepID = step.ID
+			if n > 0 {
+				steps++
+				size += n
+				log.Debug().Msgf("counted %d bytes of logs of step %d", n, step.ID)
+			}
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("log size backfill stopped, resume it with --after-step %d: %w", lastStepID, err)
+	}
+
+	log.Info().Msgf("counted %d bytes of logs of %d steps", size, steps)
+	return nil
+}
@@ -43,6 +43,8 @@ func main() {
 			Usage:  "ping the server",
 			Action: pinger,
 		},
+		logsCmd,
+		secretsCmd,
 	}
 	app.Flags = flags
 
@@ -38,6 +38,11 @@ func startMetricsCollector(ctx context.Context, _store store.Store) {
 		Name:      "waiting_steps",
 		Help:      "Total number of pipeline waiting on deps.",
 	})
+	limitedSteps := prometheus_auto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "woodpecker",
+		Name:      "limited_steps",
+		Help:      "Total number of pipeline steps waiting on concurrency limits.",
+	})
 	runningSteps := prometheus_auto.NewGauge(prometheus.GaugeOpts{
 		Namespace: "woodpecker",
 		Name:      "running_steps",
@@ -71,6 +76,7 @@ func startMetricsCollector(ctx context.Context, _store store.Store) {
 			stats := server.Config.Services.Queue.Info(ctx)
 			pendingSteps.Set(float64(stats.Stats.Pending))
 			waitingSteps.Set(float64(stats.Stats.WaitingOnDeps))
+			limitedSteps.Set(float64(stats.Stats.WaitingOnLimit))
 			runningSteps.Set(float64(stats.Stats.Running))
 			workers.Set(float64(stats.Stats.Workers))
 
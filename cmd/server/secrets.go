@@ -0,0 +1,89 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v3"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/services"
+	"go.woodpecker-ci.org/woodpecker/v2/server/services/registry"
+	"go.woodpecker-ci.org/woodpecker/v2/server/services/secret"
+	"go.woodpecker-ci.org/woodpecker/v2/server/store/encrypted"
+	"go.woodpecker-ci.org/woodpecker/v2/shared/logger"
+)
+
+var secretsCmd = &cli.Command{
+	Name:  "secrets",
+	Usage: "manage the stored secrets",
+	Commands: []*cli.Command{
+		{
+			Name:   "reencrypt",
+			Usage:  "encrypt all secrets, registry passwords and user and forge credentials with the current encryption key, the server can keep running if it knows the current and the old keys",
+			Action: reencryptSecrets,
+		},
+	},
+}
+
+func reencryptSecrets(ctx context.Context, c *cli.Command) error {
+	if err := logger.SetupGlobalLogger(ctx, c, true); err != nil {
+		return err
+	}
+
+	_store, err := setupStore(ctx, c)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := _store.Close(); err != nil {
+			log.Error().Err(err).Msg("could not close store")
+		}
+	}()
+
+	encSvc, err := services.SetupSecretsEncryption(c, _store)
+	if err != nil {
+		return err
+	}
+	if encSvc == nil {
+		return errors.New("secrets encryption is not enabled, configure an encryption key provider")
+	}
+
+	n, err := secret.NewMigration(secret.NewEncrypted(secret.NewDB(_store), encSvc), _store).ReencryptAll()
+	if err != nil {
+		return err
+	}
+
+	log.Info().Msgf("re-encrypted %d secrets", n)
+
+	n, err = registry.NewMigration(registry.NewEncrypted(registry.NewDB(_store), encSvc), _store).ReencryptAll()
+	if err != nil {
+		return err
+	}
+	log.Info().Msgf("re-encrypted %d registries", n)
+
+	n, err = encrypted.New(_store, encSvc).ReencryptAll()
+	if err != nil {
+		return err
+	}
+	log.Info().Msgf("re-encrypted the credentials of %d users and forges", n)
+
+	return nil
+}
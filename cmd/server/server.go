@@ -114,7 +114,7 @@ func run(ctx context.Context, c *cli.Command) error {
 		}
 	}()
 
-	err = setupEvilGlobals(ctx, c, _store)
+	_store, err = setupEvilGlobals(ctx, c, _store)
 	if err != nil {
 		return fmt.Errorf("can't setup globals: %w", err)
 	}
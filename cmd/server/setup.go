@@ -17,6 +17,7 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/base32"
 	"errors"
 	"fmt"
@@ -25,7 +26,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/docker/go-units"
 	"github.com/gorilla/securecookie"
+	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog/log"
 	"github.com/urfave/cli/v3"
 
@@ -34,14 +37,18 @@ import (
 	"go.woodpecker-ci.org/woodpecker/v2/server/forge/setup"
 	"go.woodpecker-ci.org/woodpecker/v2/server/logging"
 	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/pipeline"
 	"go.woodpecker-ci.org/woodpecker/v2/server/pubsub"
 	"go.woodpecker-ci.org/woodpecker/v2/server/queue"
 	"go.woodpecker-ci.org/woodpecker/v2/server/services"
+	"go.woodpecker-ci.org/woodpecker/v2/server/services/encryption"
 	logService "go.woodpecker-ci.org/woodpecker/v2/server/services/log"
 	"go.woodpecker-ci.org/woodpecker/v2/server/services/log/file"
+	"go.woodpecker-ci.org/woodpecker/v2/server/services/log/s3"
 	"go.woodpecker-ci.org/woodpecker/v2/server/services/permissions"
 	"go.woodpecker-ci.org/woodpecker/v2/server/store"
 	"go.woodpecker-ci.org/woodpecker/v2/server/store/datastore"
+	"go.woodpecker-ci.org/woodpecker/v2/server/store/encrypted"
 	"go.woodpecker-ci.org/woodpecker/v2/server/store/types"
 	"go.woodpecker-ci.org/woodpecker/v2/shared/constant"
 )
@@ -99,6 +106,20 @@ func setupStore(ctx context.Context, c *cli.Command) (store.Store, error) {
 	return store, nil
 }
 
+// setupStoreEncryption wraps the store to keep the user and forge credentials
+// encrypted if an encryption key is configured.
+func setupStoreEncryption(s store.Store, encSvc encryption.Service) (store.Store, error) {
+	if encSvc == nil {
+		return s, nil
+	}
+
+	encryptedStore := encrypted.New(s, encSvc)
+	if err := encryptedStore.EncryptAll(); err != nil {
+		return nil, err
+	}
+	return encryptedStore, nil
+}
+
 func checkSqliteFileExist(path string) error {
 	_, err := os.Stat(path)
 	if err != nil && os.IsNotExist(err) {
@@ -108,18 +129,87 @@ func checkSqliteFileExist(path string) error {
 	return err
 }
 
-func setupQueue(ctx context.Context, s store.Store) queue.Queue {
-	return queue.WithTaskStore(ctx, queue.New(ctx), s)
+func setupQueue(ctx context.Context, c *cli.Command, s store.Store) queue.Queue {
+	opts := []queue.Option{
+		queue.WithPriorityAging(c.Duration("queue-priority-aging")),
+		queue.WithConcurrencyLimits(queue.ConcurrencyLimitsFromStore(s)),
+		queue.WithAgentResources(queue.AgentResourcesFromStore(s)),
+	}
+	if c.Bool("queue-fair-share") {
+		opts = append(opts, queue.WithFairShare(queue.OrgWeightsFromStore(s)))
+	}
+	if c.Bool("queue-shared") {
+		return queue.NewShared(ctx, s, opts...)
+	}
+	return queue.WithTaskStore(ctx, queue.New(ctx, opts...), s)
+}
+
+func setupPubsub(ctx context.Context, c *cli.Command, channel string) (pubsub.Publisher, error) {
+	switch c.String("pubsub") {
+	case "memory", "":
+		return pubsub.New(), nil
+	case "postgres":
+		datasource := c.String("pubsub-postgres-datasource")
+		if datasource == "" && c.String("driver") == "postgres" {
+			datasource = c.String("datasource")
+		}
+		if datasource == "" {
+			return nil, errors.New("postgres pubsub requires a datasource")
+		}
+		return pubsub.NewPostgres(ctx, datasource, channel)
+	case "redis":
+		options, err := setupRedisOptions(c)
+		if err != nil {
+			return nil, err
+		}
+		return pubsub.NewRedis(ctx, options, channel)
+	default:
+		return nil, fmt.Errorf("unknown pubsub backend '%s'", c.String("pubsub"))
+	}
+}
+
+// setupRedisOptions returns the options of the redis client of the pubsub
+// backend. The address is either host:port or a redis:// or rediss:// URL.
+func setupRedisOptions(c *cli.Command) (*redis.Options, error) {
+	addr := c.String("pubsub-redis-addr")
+	options := &redis.Options{Addr: addr}
+	if strings.Contains(addr, "://") {
+		var err error
+		if options, err = redis.ParseURL(addr); err != nil {
+			return nil, fmt.Errorf("invalid redis url: %w", err)
+		}
+	}
+	if password := c.String("pubsub-redis-password"); password != "" {
+		options.Password = password
+	}
+	if c.Bool("pubsub-redis-tls") && options.TLSConfig == nil {
+		options.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	return options, nil
 }
 
 func setupMembershipService(_ context.Context, _store store.Store) cache.MembershipService {
 	return cache.NewMembershipService(_store)
 }
 
-func setupLogStore(c *cli.Command, s store.Store) (logService.Service, error) {
-	switch c.String("log-store") {
+func setupLogStore(ctx context.Context, c *cli.Command, s store.Store) (logService.Service, error) {
+	return setupLogStoreByName(ctx, c, s, c.String("log-store"))
+}
+
+func setupLogStoreByName(ctx context.Context, c *cli.Command, s store.Store, name string) (logService.Service, error) {
+	switch name {
 	case "file":
 		return file.NewLogStore(c.String("log-store-file-path"))
+	case "s3":
+		return s3.NewLogStore(ctx, s3.Config{
+			Endpoint:  c.String("log-store-s3-endpoint"),
+			Bucket:    c.String("log-store-s3-bucket"),
+			Prefix:    c.String("log-store-s3-prefix"),
+			Region:    c.String("log-store-s3-region"),
+			AccessKey: c.String("log-store-s3-access-key"),
+			SecretKey: c.String("log-store-s3-secret-key"),
+			PathStyle: c.Bool("log-store-s3-path-style"),
+		})
 	default:
 		return s, nil
 	}
@@ -148,21 +238,46 @@ func setupJWTSecret(_store store.Store) (string, error) {
 	return jwtSecret, nil
 }
 
-func setupEvilGlobals(ctx context.Context, c *cli.Command, s store.Store) error {
+// setupEvilGlobals sets up the global services and returns the store wrapped
+// to encrypt the user and forge credentials if an encryption key is configured.
+func setupEvilGlobals(ctx context.Context, c *cli.Command, s store.Store) (store.Store, error) {
+	// the encryption is set up once, so key providers like KMS share their keys
+	encSvc, err := services.SetupSecretsEncryption(c, s)
+	if err != nil {
+		return nil, fmt.Errorf("could not setup secrets encryption: %w", err)
+	}
+	if s, err = setupStoreEncryption(s, encSvc); err != nil {
+		return nil, fmt.Errorf("could not setup store encryption: %w", err)
+	}
+
 	// services
-	server.Config.Services.Queue = setupQueue(ctx, s)
-	server.Config.Services.Logs = logging.New()
-	server.Config.Services.Pubsub = pubsub.New()
+	server.Config.Services.Queue = setupQueue(ctx, c, s)
+	channel := c.String("pubsub-channel")
+	publisher, err := setupPubsub(ctx, c, channel)
+	if err != nil {
+		return nil, fmt.Errorf("could not setup pubsub: %w", err)
+	}
+	server.Config.Services.Pubsub = publisher
+	if backend := c.String("pubsub"); backend == "memory" || backend == "" {
+		// without other servers logs are streamed directly, keeping their order
+		server.Config.Services.Logs = logging.New()
+	} else {
+		logs, err := setupPubsub(ctx, c, channel+"_logs")
+		if err != nil {
+			return nil, fmt.Errorf("could not setup pubsub for logs: %w", err)
+		}
+		server.Config.Services.Logs = logging.NewPublished(ctx, logs)
+	}
 	server.Config.Services.Membership = setupMembershipService(ctx, s)
-	serviceManager, err := services.NewManager(c, s, setup.Forge)
+	serviceManager, err := services.NewManager(c, s, encSvc, setup.Forge)
 	if err != nil {
-		return fmt.Errorf("could not setup service manager: %w", err)
+		return nil, fmt.Errorf("could not setup service manager: %w", err)
 	}
 	server.Config.Services.Manager = serviceManager
 
-	server.Config.Services.LogStore, err = setupLogStore(c, s)
+	server.Config.Services.LogStore, err = setupLogStore(ctx, c, s)
 	if err != nil {
-		return fmt.Errorf("could not setup log store: %w", err)
+		return nil, fmt.Errorf("could not setup log store: %w", err)
 	}
 
 	// authentication
@@ -184,6 +299,36 @@ func setupEvilGlobals(ctx context.Context, c *cli.Command, s store.Store) error
 	server.Config.Pipeline.DefaultCancelPreviousPipelineEvents = events
 	server.Config.Pipeline.DefaultTimeout = c.Int("default-pipeline-timeout")
 	server.Config.Pipeline.MaxTimeout = c.Int("max-pipeline-timeout")
+	server.Config.Pipeline.LogSearchIndex = c.Bool("log-search-index")
+	if size := c.String("log-step-max-size"); size != "" {
+		maxSize, err := units.RAMInBytes(size)
+		if err != nil {
+			return nil, fmt.Errorf("invalid step log size '%s': %w", size, err)
+		}
+		server.Config.Pipeline.StepLogMaxSize = maxSize
+	}
+	server.Config.Pipeline.StepLogMaxLines = int(c.Int("log-step-max-lines"))
+	if size := c.String("log-quota-repo"); size != "" {
+		quota, err := units.RAMInBytes(size)
+		if err != nil {
+			return nil, fmt.Errorf("invalid repo log quota '%s': %w", size, err)
+		}
+		server.Config.Pipeline.LogQuota.Repo = quota
+	}
+	if size := c.String("log-quota-org"); size != "" {
+		quota, err := units.RAMInBytes(size)
+		if err != nil {
+			return nil, fmt.Errorf("invalid org log quota '%s': %w", size, err)
+		}
+		server.Config.Pipeline.LogQuota.Org = quota
+	}
+	server.Config.Pipeline.LogQuota.WarnPercent = int(c.Int("log-quota-warn-percent"))
+	switch action := c.String("log-quota-action"); action {
+	case pipeline.LogQuotaActionBlock, pipeline.LogQuotaActionPurge:
+		server.Config.Pipeline.LogQuota.Action = action
+	default:
+		return nil, fmt.Errorf("invalid log quota action '%s'", action)
+	}
 
 	// limits
 	server.Config.Pipeline.Limits.MemSwapLimit = c.Int("limit-mem-swap")
@@ -201,7 +346,7 @@ func setupEvilGlobals(ctx context.Context, c *cli.Command, s store.Store) error
 	// server configuration
 	server.Config.Server.JWTSecret, err = setupJWTSecret(s)
 	if err != nil {
-		return fmt.Errorf("could not setup jwt secret: %w", err)
+		return nil, fmt.Errorf("could not setup jwt secret: %w", err)
 	}
 	server.Config.Server.Cert = c.String("server-cert")
 	server.Config.Server.Key = c.String("server-key")
@@ -244,5 +389,5 @@ func setupEvilGlobals(ctx context.Context, c *cli.Command, s store.Store) error
 	server.Config.Permissions.Admins = permissions.NewAdmins(c.StringSlice("admin"))
 	server.Config.Permissions.Orgs = permissions.NewOrgs(c.StringSlice("orgs"))
 	server.Config.Permissions.OwnersAllowlist = permissions.NewOwnersAllowlist(c.StringSlice("repo-owners"))
-	return nil
+	return s, nil
 }
@@ -68,6 +68,18 @@ func TestSchema(t *testing.T) {
 			name:     "Run on",
 			testFile: ".woodpecker/test-run-on.yaml",
 		},
+		{
+			name:     "Priority",
+			testFile: ".woodpecker/test-priority.yaml",
+		},
+		{
+			name:     "Concurrency",
+			testFile: ".woodpecker/test-concurrency.yaml",
+		},
+		{
+			name:     "Resources",
+			testFile: ".woodpecker/test-resources.yaml",
+		},
 		{
 			name:     "Service",
 			testFile: ".woodpecker/test-service.yaml",
@@ -16,20 +16,24 @@ package types
 
 import (
 	"go.woodpecker-ci.org/woodpecker/v2/pipeline/frontend/yaml/constraint"
+	"go.woodpecker-ci.org/woodpecker/v2/pipeline/frontend/yaml/types/base"
 )
 
 type (
 	// Workflow defines a workflow configuration.
 	Workflow struct {
-		When      constraint.When   `yaml:"when,omitempty"`
-		Workspace Workspace         `yaml:"workspace,omitempty"`
-		Clone     ContainerList     `yaml:"clone,omitempty"`
-		Steps     ContainerList     `yaml:"steps,omitempty"`
-		Services  ContainerList     `yaml:"services,omitempty"`
-		Labels    map[string]string `yaml:"labels,omitempty"`
-		DependsOn []string          `yaml:"depends_on,omitempty"`
-		RunsOn    []string          `yaml:"runs_on,omitempty"`
-		SkipClone bool              `yaml:"skip_clone"`
+		When        constraint.When   `yaml:"when,omitempty"`
+		Workspace   Workspace         `yaml:"workspace,omitempty"`
+		Clone       ContainerList     `yaml:"clone,omitempty"`
+		Steps       ContainerList     `yaml:"steps,omitempty"`
+		Services    ContainerList     `yaml:"services,omitempty"`
+		Labels      map[string]string `yaml:"labels,omitempty"`
+		DependsOn   []string          `yaml:"depends_on,omitempty"`
+		RunsOn      []string          `yaml:"runs_on,omitempty"`
+		SkipClone   bool              `yaml:"skip_clone"`
+		Priority    *int              `yaml:"priority,omitempty"`
+		Concurrency *Concurrency      `yaml:"concurrency,omitempty"`
+		Resources   *Resources        `yaml:"resources,omitempty"`
 
 		// Undocumented
 		Networks WorkflowNetworks `yaml:"networks,omitempty"`
@@ -43,6 +47,19 @@ type (
 		PipelineDoNotUseIt ContainerList `yaml:"pipeline,omitempty"` // TODO: remove in next major version
 	}
 
+	// Concurrency defines a group of workflows of which only one runs at a time.
+	Concurrency struct {
+		Group            string `yaml:"group"`
+		CancelInProgress bool   `yaml:"cancel_in_progress,omitempty"`
+	}
+
+	// Resources defines the resources a workflow requests from an agent.
+	Resources struct {
+		CPU    float64             `yaml:"cpu,omitempty"`
+		Memory base.MemStringOrInt `yaml:"memory,omitempty"`
+		Disk   base.MemStringOrInt `yaml:"disk,omitempty"`
+	}
+
 	// Workspace defines a pipeline workspace.
 	Workspace struct {
 		Base string
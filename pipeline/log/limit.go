@@ -0,0 +1,89 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package log
+
+import (
+	"fmt"
+
+	"github.com/docker/go-units"
+)
+
+// Limit restricts the log output of a single step. Zero values mean unlimited.
+type Limit struct {
+	Bytes int64
+	Lines int
+}
+
+// IsZero returns true if the limit does not restrict anything.
+func (l Limit) IsZero() bool {
+	return l.Bytes <= 0 && l.Lines <= 0
+}
+
+// Limiter tracks the log output of a single step against a limit.
+type Limiter struct {
+	limit     Limit
+	bytes     int64
+	lines     int
+	truncated bool
+}
+
+// NewLimiter returns a new limiter for the given limit.
+func NewLimiter(limit Limit) *Limiter {
+	return &Limiter{limit: limit}
+}
+
+// Allow reports whether a line with the given size can still be written.
+// The first line exceeding the limit returns the message of the truncation
+// marker that should be written instead, all following lines are rejected
+// with an empty message.
+func (l *Limiter) Allow(size int) (bool, string) {
+	if l.truncated {
+		return false, ""
+	}
+
+	if l.limit.Lines > 0 && l.lines+1 > l.limit.Lines {
+		l.truncated = true
+		return false, fmt.Sprintf("log truncated after %d lines", l.limit.Lines)
+	}
+	if l.limit.Bytes > 0 && l.bytes+int64(size) > l.limit.Bytes {
+		l.truncated = true
+		return false, fmt.Sprintf("log truncated after %s", units.BytesSize(float64(l.limit.Bytes)))
+	}
+
+	l.lines++
+	l.bytes += int64(size)
+	return true, ""
+}
+
+// Add counts log output written before the limiter was created, e.g. by
+// another server instance or before a restart.
+func (l *Limiter) Add(bytes int64, lines int) {
+	l.bytes += bytes
+	l.lines += lines
+}
+
+// Truncate marks the log as truncated without writing a marker, e.g. if the marker
+// was already written by someone else.
+func (l *Limiter) Truncate() {
+	l.truncated = true
+}
+
+// Truncated returns true if the limit was hit.
+func (l *Limiter) Truncated() bool {
+	return l.truncated
+}
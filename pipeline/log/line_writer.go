@@ -36,36 +36,174 @@ type LineWriter struct {
 	num       int
 	startTime time.Time
 	replacer  *strings.Replacer
+	limiter   *Limiter
+	sections  []section
+	split     *splitSecrets
+	flush     *time.Timer
+}
+
+// splitSecretDelay is the maximum time a line is held back because it could
+// contain the start of a secret split across lines.
+const splitSecretDelay = 500 * time.Millisecond
+
+// section is a section of the logs opened by a section marker.
+type section struct {
+	name   string
+	header bool
 }
 
 // NewLineWriter returns a new line reader.
-func NewLineWriter(peer rpc.Peer, stepUUID string, secret ...string) io.Writer {
+func NewLineWriter(peer rpc.Peer, stepUUID string, secret ...string) io.WriteCloser {
+	return NewLimitedLineWriter(peer, stepUUID, Limit{}, secret...)
+}
+
+// NewLimitedLineWriter returns a new line reader which drops all output after the
+// limit was hit and writes a truncation marker instead.
+func NewLimitedLineWriter(peer rpc.Peer, stepUUID string, limit Limit, secret ...string) io.WriteCloser {
 	lw := &LineWriter{
 		peer:      peer,
 		stepUUID:  stepUUID,
 		startTime: time.Now().UTC(),
 		replacer:  shared.NewSecretsReplacer(secret),
+		split:     newSplitSecrets(secret),
+	}
+	if !limit.IsZero() {
+		lw.limiter = NewLimiter(limit)
 	}
 	return lw
 }
 
 func (w *LineWriter) Write(p []byte) (n int, err error) {
+	w.Lock()
+	defer w.Unlock()
+
 	data := string(p)
 	if w.replacer != nil {
 		data = w.replacer.Replace(data)
 	}
+
+	kind, name := parseSectionMarker(data)
+	if kind != sectionNone {
+		// lines held back can't be continued by a section marker
+		w.flushPending(false)
+	}
+
+	switch {
+	case kind == sectionHeader:
+		w.endSections(true)
+		if w.send(rpc.LogEntrySectionStart, name) {
+			w.sections = append(w.sections, section{name: name, header: true})
+		}
+	case kind == sectionStart:
+		if w.send(rpc.LogEntrySectionStart, name) {
+			w.sections = append(w.sections, section{name: name})
+		}
+	case kind == sectionEnd && len(w.sections) > 0:
+		w.endSections(true)
+		w.endSection()
+	default:
+		w.sendOutput(data)
+	}
+
+	return len(p), nil
+}
+
+// Close sends the lines held back and ends all sections which are still open.
+func (w *LineWriter) Close() error {
+	w.Lock()
+	defer w.Unlock()
+
+	w.flushPending(true)
+	w.endSections(false)
+	return nil
+}
+
+// sendOutput sends a line of output, holding it back if it ends with the start
+// of a secret which could be continued by the next line.
+func (w *LineWriter) sendOutput(data string) {
+	if w.split == nil {
+		w.send(rpc.LogEntryStdout, data)
+		return
+	}
+
+	for _, line := range w.split.push(strings.TrimSuffix(data, "\n")) {
+		w.send(rpc.LogEntryStdout, line)
+	}
+
+	switch {
+	case len(w.split.pending) == 0 && w.flush != nil:
+		w.flush.Stop()
+		w.flush = nil
+	case len(w.split.pending) > 0 && w.flush == nil:
+		w.flush = time.AfterFunc(splitSecretDelay, func() {
+			w.Lock()
+			defer w.Unlock()
+			w.flushPending(true)
+		})
+	}
+}
+
+// flushPending sends the lines held back, the start of a secret at their end is
+// masked if requested.
+func (w *LineWriter) flushPending(maskPartial bool) {
+	if w.split == nil {
+		return
+	}
+	if w.flush != nil {
+		w.flush.Stop()
+		w.flush = nil
+	}
+	for _, line := range w.split.flush(maskPartial) {
+		w.send(rpc.LogEntryStdout, line)
+	}
+}
+
+// endSections ends the open sections, or only the innermost sections started
+// by a header if headersOnly is set.
+func (w *LineWriter) endSections(headersOnly bool) {
+	for len(w.sections) > 0 && (!headersOnly || w.sections[len(w.sections)-1].header) {
+		w.endSection()
+	}
+}
+
+// endSection ends the innermost open section.
+func (w *LineWriter) endSection() {
+	if len(w.sections) == 0 {
+		return
+	}
+	name := w.sections[len(w.sections)-1].name
+	w.sections = w.sections[:len(w.sections)-1]
+	// section ends are not limited, as there can't be more ends than the already sent starts
+	w.enqueue(rpc.LogEntrySectionEnd, name)
+}
+
+// send enqueues the log entry if the limit allows it and returns whether it was sent.
+func (w *LineWriter) send(entryType int, data string) bool {
+	if w.limiter != nil {
+		ok, marker := w.limiter.Allow(len(data))
+		if !ok {
+			if marker != "" {
+				w.enqueue(rpc.LogEntryTruncated, marker)
+			}
+			return false
+		}
+	}
+	w.enqueue(entryType, data)
+	return true
+}
+
+func (w *LineWriter) enqueue(entryType int, data string) {
 	log.Trace().Str("step-uuid", w.stepUUID).Msgf("grpc write line: %s", data)
 
 	line := &rpc.LogEntry{
 		Data:     []byte(strings.TrimSuffix(data, "\n")), // remove trailing newline
 		StepUUID: w.stepUUID,
 		Time:     int64(time.Since(w.startTime).Seconds()),
-		Type:     rpc.LogEntryStdout,
+		Type:     entryType,
 		Line:     w.num,
 	}
 
 	w.num++
 
 	w.peer.EnqueueLog(line)
-	return len(data), nil
 }
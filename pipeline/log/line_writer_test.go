@@ -55,3 +55,126 @@ func TestLineWriter(t *testing.T) {
 
 	peer.AssertExpectations(t)
 }
+
+func TestLimitedLineWriter(t *testing.T) {
+	peer := mocks.NewPeer(t)
+	peer.On("EnqueueLog", mock.Anything)
+
+	lw := log.NewLimitedLineWriter(peer, "e9ea76a5-44a1-4059-9c4a-6956c478b26d", log.Limit{Lines: 2})
+
+	for i := 0; i < 5; i++ {
+		_, err := lw.Write([]byte("hello world\n"))
+		assert.NoError(t, err)
+	}
+
+	peer.AssertNumberOfCalls(t, "EnqueueLog", 3)
+	peer.AssertCalled(t, "EnqueueLog", &rpc.LogEntry{
+		StepUUID: "e9ea76a5-44a1-4059-9c4a-6956c478b26d",
+		Time:     0,
+		Type:     rpc.LogEntryTruncated,
+		Line:     2,
+		Data:     []byte("log truncated after 2 lines"),
+	})
+}
+
+func TestLimiter(t *testing.T) {
+	limiter := log.NewLimiter(log.Limit{Bytes: 10 * 1024 * 1024})
+
+	ok, marker := limiter.Allow(6 * 1024 * 1024)
+	assert.True(t, ok)
+	assert.Empty(t, marker)
+
+	ok, marker = limiter.Allow(6 * 1024 * 1024)
+	assert.False(t, ok)
+	assert.Equal(t, "log truncated after 10MiB", marker)
+	assert.True(t, limiter.Truncated())
+
+	ok, marker = limiter.Allow(1)
+	assert.False(t, ok)
+	assert.Empty(t, marker)
+}
+
+func TestLineWriterSections(t *testing.T) {
+	peer := mocks.NewPeer(t)
+	var entries []*rpc.LogEntry
+	peer.On("EnqueueLog", mock.Anything).Run(func(args mock.Arguments) {
+		entries = append(entries, args.Get(0).(*rpc.LogEntry))
+	})
+
+	lw := log.NewLineWriter(peer, "e9ea76a5-44a1-4059-9c4a-6956c478b26d")
+	for _, line := range []string{
+		"::group::build\n",
+		"go build\n",
+		"##[group]test\r\n",
+		"go test\n",
+		"##[endgroup]\n",
+		"::endgroup::\n",
+		"::endgroup::\n",
+		"##[section]lint\n",
+		"##[section]vet\n",
+		"::group::deploy\n",
+	} {
+		_, err := lw.Write([]byte(line))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, lw.Close())
+
+	type entry struct {
+		Type int
+		Data string
+	}
+	var got []entry
+	for i, e := range entries {
+		assert.Equal(t, i, e.Line)
+		got = append(got, entry{e.Type, string(e.Data)})
+	}
+	assert.Equal(t, []entry{
+		{rpc.LogEntrySectionStart, "build"},
+		{rpc.LogEntryStdout, "go build"},
+		{rpc.LogEntrySectionStart, "test"},
+		{rpc.LogEntryStdout, "go test"},
+		{rpc.LogEntrySectionEnd, "test"},
+		{rpc.LogEntrySectionEnd, "build"},
+		{rpc.LogEntryStdout, "::endgroup::"},
+		{rpc.LogEntrySectionStart, "lint"},
+		{rpc.LogEntrySectionEnd, "lint"},
+		{rpc.LogEntrySectionStart, "vet"},
+		{rpc.LogEntrySectionStart, "deploy"},
+		{rpc.LogEntrySectionEnd, "deploy"},
+		{rpc.LogEntrySectionEnd, "vet"},
+	}, got)
+}
+
+func TestLineWriterSplitSecrets(t *testing.T) {
+	peer := mocks.NewPeer(t)
+	var lines []string
+	peer.On("EnqueueLog", mock.Anything).Run(func(args mock.Arguments) {
+		lines = append(lines, string(args.Get(0).(*rpc.LogEntry).Data))
+	})
+
+	lw := log.NewLineWriter(peer, "e9ea76a5-44a1-4059-9c4a-6956c478b26d", "my-very-long-secret-value")
+	for _, line := range []string{
+		"start\n",
+		// base64 -w 16 of the secret
+		"bXktdmVyeS1sb25n\n",
+		"LXNlY3JldC12YWx1\n",
+		"ZQ==\n",
+		"token: my-very-\n",
+		"long-secret-value and more\n",
+		"ends with my-ve\n",
+	} {
+		_, err := lw.Write([]byte(line))
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, []string{
+		"start",
+		"********",
+		"********",
+		"********",
+		"token: ********",
+		"******** and more",
+	}, lines)
+
+	assert.NoError(t, lw.Close())
+	assert.Equal(t, "ends with ********", lines[len(lines)-1])
+}
@@ -0,0 +1,174 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package log
+
+import (
+	"strings"
+
+	"go.woodpecker-ci.org/woodpecker/v2/pipeline/shared"
+)
+
+// minSplitPrefix is the minimum length of the start of a secret at the end of a
+// line to hold the line back until it is known whether the secret continues in
+// the next lines.
+const minSplitPrefix = 4
+
+// splitSecrets masks secrets which are split across multiple log lines, e.g. by
+// tools wrapping long base64 encoded values. Lines ending with the start of a
+// secret are held back until the following lines show whether the secret continues.
+type splitSecrets struct {
+	values  []string
+	pending []string
+}
+
+func newSplitSecrets(secrets []string) *splitSecrets {
+	var values []string
+	for _, value := range shared.SecretValues(secrets) {
+		// multi-line secrets printed as they are get masked line by line already
+		if !strings.Contains(value, "\n") {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return &splitSecrets{values: values}
+}
+
+// push adds a line and returns the lines which are ready to be written.
+func (s *splitSecrets) push(line string) []string {
+	lines := append(s.pending, line)
+	s.mask(lines)
+
+	// hold back the lines containing the start of a possibly split secret
+	keep := lineAt(lines, s.partialStart(lines))
+	if keep < 0 {
+		s.pending = nil
+		return lines
+	}
+	s.pending = append([]string(nil), lines[keep:]...)
+	return lines[:keep]
+}
+
+// flush returns the held back lines. If the start of a secret could still be
+// continued by a following line it is masked if requested.
+func (s *splitSecrets) flush(maskPartial bool) []string {
+	lines := s.pending
+	s.pending = nil
+	if !maskPartial {
+		return lines
+	}
+
+	start := s.partialStart(lines)
+	offset := 0
+	for i, line := range lines {
+		if start >= 0 && offset+len(line) > start {
+			lines[i] = line[:max(start-offset, 0)] + shared.SecretMask
+		}
+		offset += len(line)
+	}
+	return lines
+}
+
+// mask masks the secrets spanning multiple of the lines. Secrets inside of a single
+// line are masked by the secrets replacer already.
+func (s *splitSecrets) mask(lines []string) {
+	if len(lines) < 2 { //nolint:mnd
+		return
+	}
+
+	joined := strings.Join(lines, "")
+	masked := make([]bool, len(joined))
+	found := false
+	for _, value := range s.values {
+		for i := 0; i+len(value) <= len(joined); {
+			idx := strings.Index(joined[i:], value)
+			if idx < 0 {
+				break
+			}
+			for j := i + idx; j < i+idx+len(value); j++ {
+				masked[j] = true
+			}
+			found = true
+			i += idx + len(value)
+		}
+	}
+	if !found {
+		return
+	}
+
+	offset := 0
+	for i, line := range lines {
+		var b strings.Builder
+		inMask := false
+		for j := 0; j < len(line); j++ {
+			if masked[offset+j] {
+				if !inMask {
+					b.WriteString(shared.SecretMask)
+				}
+				inMask = true
+				continue
+			}
+			inMask = false
+			b.WriteByte(line[j])
+		}
+		offset += len(line)
+		lines[i] = b.String()
+	}
+}
+
+// partialStart returns the position in the joined lines at which a secret starts
+// that is not complete yet, or -1 if the lines do not end with the start of a secret.
+func (s *splitSecrets) partialStart(lines []string) int {
+	joined := strings.Join(lines, "")
+	start := -1
+	for _, value := range s.values {
+		prefix := value[:minSplitPrefix]
+		for i := max(len(joined)-len(value)+1, 0); i <= len(joined)-minSplitPrefix; {
+			idx := strings.Index(joined[i:], prefix)
+			if idx < 0 {
+				break
+			}
+			pos := i + idx
+			if strings.HasPrefix(value, joined[pos:]) {
+				if start < 0 || pos < start {
+					start = pos
+				}
+				break
+			}
+			i = pos + 1
+		}
+	}
+	return start
+}
+
+// lineAt returns the index of the line containing the position in the joined lines,
+// or -1 if the position is negative.
+func lineAt(lines []string, pos int) int {
+	if pos < 0 {
+		return -1
+	}
+	offset := 0
+	for i, line := range lines {
+		offset += len(line)
+		if pos < offset {
+			return i
+		}
+	}
+	return len(lines) - 1
+}
@@ -0,0 +1,56 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package log
+
+import "strings"
+
+type sectionKind int
+
+const (
+	sectionNone sectionKind = iota
+	// sectionStart starts a section which is ended by a sectionEnd marker.
+	sectionStart
+	// sectionEnd ends the innermost section.
+	sectionEnd
+	// sectionHeader starts a section which is ended by the next header or the end of the step.
+	sectionHeader
+)
+
+var sectionMarkers = []struct {
+	prefix string
+	kind   sectionKind
+}{
+	{"::group::", sectionStart},
+	{"::endgroup::", sectionEnd},
+	{"##[group]", sectionStart},
+	{"##[endgroup]", sectionEnd},
+	{"##[section]Starting: ", sectionStart},
+	{"##[section]Finishing: ", sectionEnd},
+	{"##[section]", sectionHeader},
+}
+
+// parseSectionMarker returns the kind and name of the section if the line is a section marker.
+func parseSectionMarker(line string) (sectionKind, string) {
+	line = strings.TrimRight(line, "\r\n")
+	for _, marker := range sectionMarkers {
+		if name, ok := strings.CutPrefix(line, marker.prefix); ok {
+			return marker.kind, strings.TrimSpace(name)
+		}
+	}
+	return sectionNone, ""
+}
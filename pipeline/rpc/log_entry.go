@@ -26,6 +26,9 @@ const (
 	LogEntryExitCode
 	LogEntryMetadata
 	LogEntryProgress
+	LogEntryTruncated
+	LogEntrySectionStart
+	LogEntrySectionEnd
 )
 
 // Line is a line of console output.
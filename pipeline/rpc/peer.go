@@ -55,6 +55,23 @@ type (
 		GrpcVersion   int32  `json:"grpc_version,omitempty"`
 		ServerVersion string `json:"server_version,omitempty"`
 	}
+
+	// AgentInfo defines the details an agent registers with.
+	AgentInfo struct {
+		Platform  string    `json:"platform"`
+		Backend   string    `json:"backend"`
+		Version   string    `json:"version"`
+		Capacity  int       `json:"capacity"`
+		Resources Resources `json:"resources"`
+	}
+
+	// Resources defines the resources an agent can allocate to workflows.
+	// Zero means the resource is not limited.
+	Resources struct {
+		CPU    int64 `json:"cpu"`    // millicores
+		Memory int64 `json:"memory"` // bytes
+		Disk   int64 `json:"disk"`   // bytes
+	}
 )
 
 //go:generate mockery --name Peer --output mocks --case underscore --note "+build test"
@@ -86,7 +103,7 @@ type Peer interface {
 	EnqueueLog(logEntry *LogEntry)
 
 	// RegisterAgent register our agent to the server
-	RegisterAgent(ctx context.Context, platform, backend, version string, capacity int) (int64, error)
+	RegisterAgent(ctx context.Context, info AgentInfo) (int64, error)
 
 	// UnregisterAgent unregister our agent from the server
 	UnregisterAgent(ctx context.Context) error
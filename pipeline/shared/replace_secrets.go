@@ -14,34 +14,135 @@
 
 package shared
 
-import "strings"
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// SecretMask replaces secrets in the logs.
+const SecretMask = "********"
+
+// Strings shorter than minStringLength are not considered secrets.
+// Do not sanitize them.
+const minStringLength = 3
+
+// Parts of base64 encodings shorter than minBase64FragmentLength are not
+// masked, as such short fragments of short secrets are common in unrelated
+// base64 values.
+const minBase64FragmentLength = 8
 
 // NewSecretsReplacer creates a new strings.Replacer to replace sensitive
 // strings with asterisks. It takes a slice of secrets strings as input
 // and returns a populated strings.Replacer that will replace those
-// secrets with asterisks. Each secret string is split on newlines to
-// handle multi-line secrets.
+// secrets and their common encodings with asterisks. Each secret string
+// is split on newlines to handle multi-line secrets.
 func NewSecretsReplacer(secrets []string) *strings.Replacer {
+	seen := make(map[string]struct{})
+	var parts []string
+	for _, value := range SecretValues(secrets) {
+		// since replacer is executed on each line we have to split multi-line-secrets
+		for _, part := range strings.Split(value, "\n") {
+			if _, ok := seen[part]; ok || len(part) == 0 {
+				continue
+			}
+			seen[part] = struct{}{}
+			parts = append(parts, part)
+		}
+	}
+	// the replacer prefers earlier pairs, so longer secrets have to come first
+	sortLongestFirst(parts)
+
 	var oldNew []string
+	for _, part := range parts {
+		oldNew = append(oldNew, part, SecretMask)
+	}
+	return strings.NewReplacer(oldNew...)
+}
 
-	// Strings shorter than minStringLength are not considered secrets.
-	// Do not sanitize them.
-	const minStringLength = 3
+// SecretValues returns the secrets together with the encodings tools commonly
+// print them in (base64, URL and JSON escaped), longest first.
+func SecretValues(secrets []string) []string {
+	seen := make(map[string]struct{})
+	var values []string
+	add := func(value string) {
+		if _, ok := seen[value]; ok || len(value) <= minStringLength {
+			return
+		}
+		seen[value] = struct{}{}
+		values = append(values, value)
+	}
 
-	for _, old := range secrets {
-		old = strings.TrimSpace(old)
-		if len(old) <= minStringLength {
+	for _, secret := range secrets {
+		secret = strings.TrimSpace(secret)
+		if len(secret) <= minStringLength {
 			continue
 		}
-		// since replacer is executed on each line we have to split multi-line-secrets
-		for _, part := range strings.Split(old, "\n") {
-			if len(part) == 0 {
-				continue
+		add(secret)
+		add(url.QueryEscape(secret))
+		add(url.PathEscape(secret))
+		for _, escaped := range jsonEscape(secret) {
+			add(escaped)
+		}
+		for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding} {
+			for _, encoded := range base64Variants(encoding, secret) {
+				add(encoded)
 			}
-			oldNew = append(oldNew, part)
-			oldNew = append(oldNew, "********")
 		}
 	}
 
-	return strings.NewReplacer(oldNew...)
+	sortLongestFirst(values)
+	return values
+}
+
+// jsonEscape returns the secret escaped as JSON string with and without HTML escaping.
+func jsonEscape(secret string) []string {
+	var escaped []string
+	for _, escapeHTML := range []bool{true, false} {
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(escapeHTML)
+		if err := enc.Encode(secret); err != nil {
+			continue
+		}
+		escaped = append(escaped, strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(buf.String()), `"`), `"`))
+	}
+	return escaped
+}
+
+// base64Variants returns the parts of the base64 encoding which only depend on the
+// secret, for each of the three possible alignments of the secret inside of a larger
+// encoded value like "user:secret". Parts shorter than minBase64FragmentLength are
+// left out.
+func base64Variants(encoding *base64.Encoding, secret string) []string {
+	const (
+		bitsPerByte = 8
+		bitsPerChar = 6
+		alignments  = 3
+	)
+
+	variants := make([]string, 0, alignments+1)
+	variants = append(variants, encoding.EncodeToString([]byte(secret)))
+	raw := encoding.WithPadding(base64.NoPadding)
+	for offset := 0; offset < alignments; offset++ {
+		encoded := raw.EncodeToString(append(make([]byte, offset), secret...))
+		start := (offset*bitsPerByte + bitsPerChar - 1) / bitsPerChar
+		end := (offset + len(secret)) * bitsPerByte / bitsPerChar
+		if end-start >= minBase64FragmentLength {
+			variants = append(variants, encoded[start:end])
+		}
+	}
+	return variants
+}
+
+func sortLongestFirst(values []string) {
+	sort.SliceStable(values, func(i, j int) bool {
+		if len(values[i]) != len(values[j]) {
+			return len(values[i]) > len(values[j])
+		}
+		return values[i] < values[j]
+	})
 }
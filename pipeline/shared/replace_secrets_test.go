@@ -51,6 +51,33 @@ func TestNewSecretsReplacer(t *testing.T) {
 		log:     "start log\ndone\nnow\nan\nmulti line secret!! ;)\nwith\ntwo\n\nnewlines",
 		secrets: []string{"an\nmulti line secret!!", "two\n\nnewlines"},
 		expect:  "start log\ndone\nnow\n********\n******** ;)\nwith\n********\n\n********",
+	}, {
+		name:    "base64 encoded secret",
+		log:     "token: c3VwZXJzZWNyZXQ=",
+		secrets: []string{"supersecret"},
+		expect:  "token: ********",
+	}, {
+		name: "base64 encoded secret inside of a larger value",
+		// base64("user:supersecret"), base64("us:supersecret") and base64("u:supersecret")
+		log:     "dXNlcjpzdXBlcnNlY3JldA== dXM6c3VwZXJzZWNyZXQ= dTpzdXBlcnNlY3JldA==",
+		secrets: []string{"supersecret"},
+		expect:  "dXNlcjp********A== dXM6******** dTp********A==",
+	}, {
+		name: "base64 encoded short secret",
+		// base64("abcd") and base64("abcxyz"), which starts with the same characters
+		log:     "YWJjZA== YWJjeHl6",
+		secrets: []string{"abcd"},
+		expect:  "******** YWJjeHl6",
+	}, {
+		name:    "url encoded secret",
+		log:     "https://example.com/?token=p%40ss+w%2Ford",
+		secrets: []string{"p@ss w/ord"},
+		expect:  "https://example.com/?token=********",
+	}, {
+		name:    "json escaped multi line secret",
+		log:     `{"key":"-----BEGIN KEY-----\nMIIEvQIBADANBg\n-----END KEY-----"}`,
+		secrets: []string{"-----BEGIN KEY-----\nMIIEvQIBADANBg\n-----END KEY-----"},
+		expect:  `{"key":"********"}`,
 	}}
 
 	for _, c := range tc {
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
@@ -30,6 +31,7 @@ import (
 	"go.woodpecker-ci.org/woodpecker/v2/server/forge/types"
 	"go.woodpecker-ci.org/woodpecker/v2/server/model"
 	"go.woodpecker-ci.org/woodpecker/v2/server/pipeline"
+	"go.woodpecker-ci.org/woodpecker/v2/server/queue"
 	"go.woodpecker-ci.org/woodpecker/v2/server/store"
 	"go.woodpecker-ci.org/woodpecker/v2/shared/token"
 )
@@ -44,9 +46,26 @@ import (
 //	@Tags			Pipeline queues
 //	@Param			Authorization	header	string	true	"Insert your personal access token"	default(Bearer <personal access token>)
 func GetQueueInfo(c *gin.Context) {
-	c.IndentedJSON(http.StatusOK,
-		server.Config.Services.Queue.Info(c),
-	)
+	info := server.Config.Services.Queue.Info(c)
+
+	agents, err := store.FromContext(c).AgentList(&model.ListOptions{All: true})
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Error getting agent list. %s", err)
+		return
+	}
+	now := time.Now()
+	for _, tasks := range [][]*model.Task{info.Pending, info.WaitingOnLimit} {
+		for _, task := range tasks {
+			if mismatches := queue.ExplainAgents(task, agents, now); mismatches != nil {
+				if info.NoEligibleAgent == nil {
+					info.NoEligibleAgent = make(map[string][]queue.AgentMismatch)
+				}
+				info.NoEligibleAgent[task.ID] = mismatches
+			}
+		}
+	}
+
+	c.IndentedJSON(http.StatusOK, info)
 }
 
 // PauseQueue
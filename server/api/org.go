@@ -24,6 +24,7 @@ import (
 
 	"go.woodpecker-ci.org/woodpecker/v2/server"
 	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/pipeline"
 	"go.woodpecker-ci.org/woodpecker/v2/server/router/middleware/session"
 	"go.woodpecker-ci.org/woodpecker/v2/server/store"
 )
@@ -75,6 +76,38 @@ func GetOrg(c *gin.Context) {
 	c.JSON(http.StatusOK, org)
 }
 
+// GetOrgStorageUsage
+//
+//	@Summary	Get the log storage used by all repositories of an organization
+//	@Router		/orgs/{org_id}/usage [get]
+//	@Produce	json
+//	@Success	200	{object}	StorageUsage
+//	@Tags		Organization
+//	@Param		Authorization	header	string	true	"Insert your personal access token"	default(Bearer <personal access token>)
+//	@Param		org_id			path	string	true	"the organization's id"
+func GetOrgStorageUsage(c *gin.Context) {
+	_store := store.FromContext(c)
+
+	orgID, err := strconv.ParseInt(c.Param("org_id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Error parsing org id. %s", err)
+		return
+	}
+
+	org, err := _store.OrgGet(orgID)
+	if err != nil {
+		handleDBError(c, err)
+		return
+	}
+
+	usage, err := pipeline.OrgStorageUsage(_store, org)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, usage)
+}
+
 // GetOrgPermissions
 //
 //	@Summary	Get the permissions of the currently authenticated user for the given organization
@@ -188,6 +221,74 @@ func LookupOrg(c *gin.Context) {
 	c.JSON(http.StatusOK, org)
 }
 
+// PatchOrg
+//
+//	@Summary		Update an organization
+//	@Description	Updates the scheduling settings of the given org. Requires admin rights.
+//	@Router			/orgs/{org_id} [patch]
+//	@Produce		json
+//	@Success		200	{object}	Org
+//	@Tags			Orgs
+//	@Param			Authorization	header	string		true	"Insert your personal access token"	default(Bearer <personal access token>)
+//	@Param			org_id			path	string		true	"the organization's id"
+//	@Param			org				body	OrgPatch	true	"the organization's settings"
+func PatchOrg(c *gin.Context) {
+	_store := store.FromContext(c)
+
+	orgID, err := strconv.ParseInt(c.Param("org_id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Error parsing org id. %s", err)
+		return
+	}
+
+	in := new(model.OrgPatch)
+	if err := c.Bind(in); err != nil {
+		c.String(http.StatusBadRequest, "Error parsing request body. %s", err)
+		return
+	}
+
+	org, err := _store.OrgGet(orgID)
+	if err != nil {
+		handleDBError(c, err)
+		return
+	}
+
+	if in.Priority != nil {
+		org.Priority = *in.Priority
+	}
+	if in.Weight != nil {
+		if *in.Weight < 1 {
+			c.String(http.StatusBadRequest, "Weight must be at least 1")
+			return
+		}
+		org.Weight = *in.Weight
+	}
+	if in.MaxConcurrent != nil {
+		if *in.MaxConcurrent < 0 {
+			c.String(http.StatusBadRequest, "Max concurrent must not be negative")
+			return
+		}
+		org.MaxConcurrent = *in.MaxConcurrent
+	}
+	if in.LogQuota != nil {
+		if *in.LogQuota < 0 {
+			c.String(http.StatusBadRequest, "Log quota must not be negative")
+			return
+		}
+		org.LogQuota = *in.LogQuota
+	}
+
+	if err := _store.OrgUpdate(org); err != nil {
+		c.String(http.StatusInternalServerError, "Error updating org %d. %s", orgID, err)
+		return
+	}
+	if in.LogQuota != nil {
+		pipeline.ResetOrgLogQuotasCache()
+	}
+
+	c.JSON(http.StatusOK, org)
+}
+
 // DeleteOrg
 //
 //	@Summary		Delete an organization
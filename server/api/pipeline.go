@@ -17,10 +17,12 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"regexp/syntax"
 	"strconv"
 	"strings"
 	"time"
@@ -31,7 +33,9 @@ import (
 	"go.woodpecker-ci.org/woodpecker/v2/server"
 	"go.woodpecker-ci.org/woodpecker/v2/server/model"
 	"go.woodpecker-ci.org/woodpecker/v2/server/pipeline"
+	"go.woodpecker-ci.org/woodpecker/v2/server/queue"
 	"go.woodpecker-ci.org/woodpecker/v2/server/router/middleware/session"
+	logService "go.woodpecker-ci.org/woodpecker/v2/server/services/log"
 	"go.woodpecker-ci.org/woodpecker/v2/server/store"
 )
 
@@ -259,6 +263,10 @@ func GetPipelineLast(c *gin.Context) {
 //	@Param		repo_id			path	int		true	"the repository id"
 //	@Param		number			path	int		true	"the number of the pipeline"
 //	@Param		stepID			path	int		true	"the step id"
+//	@Param		from_line		query	int		false	"the first line to return"
+//	@Param		to_line			query	int		false	"the last line to return"
+//	@Param		offset			query	int		false	"the number of entries to skip"
+//	@Param		limit			query	int		false	"the maximum number of entries to return"
 func GetStepLogs(c *gin.Context) {
 	_store := store.FromContext(c)
 	repo := session.Repo(c)
@@ -295,7 +303,13 @@ func GetStepLogs(c *gin.Context) {
 		return
 	}
 
-	logs, err := server.Config.Services.LogStore.LogFind(step)
+	r, err := parseLogRange(c)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	logs, err := logService.FindRange(server.Config.Services.LogStore, step, r)
 	if err != nil {
 		handleDBError(c, err)
 		return
@@ -304,6 +318,30 @@ func GetStepLogs(c *gin.Context) {
 	c.JSON(http.StatusOK, logs)
 }
 
+func parseLogRange(c *gin.Context) (logService.Range, error) {
+	var r logService.Range
+	for _, param := range []struct {
+		name  string
+		value *int
+	}{
+		{"from_line", &r.FromLine},
+		{"to_line", &r.ToLine},
+		{"offset", &r.Offset},
+		{"limit", &r.Limit},
+	} {
+		value := c.Query(param.name)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return r, fmt.Errorf("invalid %s '%s'", param.name, value)
+		}
+		*param.value = n
+	}
+	return r, nil
+}
+
 // DeleteStepLogs
 //
 //	@Summary	Delete step logs of a pipeline
@@ -355,11 +393,15 @@ func DeleteStepLogs(c *gin.Context) {
 		return
 	}
 
-	err = _store.LogDelete(_step)
+	err = server.Config.Services.LogStore.LogDelete(_step)
 	if err != nil {
 		handleDBError(c, err)
 		return
 	}
+	if err := _store.StepResetLogSize(_step); err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
 
 	c.Status(http.StatusNoContent)
 }
@@ -398,6 +440,68 @@ func GetPipelineConfig(c *gin.Context) {
 	c.JSON(http.StatusOK, configs)
 }
 
+// ExplainPipeline
+//
+//	@Summary	Explain why the workflows of a pipeline are still pending
+//	@Description	For each pending workflow the queue state is explained together with every connected agent and why it can't run the workflow. Requires admin permissions on the repository, as agent names and labels are included.
+//	@Router		/repos/{repo_id}/pipelines/{number}/explain [get]
+//	@Produce	json
+//	@Success	200	{array}	QueueExplanation
+//	@Tags		Pipelines
+//	@Param		Authorization	header	string	true	"Insert your personal access token"	default(Bearer <personal access token>)
+//	@Param		repo_id			path	int		true	"the repository id"
+//	@Param		number			path	int		true	"the number of the pipeline"
+func ExplainPipeline(c *gin.Context) {
+	_store := store.FromContext(c)
+	repo := session.Repo(c)
+	num, err := strconv.ParseInt(c.Param("number"), 10, 64)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	pl, err := _store.GetPipelineNumber(repo, num)
+	if err != nil {
+		handleDBError(c, err)
+		return
+	}
+
+	workflows, err := _store.WorkflowGetTree(pl)
+	if err != nil {
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	agents, err := _store.AgentList(&model.ListOptions{All: true})
+	if err != nil {
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	names := make(map[string]string, len(workflows))
+	for _, workflow := range workflows {
+		names[fmt.Sprint(workflow.ID)] = workflow.Name
+	}
+
+	info := server.Config.Services.Queue.Info(c)
+	explanations := make([]*queue.Explanation, 0, len(workflows))
+	for _, workflow := range workflows {
+		e := queue.Explain(info, fmt.Sprint(workflow.ID), agents)
+		if e == nil || e.State == queue.TaskStateRunning {
+			continue
+		}
+		e.Workflow = workflow.Name
+		for i, dep := range e.WaitingFor {
+			if name, ok := names[dep]; ok {
+				e.WaitingFor[i] = name
+			}
+		}
+		explanations = append(explanations, e)
+	}
+
+	c.JSON(http.StatusOK, explanations)
+}
+
 // CancelPipeline
 //
 //	@Summary	Cancel a pipeline
@@ -598,6 +702,160 @@ func PostPipeline(c *gin.Context) {
 	}
 }
 
+// GetPipelineLogArchive
+//
+//	@Summary	Download the logs of all steps of a pipeline as archive
+//	@Router		/repos/{repo_id}/logs/{number}/archive [get]
+//	@Produce	application/gzip
+//	@Produce	application/zip
+//	@Success	200
+//	@Tags		Pipeline logs
+//	@Param		Authorization	header	string	true	"Insert your personal access token"	default(Bearer <personal access token>)
+//	@Param		repo_id			path	int		true	"the repository id"
+//	@Param		number			path	int		true	"the number of the pipeline"
+//	@Param		format			query	string	false	"the archive format, tar.gz or zip"	default(tar.gz)
+//	@Param		strip_ansi		query	bool	false	"remove ANSI escape sequences like colors"
+func GetPipelineLogArchive(c *gin.Context) {
+	_store := store.FromContext(c)
+	repo := session.Repo(c)
+
+	num, err := strconv.ParseInt(c.Params.ByName("number"), 10, 64)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	opts := logService.ArchiveOptions{
+		Format:    c.DefaultQuery("format", logService.ArchiveTarGz),
+		StripANSI: c.Query("strip_ansi") == "true",
+	}
+	contentType := "application/gzip"
+	switch opts.Format {
+	case logService.ArchiveTarGz:
+	case logService.ArchiveZip:
+		contentType = "application/zip"
+	default:
+		c.String(http.StatusBadRequest, "Unknown archive format '%s'", opts.Format)
+		return
+	}
+
+	pl, err := _store.GetPipelineNumber(repo, num)
+	if err != nil {
+		handleDBError(c, err)
+		return
+	}
+	workflows, err := _store.WorkflowGetTree(pl)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	filename := fmt.Sprintf("%s-%s-%d-logs.%s", repo.Owner, repo.Name, pl.Number, opts.Format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Header("Content-Type", contentType)
+	c.Status(http.StatusOK)
+
+	// the archive is streamed, so errors can't change the response anymore
+	if err := logService.WriteArchive(c.Writer, server.Config.Services.LogStore, repo, pl, workflows, opts); err != nil {
+		log.Error().Err(err).Msgf("could not write log archive of pipeline %d of repo %s", pl.Number, repo.FullName)
+	}
+}
+
+const (
+	logSearchDefaultDays    = 7
+	logSearchMaxDays        = 365
+	logSearchDefaultContext = 2
+	logSearchMaxContext     = 10
+	logSearchDefaultLimit   = 100
+	logSearchMaxLimit       = 1000
+	// a search stops after this many log bytes or this time and returns where
+	// to continue, so a single request can't scan the logs of a whole year
+	logSearchMaxBytes = 256 * 1024 * 1024
+	logSearchTimeout  = 30 * time.Second
+)
+
+// SearchLogs
+//
+//	@Summary	Search the logs of the pipelines of a repository
+//	@Description	The search stops after scanning a limited amount of logs or time. The result then contains the pipeline number to continue the search at with the start parameter.
+//	@Router		/repos/{repo_id}/logs/search [get]
+//	@Produce	json
+//	@Success	200	{object}	LogSearchResult
+//	@Tags		Pipeline logs
+//	@Param		Authorization	header	string	true	"Insert your personal access token"	default(Bearer <personal access token>)
+//	@Param		repo_id			path	int		true	"the repository id"
+//	@Param		q				query	string	true	"the text to search for"
+//	@Param		regex			query	bool	false	"search for a regular expression"
+//	@Param		days			query	int		false	"only search pipelines of the last days"	default(7)
+//	@Param		context			query	int		false	"number of lines to return before and after a match"	default(2)
+//	@Param		limit			query	int		false	"maximum number of matches"	default(100)
+//	@Param		start			query	int		false	"continue the search at this pipeline number"
+func SearchLogs(c *gin.Context) {
+	_store := store.FromContext(c)
+	repo := session.Repo(c)
+
+	opts := logService.SearchOptions{
+		Query:    c.Query("q"),
+		Regex:    c.Query("regex") == "true",
+		MaxBytes: logSearchMaxBytes,
+		Index:    server.Config.Pipeline.LogSearchIndex,
+	}
+	if opts.Query == "" {
+		c.String(http.StatusBadRequest, "Missing search query")
+		return
+	}
+	if start := c.Query("start"); start != "" {
+		var err error
+		if opts.Start, err = strconv.ParseInt(start, 10, 64); err != nil || opts.Start < 0 {
+			c.String(http.StatusBadRequest, "start must be a pipeline number")
+			return
+		}
+	}
+
+	days, err := queryInt(c, "days", logSearchDefaultDays, logSearchMaxDays)
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	opts.Since = time.Now().AddDate(0, 0, -days)
+	if opts.Context, err = queryInt(c, "context", logSearchDefaultContext, logSearchMaxContext); err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	if opts.Limit, err = queryInt(c, "limit", logSearchDefaultLimit, logSearchMaxLimit); err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c, logSearchTimeout)
+	defer cancel()
+	results, err := logService.Search(ctx, _store, server.Config.Services.LogStore, repo, opts)
+	if err != nil {
+		var syntaxErr *syntax.Error
+		if errors.As(err, &syntaxErr) {
+			c.String(http.StatusBadRequest, "Invalid regular expression: %s", err)
+			return
+		}
+		_ = c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, results)
+}
+
+// queryInt returns the integer query parameter, the default value if it is
+// not set or an error if it is negative or larger than the maximum.
+func queryInt(c *gin.Context, name string, def, maximum int) (int, error) {
+	value := c.Query(name)
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 || n > maximum {
+		return 0, fmt.Errorf("%s must be a number between 0 and %d", name, maximum)
+	}
+	return n, nil
+}
+
 // DeletePipelineLogs
 //
 //	@Summary	Deletes all logs of a pipeline
@@ -635,6 +893,9 @@ func DeletePipelineLogs(c *gin.Context) {
 		if lErr := server.Config.Services.LogStore.LogDelete(step); err != nil {
 			err = errors.Join(err, lErr)
 		}
+		if lErr := _store.StepResetLogSize(step); lErr != nil {
+			err = errors.Join(err, lErr)
+		}
 	}
 	if err != nil {
 		c.String(http.StatusInternalServerError, "Error deleting pipeline logs. %s", err)
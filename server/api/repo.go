@@ -30,6 +30,7 @@ import (
 	"go.woodpecker-ci.org/woodpecker/v2/server"
 	"go.woodpecker-ci.org/woodpecker/v2/server/forge"
 	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/pipeline"
 	"go.woodpecker-ci.org/woodpecker/v2/server/router/middleware/session"
 	"go.woodpecker-ci.org/woodpecker/v2/server/store"
 	"go.woodpecker-ci.org/woodpecker/v2/server/store/types"
@@ -231,6 +232,16 @@ func PatchRepo(c *gin.Context) {
 		c.String(http.StatusForbidden, "Insufficient privileges")
 		return
 	}
+	if in.Priority != nil && *in.Priority != repo.Priority && !user.Admin {
+		log.Trace().Msgf("user '%s' wants to change the repo priority without being an instance admin", user.Login)
+		c.String(http.StatusForbidden, "Insufficient privileges")
+		return
+	}
+	if in.LogQuota != nil && *in.LogQuota != repo.LogQuota && !user.Admin {
+		log.Trace().Msgf("user '%s' wants to change the repo log quota without being an instance admin", user.Login)
+		c.String(http.StatusForbidden, "Insufficient privileges")
+		return
+	}
 
 	if in.AllowPull != nil {
 		repo.AllowPull = *in.AllowPull
@@ -268,6 +279,44 @@ func PatchRepo(c *gin.Context) {
 	if in.NetrcOnlyTrusted != nil {
 		repo.NetrcOnlyTrusted = *in.NetrcOnlyTrusted
 	}
+	if in.Priority != nil {
+		repo.Priority = *in.Priority
+	}
+	if in.MaxConcurrent != nil {
+		if *in.MaxConcurrent < 0 {
+			c.String(http.StatusBadRequest, "Max concurrent must not be negative")
+			return
+		}
+		repo.MaxConcurrent = *in.MaxConcurrent
+	}
+	if in.MaxPendingAge != nil {
+		if *in.MaxPendingAge < 0 {
+			c.String(http.StatusBadRequest, "Max pending age must not be negative")
+			return
+		}
+		repo.MaxPendingAge = *in.MaxPendingAge
+	}
+	if in.RetentionKeepPerBranch != nil {
+		if *in.RetentionKeepPerBranch < 0 {
+			c.String(http.StatusBadRequest, "Retention keep per branch must not be negative")
+			return
+		}
+		repo.RetentionKeepPerBranch = *in.RetentionKeepPerBranch
+	}
+	if in.RetentionDays != nil {
+		if *in.RetentionDays < 0 {
+			c.String(http.StatusBadRequest, "Retention days must not be negative")
+			return
+		}
+		repo.RetentionDays = *in.RetentionDays
+	}
+	if in.LogQuota != nil {
+		if *in.LogQuota < 0 {
+			c.String(http.StatusBadRequest, "Log quota must not be negative")
+			return
+		}
+		repo.LogQuota = *in.LogQuota
+	}
 	if in.Visibility != nil {
 		switch *in.Visibility {
 		case string(model.VisibilityInternal), string(model.VisibilityPrivate), string(model.VisibilityPublic):
@@ -340,6 +389,24 @@ func GetRepo(c *gin.Context) {
 	c.JSON(http.StatusOK, repo)
 }
 
+// GetRepoStorageUsage
+//
+//	@Summary	Get the log storage used by a repository
+//	@Router		/repos/{repo_id}/usage [get]
+//	@Produce	json
+//	@Success	200	{object}	StorageUsage
+//	@Tags		Repositories
+//	@Param		Authorization	header	string	true	"Insert your personal access token"	default(Bearer <personal access token>)
+//	@Param		repo_id			path	int		true	"the repository id"
+func GetRepoStorageUsage(c *gin.Context) {
+	usage, err := pipeline.RepoStorageUsage(store.FromContext(c), session.Repo(c))
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, usage)
+}
+
 // GetRepoPermissions
 //
 //	@Summary		Check current authenticated users access to the repository
@@ -30,7 +30,7 @@ import (
 
 var Config = struct {
 	Services struct {
-		Pubsub     *pubsub.Publisher
+		Pubsub     pubsub.Publisher
 		Queue      queue.Queue
 		Logs       logging.Log
 		Membership cache.MembershipService
@@ -72,7 +72,16 @@ var Config = struct {
 		Privileged                          []string
 		DefaultTimeout                      int64
 		MaxTimeout                          int64
-		Proxy                               struct {
+		LogSearchIndex                      bool
+		StepLogMaxSize                      int64
+		StepLogMaxLines                     int
+		LogQuota                            struct {
+			Repo        int64
+			Org         int64
+			WarnPercent int
+			Action      string
+		}
+		Proxy struct {
 			No    string
 			HTTP  string
 			HTTPS string
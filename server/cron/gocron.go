@@ -15,6 +15,7 @@
 package cron
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"time"
@@ -23,7 +24,10 @@ import (
 
 	"github.com/go-co-op/gocron/v2"
 	"github.com/rs/zerolog/log"
+	"go.woodpecker-ci.org/woodpecker/v2/server"
 	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/pipeline"
+	pipelineLog "go.woodpecker-ci.org/woodpecker/v2/server/services/log"
 	"go.woodpecker-ci.org/woodpecker/v2/server/store"
 )
 
@@ -40,6 +44,18 @@ const (
 	cleanupPipelineLogsSchedule        = 1 * time.Hour
 	cleanupPipelineLogsId              = "cleanupPipelineLogs"
 	cleanupPipelineLogsMessageTemplate = "Deleted by cleanup task, retention %s"
+
+	cleanupPipelinesSchedule = 1 * time.Hour
+	cleanupPipelinesId       = "cleanupPipelines"
+	// orphanedConfigsGracePeriod keeps configs persisted recently, as they could
+	// be about to be linked to a pipeline which is created right now
+	orphanedConfigsGracePeriod = 1 * time.Hour
+
+	purgeLogQuotasSchedule = 1 * time.Hour
+	purgeLogQuotasId       = "purgeLogQuotas"
+
+	expirePendingWorkflowsSchedule = 1 * time.Minute
+	expirePendingWorkflowsId       = "expirePendingWorkflows"
 )
 
 type Cron struct {
@@ -69,6 +85,16 @@ func (c *Cron) Start() {
 	if logsRetention != "" {
 		c.setupPipelineLogsCleanup(logsRetention)
 	}
+	// always set up, as repos can configure a retention policy themselves. Without
+	// any policy it only lists the repos, as orphaned configs and logs are only
+	// cleaned up after pipelines got deleted.
+	c.setupPipelinesCleanup(c.cmd.String("maintenance-cleanup-pipelines-older-than"),
+		int(c.cmd.Int("maintenance-cleanup-pipelines-keep-per-branch")))
+	if server.Config.Pipeline.LogQuota.Action == pipeline.LogQuotaActionPurge {
+		c.setupLogQuotasPurge()
+	}
+	// always set up, as repos can configure a maximum pending age themselves
+	c.setupPendingWorkflowsExpiry(c.cmd.String("maintenance-expire-pending-workflows-older-than"))
 	c.scheduler.Start()
 }
 
@@ -116,6 +142,73 @@ func (c *Cron) setupPipelineLogsCleanup(retentionStr string) {
 		Msg(maintenanceTaskInitializedMessage)
 }
 
+func (c *Cron) setupPipelinesCleanup(maxAgeStr string, keepPerBranch int) {
+	log.Debug().Str("task", cleanupPipelinesId).Msg(maintenanceTaskInitializingMessage)
+
+	policy := pipeline.RetentionPolicy{KeepPerBranch: keepPerBranch}
+	if maxAgeStr != "" {
+		var err error
+		policy.MaxAge, err = time.ParseDuration(maxAgeStr)
+		if err != nil {
+			log.Error().Err(err).Str("task", cleanupPipelinesId).Msg(maintenanceTaskInitializeFailedMessage)
+			return
+		}
+	}
+
+	jobDef := gocron.DurationJob(cleanupPipelinesSchedule)
+	task := gocron.NewTask(cleanupPipelines, c.store, policy)
+	_, err := c.scheduler.NewJob(jobDef, task, gocron.WithSingletonMode(gocron.LimitModeReschedule))
+	if err != nil {
+		log.Error().Err(err).Str("task", cleanupPipelinesId).Msg(maintenanceTaskInitializeFailedMessage)
+		return
+	}
+
+	log.Info().Str("task", cleanupPipelinesId).
+		Str("max-age", policy.MaxAge.String()).
+		Int("keep-per-branch", policy.KeepPerBranch).
+		Msg(maintenanceTaskInitializedMessage)
+}
+
+func (c *Cron) setupLogQuotasPurge() {
+	log.Debug().Str("task", purgeLogQuotasId).Msg(maintenanceTaskInitializingMessage)
+
+	jobDef := gocron.DurationJob(purgeLogQuotasSchedule)
+	task := gocron.NewTask(purgeLogQuotas, c.store)
+	_, err := c.scheduler.NewJob(jobDef, task)
+	if err != nil {
+		log.Error().Err(err).Str("task", purgeLogQuotasId).Msg(maintenanceTaskInitializeFailedMessage)
+		return
+	}
+
+	log.Info().Str("task", purgeLogQuotasId).Msg(maintenanceTaskInitializedMessage)
+}
+
+func (c *Cron) setupPendingWorkflowsExpiry(maxAgeStr string) {
+	log.Debug().Str("task", expirePendingWorkflowsId).Msg(maintenanceTaskInitializingMessage)
+
+	var maxAge time.Duration
+	if maxAgeStr != "" {
+		var err error
+		maxAge, err = time.ParseDuration(maxAgeStr)
+		if err != nil {
+			log.Error().Err(err).Str("task", expirePendingWorkflowsId).Msg(maintenanceTaskInitializeFailedMessage)
+			return
+		}
+	}
+
+	jobDef := gocron.DurationJob(expirePendingWorkflowsSchedule)
+	task := gocron.NewTask(expirePendingWorkflows, c.store, maxAge)
+	_, err := c.scheduler.NewJob(jobDef, task)
+	if err != nil {
+		log.Error().Err(err).Str("task", expirePendingWorkflowsId).Msg(maintenanceTaskInitializeFailedMessage)
+		return
+	}
+
+	log.Info().Str("task", expirePendingWorkflowsId).
+		Str("max-age", maxAge.String()).
+		Msg(maintenanceTaskInitializedMessage)
+}
+
 func cleanupStaleAgents(store store.Store, retention time.Duration) {
 	log.Debug().Str("task", cleanupStaleAgentsId).Msg(maintenanceTaskStartedMessage)
 
@@ -185,6 +278,9 @@ func cleanupPipelineLogs(store store.Store, retention time.Duration) {
 							log.Error().Err(err).Str("task", cleanupPipelineLogsId).Msg("failed to delete logs")
 							continue
 						}
+						if err := store.StepResetLogSize(step); err != nil {
+							log.Error().Err(err).Str("task", cleanupPipelineLogsId).Msg("failed to reset log size")
+						}
 
 						firstEntry := logs[0]
 						firstEntry.Data = []byte(fmt.Sprintf(cleanupPipelineLogsMessageTemplate, retention))
@@ -201,3 +297,55 @@ func cleanupPipelineLogs(store store.Store, retention time.Duration) {
 
 	log.Debug().Str("task", cleanupPipelineLogsId).Msg(maintenanceTaskCompletedMessage)
 }
+
+func cleanupPipelines(store store.Store, policy pipeline.RetentionPolicy) {
+	log.Debug().Str("task", cleanupPipelinesId).Msg(maintenanceTaskStartedMessage)
+	ctx := context.Background()
+
+	pipelines, err := pipeline.ApplyRetention(ctx, store, policy)
+	if err != nil {
+		log.Error().Err(err).Str("task", cleanupPipelinesId).Msg("failed to apply retention policies")
+	}
+	if pipelines == 0 {
+		// without deleted pipelines there is nothing new to clean up
+		log.Debug().Str("task", cleanupPipelinesId).Msg(maintenanceTaskCompletedMessage)
+		return
+	}
+
+	configs, err := store.ConfigDeleteOrphaned(time.Now().Add(-orphanedConfigsGracePeriod).Unix())
+	if err != nil {
+		log.Error().Err(err).Str("task", cleanupPipelinesId).Msg("failed to delete orphaned configs")
+	}
+	logs, err := pipelineLog.DeleteOrphaned(ctx, store, server.Config.Services.LogStore)
+	if err != nil {
+		log.Error().Err(err).Str("task", cleanupPipelinesId).Msg("failed to delete orphaned logs")
+	}
+
+	log.Debug().Str("task", cleanupPipelinesId).
+		Int("pipelines", pipelines).
+		Int64("configs", configs).
+		Int("logs", logs).
+		Msg(maintenanceTaskCompletedMessage)
+}
+
+func purgeLogQuotas(store store.Store) {
+	log.Debug().Str("task", purgeLogQuotasId).Msg(maintenanceTaskStartedMessage)
+
+	purged, err := pipeline.PurgeLogQuotas(context.Background(), store)
+	if err != nil {
+		log.Error().Err(err).Str("task", purgeLogQuotasId).Msg("failed to purge logs exceeding quotas")
+	}
+
+	log.Debug().Str("task", purgeLogQuotasId).Int("steps", purged).Msg(maintenanceTaskCompletedMessage)
+}
+
+func expirePendingWorkflows(store store.Store, maxAge time.Duration) {
+	log.Debug().Str("task", expirePendingWorkflowsId).Msg(maintenanceTaskStartedMessage)
+
+	if err := pipeline.ExpirePendingWorkflows(context.Background(), store, maxAge); err != nil {
+		log.Error().Err(err).Str("task", expirePendingWorkflowsId).Msg("failed to expire pending workflows")
+		return
+	}
+
+	log.Debug().Str("task", expirePendingWorkflowsId).Msg(maintenanceTaskCompletedMessage)
+}
@@ -22,27 +22,6 @@ import (
 
 func createFilterFunc(agentFilter rpc.Filter) queue.FilterFn {
 	return func(task *model.Task) bool {
-		for taskLabel, taskLabelValue := range task.Labels {
-			// if a task label is empty it will be ignored
-			if taskLabelValue == "" {
-				continue
-			}
-
-			agentLabelValue, ok := agentFilter.Labels[taskLabel]
-
-			if !ok {
-				return false
-			}
-
-			// if agent label has a wildcard
-			if agentLabelValue == "*" {
-				continue
-			}
-
-			if taskLabelValue != agentLabelValue {
-				return false
-			}
-		}
-		return true
+		return queue.MatchLabels(task.Labels, agentFilter.Labels) == nil
 	}
 }
@@ -80,6 +80,22 @@ func TestCreateFilterFunc(t *testing.T) {
 			},
 			exp: true,
 		},
+		{
+			name:        "agent matching label selectors",
+			agentLabels: map[string]string{"arch": "arm64", "zone": "eu-1", "memory": "64"},
+			task: model.Task{
+				Labels: map[string]string{"gpu": "!false", "arch": "in(amd64,arm64)", "zone": "exists", "memory": ">=32"},
+			},
+			exp: true,
+		},
+		{
+			name:        "agent not matching label selectors",
+			agentLabels: map[string]string{"arch": "arm64", "gpu": "false"},
+			task: model.Task{
+				Labels: map[string]string{"gpu": "!false", "arch": "in(amd64,arm64)"},
+			},
+			exp: false,
+		},
 	}
 
 	for _, test := range tests {
@@ -0,0 +1,133 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package grpc
+
+import (
+	"sync"
+	"time"
+
+	pipelineLog "go.woodpecker-ci.org/woodpecker/v2/pipeline/log"
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+)
+
+// stepLogLimitTTL is the time after which the limiter of a step without new logs
+// is dropped, in case the step is never reported as finished.
+const stepLogLimitTTL = 24 * time.Hour
+
+// stepLogLimits tracks the log output of running steps against the configured limits.
+type stepLogLimits struct {
+	sync.Mutex
+	limiters  map[int64]*stepLogLimiter
+	lastSweep time.Time
+}
+
+type stepLogLimiter struct {
+	*pipelineLog.Limiter
+	used time.Time
+}
+
+func newStepLogLimits() *stepLogLimits {
+	return &stepLogLimits{
+		limiters:  make(map[int64]*stepLogLimiter),
+		lastSweep: time.Now(),
+	}
+}
+
+// apply drops the log entries exceeding the limit of the step and adds a truncation
+// marker instead. It returns the remaining entries and whether the logs got truncated,
+// either by the limit or by a truncation marker already sent by the agent.
+func (l *stepLogLimits) apply(step *model.Step, entries []*model.LogEntry, limit pipelineLog.Limit) ([]*model.LogEntry, bool) {
+	if limit.IsZero() {
+		for _, entry := range entries {
+			if entry.Type == model.LogEntryTruncated {
+				return entries, true
+			}
+		}
+		return entries, false
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	now := time.Now()
+	l.sweep(now)
+	limiter, ok := l.limiters[step.ID]
+	if !ok {
+		// continue counting the logs stored before, e.g. by another server
+		limiter = &stepLogLimiter{Limiter: pipelineLog.NewLimiter(limit)}
+		limiter.Add(step.LogSize, int(step.LogLines))
+		if step.LogTruncated {
+			limiter.Truncate()
+		}
+		l.limiters[step.ID] = limiter
+	}
+	limiter.used = now
+
+	truncated := false
+	filtered := make([]*model.LogEntry, 0, len(entries))
+	for _, entry := range entries {
+		if limiter.Truncated() {
+			break
+		}
+
+		if entry.Type == model.LogEntryTruncated {
+			limiter.Truncate()
+			truncated = true
+			filtered = append(filtered, entry)
+			continue
+		}
+
+		allowed, marker := limiter.Allow(len(entry.Data))
+		if allowed {
+			filtered = append(filtered, entry)
+			continue
+		}
+
+		truncated = true
+		filtered = append(filtered, &model.LogEntry{
+			StepID: entry.StepID,
+			Time:   entry.Time,
+			Line:   entry.Line,
+			Type:   model.LogEntryTruncated,
+			Data:   []byte(marker),
+		})
+	}
+	return filtered, truncated
+}
+
+// sweep drops the limiters not used within stepLogLimitTTL, at most once per hour.
+func (l *stepLogLimits) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Hour {
+		return
+	}
+	l.lastSweep = now
+	for id, limiter := range l.limiters {
+		if now.Sub(limiter.used) > stepLogLimitTTL {
+			delete(l.limiters, id)
+		}
+	}
+}
+
+// release forgets the log output of the steps.
+func (l *stepLogLimits) release(stepIDs ...int64) {
+	l.Lock()
+	defer l.Unlock()
+	for _, id := range stepIDs {
+		delete(l.limiters, id)
+	}
+}
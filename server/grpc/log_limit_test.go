@@ -0,0 +1,114 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package grpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	pipelineLog "go.woodpecker-ci.org/woodpecker/v2/pipeline/log"
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+)
+
+func TestStepLogLimits(t *testing.T) {
+	limits := newStepLogLimits()
+	step := &model.Step{ID: 1}
+	limit := pipelineLog.Limit{Lines: 3}
+
+	entries := func(lines ...int) []*model.LogEntry {
+		var entries []*model.LogEntry
+		for _, line := range lines {
+			entries = append(entries, &model.LogEntry{StepID: step.ID, Line: line, Data: []byte("hello")})
+		}
+		return entries
+	}
+
+	filtered, truncated := limits.apply(step, entries(0, 1), limit)
+	assert.Len(t, filtered, 2)
+	assert.False(t, truncated)
+
+	filtered, truncated = limits.apply(step, entries(2, 3, 4), limit)
+	assert.True(t, truncated)
+	if assert.Len(t, filtered, 2) {
+		assert.Equal(t, model.LogEntryTruncated, filtered[1].Type)
+		assert.Equal(t, 3, filtered[1].Line)
+		assert.Equal(t, "log truncated after 3 lines", string(filtered[1].Data))
+	}
+
+	filtered, truncated = limits.apply(step, entries(5), limit)
+	assert.Empty(t, filtered)
+	assert.False(t, truncated)
+
+	limits.release(step.ID)
+	assert.Empty(t, limits.limiters)
+}
+
+func TestStepLogLimitsSweep(t *testing.T) {
+	limits := newStepLogLimits()
+	limit := pipelineLog.Limit{Lines: 3}
+	entries := []*model.LogEntry{{Data: []byte("hello")}}
+
+	limits.apply(&model.Step{ID: 1}, entries, limit)
+	limits.apply(&model.Step{ID: 2}, entries, limit)
+
+	// step 1 never reported to be finished
+	limits.limiters[1].used = time.Now().Add(-2 * stepLogLimitTTL)
+	limits.lastSweep = time.Now().Add(-2 * time.Hour)
+
+	limits.apply(&model.Step{ID: 2}, entries, limit)
+	assert.NotContains(t, limits.limiters, int64(1))
+	assert.Contains(t, limits.limiters, int64(2))
+}
+
+func TestStepLogLimitsAgentMarker(t *testing.T) {
+	limits := newStepLogLimits()
+	step := &model.Step{ID: 1}
+
+	filtered, truncated := limits.apply(step, []*model.LogEntry{
+		{StepID: step.ID, Line: 0, Data: []byte("hello")},
+		{StepID: step.ID, Line: 1, Type: model.LogEntryTruncated, Data: []byte("log truncated after 1 lines")},
+	}, pipelineLog.Limit{})
+	assert.Len(t, filtered, 2)
+	assert.True(t, truncated)
+
+	// the limit of the server does not apply to steps truncated before
+	filtered, _ = limits.apply(&model.Step{ID: 2, LogTruncated: true}, []*model.LogEntry{
+		{StepID: 2, Line: 0, Data: []byte("hello")},
+	}, pipelineLog.Limit{Lines: 10})
+	assert.Empty(t, filtered)
+}
+
+func TestStepLogLimitsStoredLogs(t *testing.T) {
+	limits := newStepLogLimits()
+	entries := []*model.LogEntry{{Data: []byte("hello")}, {Data: []byte("world")}}
+
+	// the logs stored before, e.g. by another server, count against the limit
+	filtered, truncated := limits.apply(&model.Step{ID: 1, LogSize: 100, LogLines: 2}, entries, pipelineLog.Limit{Lines: 3})
+	assert.True(t, truncated)
+	if assert.Len(t, filtered, 2) {
+		assert.Equal(t, "log truncated after 3 lines", string(filtered[1].Data))
+	}
+
+	filtered, truncated = limits.apply(&model.Step{ID: 2, LogSize: 100, LogLines: 2}, entries, pipelineLog.Limit{Bytes: 105})
+	assert.True(t, truncated)
+	if assert.Len(t, filtered, 2) {
+		assert.Equal(t, model.LogEntryTruncated, filtered[1].Type)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"strconv"
 	"time"
 
@@ -28,6 +29,7 @@ import (
 	"github.com/rs/zerolog/log"
 	grpcMetadata "google.golang.org/grpc/metadata"
 
+	pipelineLog "go.woodpecker-ci.org/woodpecker/v2/pipeline/log"
 	"go.woodpecker-ci.org/woodpecker/v2/pipeline/rpc"
 	"go.woodpecker-ci.org/woodpecker/v2/server"
 	"go.woodpecker-ci.org/woodpecker/v2/server/forge"
@@ -36,6 +38,7 @@ import (
 	"go.woodpecker-ci.org/woodpecker/v2/server/pipeline"
 	"go.woodpecker-ci.org/woodpecker/v2/server/pubsub"
 	"go.woodpecker-ci.org/woodpecker/v2/server/queue"
+	logService "go.woodpecker-ci.org/woodpecker/v2/server/services/log"
 	"go.woodpecker-ci.org/woodpecker/v2/server/store"
 )
 
@@ -44,11 +47,12 @@ const updateAgentLastWorkDelay = time.Minute
 
 type RPC struct {
 	queue         queue.Queue
-	pubsub        *pubsub.Publisher
+	pubsub        pubsub.Publisher
 	logger        logging.Log
 	store         store.Store
 	pipelineTime  *prometheus.GaugeVec
 	pipelineCount *prometheus.CounterVec
+	logLimits     *stepLogLimits
 }
 
 // Next blocks until it provides the next workflow to execute.
@@ -64,6 +68,14 @@ func (s *RPC) Next(c context.Context, agentFilter rpc.Filter) (*rpc.Workflow, er
 		return nil, err
 	}
 
+	if !maps.Equal(agent.Labels, agentFilter.Labels) {
+		// remember the labels to explain why tasks don't match the agent
+		agent.Labels = agentFilter.Labels
+		if err := s.store.AgentUpdateLabels(agent); err != nil {
+			log.Error().Err(err).Msgf("could not update labels of agent %d", agent.ID)
+		}
+	}
+
 	if agent.NoSchedule {
 		time.Sleep(1 * time.Second)
 		return nil, nil
@@ -153,6 +165,11 @@ func (s *RPC) Update(_ context.Context, strWorkflowID string, state rpc.StepStat
 		log.Error().Err(err).Msg("rpc.update: cannot update step")
 	}
 
+	if state.Exited {
+		s.logLimits.release(step.ID)
+		s.compactLogs(step)
+	}
+
 	if currentPipeline.Workflows, err = s.store.WorkflowGetTree(currentPipeline); err != nil {
 		log.Error().Err(err).Msg("cannot build tree from step list")
 		return err
@@ -307,6 +324,14 @@ func (s *RPC) Done(c context.Context, strWorkflowID string, state rpc.WorkflowSt
 
 	s.updateForgeStatus(c, repo, currentPipeline, workflow)
 
+	// steps which never reported to have exited are done with the workflow
+	stepIDs := make([]int64, 0, len(workflow.Children))
+	for _, step := range workflow.Children {
+		stepIDs = append(stepIDs, step.ID)
+	}
+	s.logLimits.release(stepIDs...)
+	s.compactLogs(workflow.Children...)
+
 	// make sure writes to pubsub are non blocking (https://github.com/woodpecker-ci/woodpecker/blob/c919f32e0b6432a95e1a6d3d0ad662f591adf73f/server/logging/log.go#L9)
 	go func() {
 		for _, step := range workflow.Children {
@@ -368,6 +393,19 @@ func (s *RPC) Log(c context.Context, stepUUID string, rpcLogEntries []*rpc.LogEn
 		})
 	}
 
+	logEntries, truncated := s.logLimits.apply(step, logEntries, pipelineLog.Limit{
+		Bytes: server.Config.Pipeline.StepLogMaxSize,
+		Lines: server.Config.Pipeline.StepLogMaxLines,
+	})
+	if truncated && !step.LogTruncated {
+		if err := s.store.StepSetLogTruncated(step); err != nil {
+			log.Error().Err(err).Msgf("could not mark logs of step %d as truncated", step.ID)
+		}
+	}
+	if len(logEntries) == 0 {
+		return nil
+	}
+
 	// make sure writes to pubsub are non blocking (https://github.com/woodpecker-ci/woodpecker/blob/c919f32e0b6432a95e1a6d3d0ad662f591adf73f/server/logging/log.go#L9)
 	go func() {
 		// write line to listening web clients
@@ -378,12 +416,24 @@ func (s *RPC) Log(c context.Context, stepUUID string, rpcLogEntries []*rpc.LogEn
 
 	if err = server.Config.Services.LogStore.LogAppend(step, logEntries); err != nil {
 		log.Error().Err(err).Msg("could not store log entries")
+		return nil
+	}
+
+	var size, lines int64
+	for _, entry := range logEntries {
+		size += int64(len(entry.Data))
+		if entry.Type != model.LogEntryTruncated {
+			lines++
+		}
+	}
+	if err := s.store.StepAddLogSize(step, size, lines); err != nil {
+		log.Error().Err(err).Msgf("could not update log size of step %d", step.ID)
 	}
 
 	return nil
 }
 
-func (s *RPC) RegisterAgent(ctx context.Context, platform, backend, version string, capacity int32) (int64, error) {
+func (s *RPC) RegisterAgent(ctx context.Context, info rpc.AgentInfo) (int64, error) {
 	agent, err := s.getAgentFromContext(ctx)
 	if err != nil {
 		return -1, err
@@ -395,10 +445,15 @@ func (s *RPC) RegisterAgent(ctx context.Context, platform, backend, version stri
 		}
 	}
 
-	agent.Backend = backend
-	agent.Platform = platform
-	agent.Capacity = capacity
-	agent.Version = version
+	agent.Backend = info.Backend
+	agent.Platform = info.Platform
+	agent.Capacity = int32(info.Capacity)
+	agent.Version = info.Version
+	agent.Resources = model.Resources{
+		CPU:    info.Resources.CPU,
+		Memory: info.Resources.Memory,
+		Disk:   info.Resources.Disk,
+	}
 
 	err = s.store.AgentUpdate(agent)
 	if err != nil {
@@ -441,6 +496,22 @@ func (s *RPC) ReportHealth(ctx context.Context, status string) error {
 	return s.store.AgentUpdate(agent)
 }
 
+// compactLogs compacts the logs of finished steps in the background, if the
+// log store supports it.
+func (s *RPC) compactLogs(steps ...*model.Step) {
+	compacter, ok := server.Config.Services.LogStore.(logService.CompactService)
+	if !ok {
+		return
+	}
+	go func() {
+		for _, step := range steps {
+			if err := compacter.LogCompact(step); err != nil {
+				log.Error().Err(err).Msgf("cannot compact logs of step %d", step.ID)
+			}
+		}
+	}()
+}
+
 func (s *RPC) completeChildrenIfParentCompleted(completedWorkflow *model.Workflow) {
 	for _, c := range completedWorkflow.Children {
 		if c.Running() {
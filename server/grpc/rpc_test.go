@@ -24,6 +24,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"google.golang.org/grpc/metadata"
 
+	pipelineRPC "go.woodpecker-ci.org/woodpecker/v2/pipeline/rpc"
 	"go.woodpecker-ci.org/woodpecker/v2/server/model"
 	mocks_store "go.woodpecker-ci.org/woodpecker/v2/server/store/mocks"
 )
@@ -59,8 +60,12 @@ func TestRegisterAgent(t *testing.T) {
 				context.Background(),
 				metadata.Pairs("hostname", "hostname", "agent_id", "1337"),
 			)
-			capacity := int32(2)
-			agentID, err := rpc.RegisterAgent(ctx, "platform", "backend", "version", capacity)
+			agentID, err := rpc.RegisterAgent(ctx, pipelineRPC.AgentInfo{
+				Platform: "platform",
+				Backend:  "backend",
+				Version:  "version",
+				Capacity: 2,
+			})
 			if !assert.NoError(t, err) {
 				return
 			}
@@ -99,8 +104,12 @@ func TestRegisterAgent(t *testing.T) {
 				context.Background(),
 				metadata.Pairs("hostname", "newHostname", "agent_id", "1337"),
 			)
-			capacity := int32(2)
-			agentID, err := rpc.RegisterAgent(ctx, "platform", "backend", "version", capacity)
+			agentID, err := rpc.RegisterAgent(ctx, pipelineRPC.AgentInfo{
+				Platform: "platform",
+				Backend:  "backend",
+				Version:  "version",
+				Capacity: 2,
+			})
 			if !assert.NoError(t, err) {
 				return
 			}
@@ -37,7 +37,7 @@ type WoodpeckerServer struct {
 	peer RPC
 }
 
-func NewWoodpeckerServer(queue queue.Queue, logger logging.Log, pubsub *pubsub.Publisher, store store.Store) proto.WoodpeckerServer {
+func NewWoodpeckerServer(queue queue.Queue, logger logging.Log, pubsub pubsub.Publisher, store store.Store) proto.WoodpeckerServer {
 	pipelineTime := prometheus_auto.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: "woodpecker",
 		Name:      "pipeline_time",
@@ -55,6 +55,7 @@ func NewWoodpeckerServer(queue queue.Queue, logger logging.Log, pubsub *pubsub.P
 		logger:        logger,
 		pipelineTime:  pipelineTime,
 		pipelineCount: pipelineCount,
+		logLimits:     newStepLogLimits(),
 	}
 	return &WoodpeckerServer{peer: peer}
 }
@@ -172,7 +173,17 @@ func (s *WoodpeckerServer) Log(c context.Context, req *proto.LogRequest) (*proto
 
 func (s *WoodpeckerServer) RegisterAgent(c context.Context, req *proto.RegisterAgentRequest) (*proto.RegisterAgentResponse, error) {
 	res := new(proto.RegisterAgentResponse)
-	agentID, err := s.peer.RegisterAgent(c, req.GetPlatform(), req.GetBackend(), req.GetVersion(), req.GetCapacity())
+	agentID, err := s.peer.RegisterAgent(c, rpc.AgentInfo{
+		Platform: req.GetPlatform(),
+		Backend:  req.GetBackend(),
+		Version:  req.GetVersion(),
+		Capacity: int(req.GetCapacity()),
+		Resources: rpc.Resources{
+			CPU:    req.GetResources().GetCpu(),
+			Memory: req.GetResources().GetMemory(),
+			Disk:   req.GetResources().GetDisk(),
+		},
+	})
 	res.AgentId = agentID
 	return res, err
 }
@@ -0,0 +1,109 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	logger "github.com/rs/zerolog/log"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/pubsub"
+)
+
+const (
+	publishedWrite = "write"
+	publishedClose = "close"
+)
+
+type published struct {
+	local     Log
+	publisher pubsub.Publisher
+}
+
+// NewPublished returns a log multiplexer that shares the log entries with other
+// servers through the publisher, so a log can be tailed on any server. Each
+// server keeps the streams in memory like the in-memory multiplexer.
+func NewPublished(ctx context.Context, publisher pubsub.Publisher) Log {
+	l := &published{
+		local:     New(),
+		publisher: publisher,
+	}
+	go publisher.Subscribe(ctx, l.receive)
+	return l
+}
+
+func (l *published) Open(ctx context.Context, stepID int64) error {
+	return l.local.Open(ctx, stepID)
+}
+
+func (l *published) Write(_ context.Context, stepID int64, entries []*model.LogEntry) error {
+	return l.publish(publishedWrite, stepID, entries)
+}
+
+func (l *published) Tail(ctx context.Context, stepID int64, handler LogChan) error {
+	return l.local.Tail(ctx, stepID, handler)
+}
+
+func (l *published) Close(_ context.Context, stepID int64) error {
+	return l.publish(publishedClose, stepID, nil)
+}
+
+func (l *published) publish(op string, stepID int64, entries []*model.LogEntry) error {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	l.publisher.Publish(pubsub.Message{
+		Labels: map[string]string{
+			"op":   op,
+			"step": strconv.FormatInt(stepID, 10),
+		},
+		Data: data,
+		// tails of a stream whose close message is lost would never end
+		Required: op == publishedClose,
+	})
+	return nil
+}
+
+func (l *published) receive(message pubsub.Message) {
+	stepID, err := strconv.ParseInt(message.Labels["step"], 10, 64)
+	if err != nil {
+		logger.Error().Err(err).Msg("received log message without step")
+		return
+	}
+
+	ctx := context.Background()
+	switch message.Labels["op"] {
+	case publishedWrite:
+		var entries []*model.LogEntry
+		if err := json.Unmarshal(message.Data, &entries); err != nil {
+			logger.Error().Err(err).Msgf("cannot unmarshal logs for step %d", stepID)
+			return
+		}
+		err = l.local.Write(ctx, stepID, entries)
+	case publishedClose:
+		err = l.local.Close(ctx, stepID)
+	}
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		logger.Error().Err(err).Msgf("cannot apply published logs for step %d", stepID)
+	}
+}
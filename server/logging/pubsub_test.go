@@ -0,0 +1,93 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package logging
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/pubsub"
+)
+
+// orderedPublisher delivers the messages synchronously and in order, like the
+// publishers shared between servers.
+type orderedPublisher struct {
+	sync.Mutex
+	receivers []pubsub.Receiver
+}
+
+func (p *orderedPublisher) Publish(message pubsub.Message) {
+	p.Lock()
+	defer p.Unlock()
+	for _, receiver := range p.receivers {
+		receiver(message)
+	}
+}
+
+func (p *orderedPublisher) Subscribe(c context.Context, receiver pubsub.Receiver) {
+	p.Lock()
+	p.receivers = append(p.receivers, receiver)
+	p.Unlock()
+	<-c.Done()
+}
+
+func TestPublished(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// two servers sharing the same publisher
+	publisher := &orderedPublisher{}
+	agentServer := NewPublished(ctx, publisher)
+	userServer := NewPublished(ctx, publisher)
+
+	<-time.After(100 * time.Millisecond)
+
+	testStepID := int64(123)
+	testEntry := &model.LogEntry{StepID: testStepID, Data: []byte("test")}
+
+	assert.NoError(t, userServer.Open(ctx, testStepID))
+	receiver := make(LogChan, 10)
+	done := make(chan error)
+	go func() {
+		done <- userServer.Tail(ctx, testStepID, receiver)
+	}()
+
+	<-time.After(100 * time.Millisecond)
+
+	assert.NoError(t, agentServer.Open(ctx, testStepID))
+	assert.NoError(t, agentServer.Write(ctx, testStepID, []*model.LogEntry{testEntry}))
+	assert.NoError(t, agentServer.Close(ctx, testStepID))
+
+	select {
+	case entries := <-receiver:
+		assert.Equal(t, []*model.LogEntry{testEntry}, entries)
+	case <-time.After(time.Second):
+		t.Fatal("log entries were not received")
+	}
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("tail was not closed")
+	}
+}
@@ -22,6 +22,7 @@ type Config struct {
 	Hash   string `json:"hash" xorm:"UNIQUE(s) 'hash'"`
 	Name   string `json:"name" xorm:"UNIQUE(s) 'name'"`
 	Data   []byte `json:"data" xorm:"LONGBLOB 'data'"`
+	Used   int64  `json:"-"    xorm:"NOT NULL DEFAULT 0 'used'"` // last time the config was persisted for a pipeline
 } //	@name Config
 
 func (Config) TableName() string {
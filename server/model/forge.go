@@ -33,7 +33,7 @@ type Forge struct {
 	InternalURL       string         `json:"internal_url"                 xorm:"VARCHAR(500) 'internal_url'"`
 	InternalClone     bool           `json:"internal_clone"               xorm:"bool 'internal_clone'"`
 	Client            string         `json:"client,omitempty"             xorm:"VARCHAR(250)"`
-	ClientSecret      string         `json:"-"                            xorm:"VARCHAR(250)"` // do not expose client secret
+	ClientSecret      string         `json:"-"                            xorm:"TEXT"` // do not expose client secret
 	SkipVerify        bool           `json:"skip_verify,omitempty"        xorm:"bool"`
 	OAuthHost         string         `json:"oauth_host,omitempty"         xorm:"VARCHAR(250) 'oauth_host'"` // public url for oauth if different from url
 	AdditionalOptions map[string]any `json:"additional_options,omitempty" xorm:"json"`
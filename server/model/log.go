@@ -23,6 +23,9 @@ const (
 	LogEntryExitCode
 	LogEntryMetadata
 	LogEntryProgress
+	LogEntryTruncated
+	LogEntrySectionStart
+	LogEntrySectionEnd
 )
 
 type LogEntry struct {
@@ -0,0 +1,69 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package model
+
+// LogSearchIndex is the search index of the logs of a step.
+type LogSearchIndex struct {
+	StepID int64  `xorm:"pk 'step_id'"`
+	Data   []byte `xorm:"LONGBLOB 'data'"`
+}
+
+func (LogSearchIndex) TableName() string {
+	return "log_search_indexes"
+}
+
+// LogSearchResult contains the pipelines with logs matching a search.
+type LogSearchResult struct {
+	Pipelines []*LogSearchPipeline `json:"pipelines"`
+	// Next is the number of the pipeline to continue the search at, if the
+	// search stopped before all pipelines were searched.
+	Next int64 `json:"next,omitempty"`
+} //	@name LogSearchResult
+
+// LogSearchPipeline is a pipeline with logs matching a search.
+type LogSearchPipeline struct {
+	ID        int64                `json:"id"`
+	Number    int64                `json:"number"`
+	Created   int64                `json:"created"`
+	Status    StatusValue          `json:"status"`
+	Event     WebhookEvent         `json:"event"`
+	Branch    string               `json:"branch"`
+	Workflows []*LogSearchWorkflow `json:"workflows"`
+} //	@name LogSearchPipeline
+
+// LogSearchWorkflow is a workflow with logs matching a search.
+type LogSearchWorkflow struct {
+	ID    int64            `json:"id"`
+	Name  string           `json:"name"`
+	Steps []*LogSearchStep `json:"steps"`
+} //	@name LogSearchWorkflow
+
+// LogSearchStep is a step with logs matching a search.
+type LogSearchStep struct {
+	ID      int64             `json:"id"`
+	Name    string            `json:"name"`
+	Matches []*LogSearchMatch `json:"matches"`
+} //	@name LogSearchStep
+
+// LogSearchMatch is a log line matching a search with the lines around it.
+type LogSearchMatch struct {
+	Line   int      `json:"line"`
+	Text   string   `json:"text"`
+	Before []string `json:"before,omitempty"`
+	After  []string `json:"after,omitempty"`
+} //	@name LogSearchMatch
@@ -21,10 +21,25 @@ type Org struct {
 	Name    string `json:"name"               xorm:"UNIQUE 'name'"`
 	IsUser  bool   `json:"is_user"            xorm:"is_user"`
 	// if name lookup has to check for membership or not
-	Private bool `json:"-"                    xorm:"private"`
+	Private  bool `json:"-"                   xorm:"private"`
+	Priority int  `json:"priority"            xorm:"NOT NULL DEFAULT 0 'priority'"`
+	// fair-share weight of the org in the queue, values lower than one count as one
+	Weight int `json:"weight"                 xorm:"NOT NULL DEFAULT 1 'weight'"`
+	// maximum number of concurrently running workflows, zero means unlimited
+	MaxConcurrent int `json:"max_concurrent"  xorm:"NOT NULL DEFAULT 0 'max_concurrent'"`
+	// maximum bytes of logs stored for the repos of the org, zero means the server default
+	LogQuota int64 `json:"log_quota"           xorm:"NOT NULL DEFAULT 0 'log_quota'"`
 } //	@name Org
 
 // TableName return database table name for xorm.
 func (Org) TableName() string {
 	return "orgs"
 }
+
+// OrgPatch represents an organization patch object.
+type OrgPatch struct {
+	Priority      *int   `json:"priority,omitempty"`
+	Weight        *int   `json:"weight,omitempty"`
+	MaxConcurrent *int   `json:"max_concurrent,omitempty"`
+	LogQuota      *int64 `json:"log_quota,omitempty"`
+} //	@name OrgPatch
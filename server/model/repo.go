@@ -73,6 +73,17 @@ type Repo struct {
 	Perm                         *Perm          `json:"-"                               xorm:"-"`
 	CancelPreviousPipelineEvents []WebhookEvent `json:"cancel_previous_pipeline_events" xorm:"json 'cancel_previous_pipeline_events'"`
 	NetrcOnlyTrusted             bool           `json:"netrc_only_trusted"              xorm:"NOT NULL DEFAULT true 'netrc_only_trusted'"`
+	Priority                     int            `json:"priority"                        xorm:"NOT NULL DEFAULT 0 'priority'"`
+	// maximum number of concurrently running workflows, zero means unlimited
+	MaxConcurrent int `json:"max_concurrent"                  xorm:"NOT NULL DEFAULT 0 'max_concurrent'"`
+	// maximum time in minutes a workflow waits for an agent, zero means the server default
+	MaxPendingAge int64 `json:"max_pending_age"                 xorm:"NOT NULL DEFAULT 0 'max_pending_age'"`
+	// number of latest pipelines per branch kept by the retention, zero means the server default
+	RetentionKeepPerBranch int `json:"retention_keep_per_branch"       xorm:"NOT NULL DEFAULT 0 'retention_keep_per_branch'"`
+	// days after which pipelines are deleted by the retention, zero means the server default
+	RetentionDays int64 `json:"retention_days"                  xorm:"NOT NULL DEFAULT 0 'retention_days'"`
+	// maximum bytes of logs stored for the repo, zero means the server default
+	LogQuota int64 `json:"log_quota"                       xorm:"NOT NULL DEFAULT 0 'log_quota'"`
 } //	@name Repo
 
 // TableName return database table name for xorm.
@@ -140,6 +151,12 @@ type RepoPatch struct {
 	AllowDeploy                  *bool           `json:"allow_deploy,omitempty"`
 	CancelPreviousPipelineEvents *[]WebhookEvent `json:"cancel_previous_pipeline_events"`
 	NetrcOnlyTrusted             *bool           `json:"netrc_only_trusted"`
+	Priority                     *int            `json:"priority,omitempty"`
+	MaxConcurrent                *int            `json:"max_concurrent,omitempty"`
+	MaxPendingAge                *int64          `json:"max_pending_age,omitempty"`
+	RetentionKeepPerBranch       *int            `json:"retention_keep_per_branch,omitempty"`
+	RetentionDays                *int64          `json:"retention_days,omitempty"`
+	LogQuota                     *int64          `json:"log_quota,omitempty"`
 } //	@name RepoPatch
 
 type ForgeRemoteID string
@@ -0,0 +1,51 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package model
+
+// Resources defines amounts of CPU, memory and disk. A zero value means the
+// resource is unknown, so it's not limited.
+type Resources struct {
+	CPU    int64 `json:"cpu"`    // millicores
+	Memory int64 `json:"memory"` // bytes
+	Disk   int64 `json:"disk"`   // bytes
+} //	@name Resources
+
+// IsZero returns true if no resource is set.
+func (r Resources) IsZero() bool {
+	return r.CPU == 0 && r.Memory == 0 && r.Disk == 0
+}
+
+// Add returns the sum of both resources.
+func (r Resources) Add(o Resources) Resources {
+	return Resources{
+		CPU:    r.CPU + o.CPU,
+		Memory: r.Memory + o.Memory,
+		Disk:   r.Disk + o.Disk,
+	}
+}
+
+// Fits returns true if the request fits into the resources that are left
+// after subtracting the used ones. Unknown resources always fit.
+func (r Resources) Fits(used, request Resources) bool {
+	fits := func(total, used, request int64) bool {
+		return total <= 0 || used+request <= total
+	}
+	return fits(r.CPU, used.CPU, request.CPU) &&
+		fits(r.Memory, used.Memory, request.Memory) &&
+		fits(r.Disk, used.Disk, request.Disk)
+}
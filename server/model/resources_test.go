@@ -0,0 +1,35 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourcesFits(t *testing.T) {
+	agent := Resources{CPU: 4000, Memory: 8 << 30}
+
+	assert.True(t, agent.Fits(Resources{}, Resources{CPU: 4000, Memory: 8 << 30}))
+	assert.True(t, agent.Fits(Resources{CPU: 2000}, Resources{CPU: 2000}))
+	assert.False(t, agent.Fits(Resources{CPU: 2000}, Resources{CPU: 2001}))
+	assert.False(t, agent.Fits(Resources{}, Resources{Memory: 9 << 30}))
+	assert.True(t, agent.Fits(Resources{}, Resources{Disk: 100 << 30}), "expect unknown disk to be unlimited")
+	assert.True(t, Resources{}.Fits(Resources{CPU: 64000}, Resources{CPU: 64000}))
+}
@@ -52,6 +52,11 @@ type Secret struct {
 	Value  string         `json:"value,omitempty" xorm:"TEXT 'value'"`
 	Images []string       `json:"images"          xorm:"json 'images'"`
 	Events []WebhookEvent `json:"events"          xorm:"json 'events'"`
+	// the usage is only recorded by SecretRecordUsage when a pipeline referencing the secret starts
+	UsageCount       int64 `json:"usage_count"                  xorm:"NOT NULL DEFAULT 0 'usage_count'"`
+	LastUsedAt       int64 `json:"last_used_at,omitempty"       xorm:"NOT NULL DEFAULT 0 'last_used_at'"`
+	LastUsedRepoID   int64 `json:"last_used_repo_id,omitempty"  xorm:"NOT NULL DEFAULT 0 'last_used_repo_id'"`
+	LastUsedPipeline int64 `json:"last_used_pipeline,omitempty" xorm:"NOT NULL DEFAULT 0 'last_used_pipeline'"`
 } //	@name Secret
 
 // TableName return database table name for xorm.
@@ -129,6 +134,11 @@ func (s *Secret) Copy() *Secret {
 		Name:   s.Name,
 		Images: s.Images,
 		Events: sortEvents(s.Events),
+
+		UsageCount:       s.UsageCount,
+		LastUsedAt:       s.LastUsedAt,
+		LastUsedRepoID:   s.LastUsedRepoID,
+		LastUsedPipeline: s.LastUsedPipeline,
 	}
 }
 
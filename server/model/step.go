@@ -26,19 +26,22 @@ const (
 
 // Step represents a process in the pipeline.
 type Step struct {
-	ID         int64       `json:"id"                   xorm:"pk autoincr 'id'"`
-	UUID       string      `json:"uuid"                 xorm:"INDEX 'uuid'"`
-	PipelineID int64       `json:"pipeline_id"          xorm:"UNIQUE(s) INDEX 'pipeline_id'"`
-	PID        int         `json:"pid"                  xorm:"UNIQUE(s) 'pid'"`
-	PPID       int         `json:"ppid"                 xorm:"ppid"`
-	Name       string      `json:"name"                 xorm:"name"`
-	State      StatusValue `json:"state"                xorm:"state"`
-	Error      string      `json:"error,omitempty"      xorm:"TEXT 'error'"`
-	Failure    string      `json:"-"                    xorm:"failure"`
-	ExitCode   int         `json:"exit_code"            xorm:"exit_code"`
-	Started    int64       `json:"start_time,omitempty" xorm:"started"`
-	Finished   int64       `json:"end_time,omitempty"   xorm:"stopped"`
-	Type       StepType    `json:"type,omitempty"       xorm:"type"`
+	ID           int64       `json:"id"                      xorm:"pk autoincr 'id'"`
+	UUID         string      `json:"uuid"                    xorm:"INDEX 'uuid'"`
+	PipelineID   int64       `json:"pipeline_id"             xorm:"UNIQUE(s) INDEX 'pipeline_id'"`
+	PID          int         `json:"pid"                     xorm:"UNIQUE(s) 'pid'"`
+	PPID         int         `json:"ppid"                    xorm:"ppid"`
+	Name         string      `json:"name"                    xorm:"name"`
+	State        StatusValue `json:"state"                   xorm:"state"`
+	Error        string      `json:"error,omitempty"         xorm:"TEXT 'error'"`
+	Failure      string      `json:"-"                       xorm:"failure"`
+	ExitCode     int         `json:"exit_code"               xorm:"exit_code"`
+	Started      int64       `json:"start_time,omitempty"    xorm:"started"`
+	Finished     int64       `json:"end_time,omitempty"      xorm:"stopped"`
+	Type         StepType    `json:"type,omitempty"          xorm:"type"`
+	LogTruncated bool        `json:"log_truncated,omitempty" xorm:"log_truncated"`
+	LogSize      int64       `json:"log_size,omitempty"      xorm:"NOT NULL DEFAULT 0 'log_size'"`
+	LogLines     int64       `json:"-"                       xorm:"NOT NULL DEFAULT 0 'log_lines'"`
 } //	@name Step
 
 // TableName return database table name for xorm.
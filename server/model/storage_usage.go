@@ -0,0 +1,29 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package model
+
+// StorageUsage represents the log storage used by a repo or org.
+type StorageUsage struct {
+	LogBytes int64 `json:"log_bytes"`
+	// zero means unlimited
+	LogQuota int64 `json:"log_quota"`
+	// usage reached the warning threshold of the quota
+	Warning bool `json:"warning"`
+	// usage reached the quota
+	Exceeded bool `json:"exceeded"`
+} //	@name StorageUsage
@@ -21,15 +21,30 @@ import (
 
 // Task defines scheduled pipeline Task.
 type Task struct {
-	ID           string                 `json:"id"           xorm:"PK UNIQUE 'id'"`
-	Data         []byte                 `json:"data"         xorm:"LONGBLOB 'data'"`
-	Labels       map[string]string      `json:"labels"       xorm:"json 'labels'"`
-	Dependencies []string               `json:"dependencies" xorm:"json 'dependencies'"`
-	RunOn        []string               `json:"run_on"       xorm:"json 'run_on'"`
-	DepStatus    map[string]StatusValue `json:"dep_status"   xorm:"json 'dependencies_status'"`
-	AgentID      int64                  `json:"agent_id"     xorm:"'agent_id'"`
+	ID               string                 `json:"id"                          xorm:"PK UNIQUE 'id'"`
+	Data             []byte                 `json:"data"                        xorm:"LONGBLOB 'data'"`
+	Labels           map[string]string      `json:"labels"                      xorm:"json 'labels'"`
+	Dependencies     []string               `json:"dependencies"                xorm:"json 'dependencies'"`
+	RunOn            []string               `json:"run_on"                      xorm:"json 'run_on'"`
+	DepStatus        map[string]StatusValue `json:"dep_status"                  xorm:"json 'dependencies_status'"`
+	AgentID          int64                  `json:"agent_id"                    xorm:"'agent_id'"`
+	Priority         int                    `json:"priority"                    xorm:"'priority'"`
+	RepoID           int64                  `json:"repo_id"                     xorm:"'repo_id'"`
+	OrgID            int64                  `json:"org_id"                      xorm:"'org_id'"`
+	ConcurrencyGroup string                 `json:"concurrency_group,omitempty" xorm:"'concurrency_group'"`
+	Resources        Resources              `json:"resources"                   xorm:"json 'resources'"`
+	LeaseOwner       string                 `json:"lease_owner,omitempty"       xorm:"'lease_owner'"`
+	LeaseExpires     int64                  `json:"lease_expires,omitempty"     xorm:"NOT NULL DEFAULT 0 'lease_expires'"`
+	Created          int64                  `json:"created,omitempty"           xorm:"'created'"`
 } //	@name Task
 
+// Task priorities, tasks with a higher priority are handed out to agents first.
+const (
+	TaskPriorityLow    = 0
+	TaskPriorityNormal = 50
+	TaskPriorityHigh   = 100
+)
+
 // TableName return database table name for xorm.
 func (Task) TableName() string {
 	return "tasks"
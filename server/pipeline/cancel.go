@@ -23,6 +23,7 @@ import (
 	"go.woodpecker-ci.org/woodpecker/v2/server"
 	"go.woodpecker-ci.org/woodpecker/v2/server/forge"
 	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/pipeline/stepbuilder"
 	"go.woodpecker-ci.org/woodpecker/v2/server/queue"
 	"go.woodpecker-ci.org/woodpecker/v2/server/store"
 )
@@ -163,3 +164,60 @@ func cancelPreviousPipelines(
 
 	return nil
 }
+
+// cancelConcurrencyGroups cancels older active pipelines that have a workflow in the
+// concurrency group of a workflow of the new pipeline with cancel_in_progress set.
+func cancelConcurrencyGroups(
+	ctx context.Context,
+	_forge forge.Forge,
+	_store store.Store,
+	pipeline *model.Pipeline,
+	repo *model.Repo,
+	user *model.User,
+	pipelineItems []*stepbuilder.Item,
+) error {
+	groups := make(map[string]bool)
+	for _, item := range pipelineItems {
+		if item.CancelInProgress && item.Workflow.ConcurrencyGroup != "" && item.Workflow.State != model.StatusSkipped {
+			groups[item.Workflow.ConcurrencyGroup] = true
+		}
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+
+	activePipelines, err := _store.GetActivePipelineList(repo)
+	if err != nil {
+		return err
+	}
+
+	for _, active := range activePipelines {
+		if active.Number >= pipeline.Number {
+			// same or newer pipeline
+			continue
+		}
+
+		workflows, err := _store.WorkflowGetTree(active)
+		if err != nil {
+			log.Error().Err(err).Int64("id", active.ID).Msg("failed to get workflows of pipeline")
+			continue
+		}
+
+		for _, workflow := range workflows {
+			if !workflow.Running() || !groups[workflow.ConcurrencyGroup] {
+				continue
+			}
+
+			if err = Cancel(ctx, _forge, _store, repo, user, active); err != nil {
+				log.Error().
+					Err(err).
+					Str("ref", active.Ref).
+					Int64("id", active.ID).
+					Msg("failed to cancel pipeline")
+			}
+			break
+		}
+	}
+
+	return nil
+}
@@ -92,6 +92,12 @@ func Create(ctx context.Context, _store store.Store, repo *model.Repo, pipeline
 		return nil, msg
 	}
 
+	quotaWarnings, quotaErr := checkLogQuotas(_store, repo)
+	if quotaErr != nil {
+		log.Debug().Str("repo", repo.FullName).Err(quotaErr).Msg("log quota exceeded")
+		return pipeline, updatePipelineWithErr(ctx, _forge, _store, pipeline, repo, repoUser, quotaErr)
+	}
+
 	// fetch the pipeline file from the forge
 	configService := server.Config.Services.Manager.ConfigServiceFromRepo(repo)
 	forgeConfigs, configFetchErr := configService.Fetch(ctx, _forge, repoUser, repo, pipeline, nil, false)
@@ -114,6 +120,7 @@ func Create(ctx context.Context, _store store.Store, repo *model.Repo, pipeline
 	} else if parseErr != nil {
 		pipeline.Errors = pipeline_errors.GetPipelineErrors(parseErr)
 	}
+	pipeline.Errors = append(pipeline.Errors, quotaWarnings...)
 
 	if len(pipelineItems) == 0 {
 		log.Debug().Str("repo", repo.FullName).Msg(ErrFiltered.Error())
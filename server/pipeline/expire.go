@@ -0,0 +1,186 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"go.woodpecker-ci.org/woodpecker/v2/pipeline/errors/types"
+	"go.woodpecker-ci.org/woodpecker/v2/server"
+	"go.woodpecker-ci.org/woodpecker/v2/server/forge"
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/queue"
+	"go.woodpecker-ci.org/woodpecker/v2/server/store"
+)
+
+// ExpirePendingWorkflows fails the workflows that wait longer than the maximum
+// pending age of their repo for an agent and evicts them from the queue. Repos
+// without a maximum pending age use the given default, zero disables expiry.
+func ExpirePendingWorkflows(ctx context.Context, _store store.Store, defaultMaxAge time.Duration) error {
+	info := server.Config.Services.Queue.Info(ctx)
+	if info.Paused {
+		return nil
+	}
+
+	for _, e := range expiredWorkflows(info, _store.GetRepo, defaultMaxAge, time.Now()) {
+		if err := expireWorkflow(ctx, _store, e.repo, e.task, e.maxAge); err != nil {
+			log.Error().Err(err).Str("repo", e.repo.FullName).Str("workflow_id", e.task.ID).Msg("cannot expire pending workflow")
+		}
+	}
+
+	return nil
+}
+
+type expiredWorkflow struct {
+	task   *model.Task
+	repo   *model.Repo
+	maxAge time.Duration
+}
+
+// expiredWorkflows returns the pending tasks that wait longer than the maximum
+// pending age of their repo. Tasks whose repo can't be loaded are skipped.
+func expiredWorkflows(info queue.InfoT, getRepo func(int64) (*model.Repo, error), defaultMaxAge time.Duration, now time.Time) []expiredWorkflow {
+	var expired []expiredWorkflow
+	repos := make(map[int64]*model.Repo)
+	for _, task := range info.Pending {
+		since, ok := info.PendingSince[task.ID]
+		if !ok {
+			continue
+		}
+
+		repo, ok := repos[task.RepoID]
+		if !ok {
+			var err error
+			if repo, err = getRepo(task.RepoID); err != nil {
+				log.Error().Err(err).Int64("repo_id", task.RepoID).Str("workflow_id", task.ID).Msg("cannot get repo of pending workflow")
+				continue
+			}
+			repos[task.RepoID] = repo
+		}
+
+		maxAge := defaultMaxAge
+		if repo.MaxPendingAge > 0 {
+			maxAge = time.Duration(repo.MaxPendingAge) * time.Minute
+		}
+		if maxAge <= 0 || now.Sub(since) < maxAge {
+			continue
+		}
+
+		expired = append(expired, expiredWorkflow{task: task, repo: repo, maxAge: maxAge})
+	}
+	return expired
+}
+
+func expireWorkflow(ctx context.Context, _store store.Store, repo *model.Repo, task *model.Task, maxAge time.Duration) error {
+	if err := server.Config.Services.Queue.EvictAtOnce(ctx, []string{task.ID}); err != nil {
+		if errors.Is(err, queue.ErrNotFound) {
+			// picked up by an agent in the meantime
+			return nil
+		}
+		return err
+	}
+
+	msg := fmt.Sprintf("no agent picked up the workflow within %s", maxAge)
+	if len(task.Labels) != 0 {
+		msg = fmt.Sprintf("no agent matched labels %s within %s", formatLabels(task.Labels), maxAge)
+	}
+	// update the dependency status of the other workflows
+	if err := server.Config.Services.Queue.ErrorAtOnce(ctx, []string{task.ID}, errors.New(msg)); err != nil {
+		log.Error().Err(err).Msgf("queue: error_at_once: %v", task.ID)
+	}
+
+	workflowID, err := strconv.ParseInt(task.ID, 10, 64)
+	if err != nil {
+		return err
+	}
+	workflow, err := _store.WorkflowLoad(workflowID)
+	if err != nil {
+		return err
+	}
+	pipeline, err := _store.GetPipeline(workflow.PipelineID)
+	if err != nil {
+		return err
+	}
+
+	finished := time.Now().Unix()
+	if _, err := UpdateWorkflowToStatusExpired(_store, *workflow, msg, finished); err != nil {
+		return err
+	}
+	steps, err := _store.StepListFromWorkflowFind(workflow)
+	if err != nil {
+		return err
+	}
+	for _, step := range steps {
+		if step.State == model.StatusPending {
+			if _, err := UpdateStepToStatusSkipped(_store, *step, finished); err != nil {
+				log.Error().Err(err).Msgf("cannot update step with id %d state", step.ID)
+			}
+		}
+	}
+
+	if pipeline.Workflows, err = _store.WorkflowGetTree(pipeline); err != nil {
+		return err
+	}
+	pipeline.Errors = append(pipeline.Errors, &types.PipelineError{
+		Type:    types.PipelineErrorTypeGeneric,
+		Message: fmt.Sprintf("workflow '%s' expired: %s", workflow.Name, msg),
+	})
+	if !model.IsThereRunningStage(pipeline.Workflows) {
+		pipeline.Status = model.PipelineStatus(pipeline.Workflows)
+		pipeline.Finished = finished
+	}
+	if err := _store.UpdatePipeline(pipeline); err != nil {
+		return err
+	}
+
+	user, err := _store.GetUser(repo.UserID)
+	if err != nil {
+		return err
+	}
+	_forge, err := server.Config.Services.Manager.ForgeFromRepo(repo)
+	if err != nil {
+		return err
+	}
+	forge.Refresh(ctx, _forge, _store, user)
+	updatePipelineStatus(ctx, _forge, pipeline, repo, user)
+	publishToTopic(pipeline, repo)
+
+	return nil
+}
+
+func formatLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+labels[key])
+	}
+	return strings.Join(pairs, ", ")
+}
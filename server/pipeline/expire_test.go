@@ -0,0 +1,97 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package pipeline
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/queue"
+)
+
+func TestExpiredWorkflows(t *testing.T) {
+	now := time.Now()
+	repos := map[int64]*model.Repo{
+		1: {ID: 1, FullName: "org/default"},
+		2: {ID: 2, FullName: "org/custom", MaxPendingAge: 5},
+	}
+	getRepo := func(id int64) (*model.Repo, error) {
+		if repo, ok := repos[id]; ok {
+			return repo, nil
+		}
+		return nil, errors.New("repo not found")
+	}
+
+	tests := []struct {
+		name          string
+		repoID        int64
+		age           time.Duration
+		defaultMaxAge time.Duration
+		expired       bool
+		maxAge        time.Duration
+	}{
+		{name: "expired with default max age", repoID: 1, age: 2 * time.Hour, defaultMaxAge: time.Hour, expired: true, maxAge: time.Hour},
+		{name: "not yet expired with default max age", repoID: 1, age: 30 * time.Minute, defaultMaxAge: time.Hour},
+		{name: "expiry disabled", repoID: 1, age: 2 * time.Hour},
+		{name: "expired with repo max age", repoID: 2, age: 10 * time.Minute, defaultMaxAge: time.Hour, expired: true, maxAge: 5 * time.Minute},
+		{name: "not yet expired with repo max age", repoID: 2, age: time.Minute, defaultMaxAge: time.Second},
+		{name: "repo lookup failure", repoID: 3, age: 2 * time.Hour, defaultMaxAge: time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &model.Task{ID: "1", RepoID: tt.repoID}
+			info := queue.InfoT{
+				Pending:      []*model.Task{task},
+				PendingSince: map[string]time.Time{task.ID: now.Add(-tt.age)},
+			}
+
+			expired := expiredWorkflows(info, getRepo, tt.defaultMaxAge, now)
+			if !tt.expired {
+				assert.Empty(t, expired)
+				return
+			}
+			if assert.Len(t, expired, 1) {
+				assert.Equal(t, task, expired[0].task)
+				assert.Equal(t, repos[tt.repoID], expired[0].repo)
+				assert.Equal(t, tt.maxAge, expired[0].maxAge)
+			}
+		})
+	}
+
+	t.Run("repo lookup failure does not stop the pass", func(t *testing.T) {
+		failing := &model.Task{ID: "1", RepoID: 3}
+		pending := &model.Task{ID: "2", RepoID: 1}
+		info := queue.InfoT{
+			Pending: []*model.Task{failing, pending},
+			PendingSince: map[string]time.Time{
+				failing.ID: now.Add(-2 * time.Hour),
+				pending.ID: now.Add(-2 * time.Hour),
+			},
+		}
+
+		expired := expiredWorkflows(info, getRepo, time.Hour, now)
+		if assert.Len(t, expired, 1) {
+			assert.Equal(t, pending, expired[0].task)
+		}
+	})
+}
@@ -34,6 +34,8 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+
 	"github.com/rs/zerolog/log"
 
 	pipeline_errors "go.woodpecker-ci.org/woodpecker/v2/pipeline/errors"
@@ -59,9 +61,12 @@ func parsePipeline(forge forge.Forge, store store.Store, currentPipeline *model.
 	}
 
 	secretService := server.Config.Services.Manager.SecretServiceFromRepo(repo)
-	secs, err := secretService.SecretListPipeline(repo, currentPipeline)
+	secs, err := secretService.SecretListPipeline(repo, currentPipeline, stepbuilder.RequestedSecrets(configs))
 	if err != nil {
 		log.Error().Err(err).Msgf("error getting secrets for %s#%d", repo.FullName, currentPipeline.Number)
+		// running without the secrets would let steps fail in confusing ways or
+		// behave differently, e.g. skip a deployment
+		return nil, fmt.Errorf("could not load secrets: %w", err)
 	}
 
 	registryService := server.Config.Services.Manager.RegistryServiceFromRepo(repo)
@@ -31,11 +31,22 @@ This file incorporates work covered by the following copyright and permission no
 package pipeline
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
 	"go.woodpecker-ci.org/woodpecker/v2/pipeline/backend/types"
+	pipeline_errors "go.woodpecker-ci.org/woodpecker/v2/pipeline/errors"
+	"go.woodpecker-ci.org/woodpecker/v2/server"
+	mocks_forge "go.woodpecker-ci.org/woodpecker/v2/server/forge/mocks"
+	forge_types "go.woodpecker-ci.org/woodpecker/v2/server/forge/types"
 	"go.woodpecker-ci.org/woodpecker/v2/server/model"
 	sharedPipeline "go.woodpecker-ci.org/woodpecker/v2/server/pipeline/stepbuilder"
+	mocks_manager "go.woodpecker-ci.org/woodpecker/v2/server/services/mocks"
+	mocks_secret "go.woodpecker-ci.org/woodpecker/v2/server/services/secret/mocks"
+	mocks_store "go.woodpecker-ci.org/woodpecker/v2/server/store/mocks"
 )
 
 func TestSetPipelineStepsOnPipeline(t *testing.T) {
@@ -80,3 +91,25 @@ func TestSetPipelineStepsOnPipeline(t *testing.T) {
 		t.Fatal("Should set step PPID")
 	}
 }
+
+func TestParsePipelineSecretsError(t *testing.T) {
+	repo := &model.Repo{ID: 1, FullName: "octocat/hello-world"}
+	user := &model.User{ID: 1}
+	pipeline := &model.Pipeline{ID: 2, Number: 1, Branch: "main", Event: model.EventPush}
+	configs := []*forge_types.FileMeta{{Name: ".woodpecker.yaml", Data: []byte("steps:\n  build:\n    image: alpine\n")}}
+
+	_forge := mocks_forge.NewForge(t)
+	_forge.On("Netrc", user, repo).Return(&model.Netrc{}, nil)
+	_store := mocks_store.NewStore(t)
+	_store.On("GetPipelineLastBefore", repo, pipeline.Branch, pipeline.ID).Return(nil, nil)
+	secretService := mocks_secret.NewService(t)
+	secretService.On("SecretListPipeline", repo, pipeline, mock.Anything).Return(nil, errors.New("extension unavailable"))
+	_manager := mocks_manager.NewManager(t)
+	_manager.On("SecretServiceFromRepo", repo).Return(secretService)
+	server.Config.Services.Manager = _manager
+
+	items, err := parsePipeline(_forge, _store, pipeline, user, repo, configs, nil)
+	assert.Nil(t, items)
+	assert.ErrorContains(t, err, "extension unavailable")
+	assert.True(t, pipeline_errors.HasBlockingErrors(err))
+}
@@ -18,14 +18,24 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
 
 	"go.woodpecker-ci.org/woodpecker/v2/pipeline/rpc"
 	"go.woodpecker-ci.org/woodpecker/v2/server"
 	"go.woodpecker-ci.org/woodpecker/v2/server/model"
 	"go.woodpecker-ci.org/woodpecker/v2/server/pipeline/stepbuilder"
+	"go.woodpecker-ci.org/woodpecker/v2/server/store"
 )
 
-func queuePipeline(ctx context.Context, repo *model.Repo, pipelineItems []*stepbuilder.Item) error {
+func queuePipeline(ctx context.Context, store store.Store, activePipeline *model.Pipeline, repo *model.Repo, pipelineItems []*stepbuilder.Item) error {
+	org, err := store.OrgGet(repo.OrgID)
+	if err != nil {
+		log.Error().Err(err).Str("repo", repo.FullName).Msg("could not get org to determine task priority")
+		org = &model.Org{}
+	}
+
 	var tasks []*model.Task
 	for _, item := range pipelineItems {
 		if item.Workflow.State == model.StatusSkipped {
@@ -38,11 +48,16 @@ func queuePipeline(ctx context.Context, repo *model.Repo, pipelineItems []*stepb
 			task.Labels[k] = v
 		}
 		task.Labels["repo"] = repo.FullName
+		task.RepoID = repo.ID
+		task.OrgID = repo.OrgID
+		task.ConcurrencyGroup = item.Workflow.ConcurrencyGroup
+		task.Resources = item.Resources
 		task.Dependencies = taskIDs(item.DependsOn, pipelineItems)
 		task.RunOn = item.RunsOn
 		task.DepStatus = make(map[string]model.StatusValue)
+		task.Priority = taskPriority(activePipeline.Event, item.Priority, repo.Priority+org.Priority)
+		task.Created = time.Now().Unix()
 
-		var err error
 		task.Data, err = json.Marshal(rpc.Workflow{
 			ID:      fmt.Sprint(item.Workflow.ID),
 			Config:  item.Config,
@@ -67,3 +82,21 @@ func taskIDs(dependsOn []string, pipelineItems []*stepbuilder.Item) (taskIDs []s
 	}
 	return
 }
+
+// taskPriority returns the base priority of a workflow task. An explicit priority
+// from the workflow config wins over the default priority of the pipeline event.
+// The offset of the repo and org priority is added and the sum is kept within
+// TaskPriorityLow and TaskPriorityHigh.
+func taskPriority(event model.WebhookEvent, configured *int, offset int) int {
+	priority := model.TaskPriorityNormal
+	switch {
+	case configured != nil:
+		priority = *configured
+	case event == model.EventDeploy, event == model.EventPush:
+		priority = model.TaskPriorityHigh
+	case event == model.EventCron:
+		priority = model.TaskPriorityLow
+	}
+
+	return min(max(priority+offset, model.TaskPriorityLow), model.TaskPriorityHigh)
+}
@@ -0,0 +1,101 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+)
+
+func TestTaskPriority(t *testing.T) {
+	t.Parallel()
+
+	intPtr := func(i int) *int { return &i }
+
+	testCases := []struct {
+		name       string
+		event      model.WebhookEvent
+		configured *int
+		offset     int
+		expected   int
+	}{
+		{
+			name:     "push is high priority",
+			event:    model.EventPush,
+			expected: model.TaskPriorityHigh,
+		},
+		{
+			name:     "deployment is high priority",
+			event:    model.EventDeploy,
+			expected: model.TaskPriorityHigh,
+		},
+		{
+			name:     "cron is low priority",
+			event:    model.EventCron,
+			expected: model.TaskPriorityLow,
+		},
+		{
+			name:     "pull request is normal priority",
+			event:    model.EventPull,
+			expected: model.TaskPriorityNormal,
+		},
+		{
+			name:       "configured priority wins over event",
+			event:      model.EventCron,
+			configured: intPtr(80),
+			expected:   80,
+		},
+		{
+			name:       "configured priority is capped",
+			event:      model.EventPush,
+			configured: intPtr(1000),
+			expected:   model.TaskPriorityHigh,
+		},
+		{
+			name:       "configured priority is floored",
+			event:      model.EventPush,
+			configured: intPtr(-5),
+			expected:   model.TaskPriorityLow,
+		},
+		{
+			name:     "repo and org offset is added",
+			event:    model.EventPull,
+			offset:   20,
+			expected: model.TaskPriorityNormal + 20,
+		},
+		{
+			name:     "offset can not exceed the highest priority",
+			event:    model.EventPush,
+			offset:   50,
+			expected: model.TaskPriorityHigh,
+		},
+		{
+			name:     "offset can not go below the lowest priority",
+			event:    model.EventCron,
+			offset:   -50,
+			expected: model.TaskPriorityLow,
+		},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, taskPriority(tc.event, tc.configured, tc.offset), tc.name)
+	}
+}
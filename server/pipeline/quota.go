@@ -0,0 +1,288 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package pipeline
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/rs/zerolog/log"
+
+	"go.woodpecker-ci.org/woodpecker/v2/pipeline/errors/types"
+	"go.woodpecker-ci.org/woodpecker/v2/server"
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/store"
+)
+
+const (
+	// LogQuotaActionBlock fails new pipelines of repos exceeding a log quota.
+	LogQuotaActionBlock = "block"
+	// LogQuotaActionPurge deletes the oldest logs of repos exceeding a log quota.
+	LogQuotaActionPurge = "purge"
+)
+
+// orgLogQuotasCacheTTL is how long it is cached whether any org has its own
+// log quota, so that pipelines of instances without quotas don't query them.
+const orgLogQuotasCacheTTL = time.Minute
+
+var orgLogQuotas struct {
+	sync.Mutex
+	exists  bool
+	checked time.Time
+}
+
+// ResetOrgLogQuotasCache makes the next pipeline check again whether any org
+// has its own log quota. It is called when the quota of an org changes.
+func ResetOrgLogQuotasCache() {
+	orgLogQuotas.Lock()
+	defer orgLogQuotas.Unlock()
+	orgLogQuotas.checked = time.Time{}
+}
+
+func orgLogQuotaExists(_store store.Store) (bool, error) {
+	orgLogQuotas.Lock()
+	defer orgLogQuotas.Unlock()
+	if time.Since(orgLogQuotas.checked) < orgLogQuotasCacheTTL {
+		return orgLogQuotas.exists, nil
+	}
+	exists, err := _store.OrgLogQuotaExists()
+	if err != nil {
+		return false, err
+	}
+	orgLogQuotas.exists, orgLogQuotas.checked = exists, time.Now()
+	return exists, nil
+}
+
+// RepoStorageUsage returns the log storage used by the repo.
+func RepoStorageUsage(_store store.Store, repo *model.Repo) (*model.StorageUsage, error) {
+	size, err := _store.RepoLogSize(repo.ID)
+	if err != nil {
+		return nil, err
+	}
+	return storageUsage(size, repoLogQuota(repo)), nil
+}
+
+// OrgStorageUsage returns the log storage used by all repos of the org.
+func OrgStorageUsage(_store store.Store, org *model.Org) (*model.StorageUsage, error) {
+	size, err := _store.OrgLogSize(org.ID)
+	if err != nil {
+		return nil, err
+	}
+	return storageUsage(size, orgLogQuota(org)), nil
+}
+
+func repoLogQuota(repo *model.Repo) int64 {
+	if repo.LogQuota > 0 {
+		return repo.LogQuota
+	}
+	return server.Config.Pipeline.LogQuota.Repo
+}
+
+func orgLogQuota(org *model.Org) int64 {
+	if org.LogQuota > 0 {
+		return org.LogQuota
+	}
+	return server.Config.Pipeline.LogQuota.Org
+}
+
+func storageUsage(size, quota int64) *model.StorageUsage {
+	usage := &model.StorageUsage{LogBytes: size, LogQuota: quota}
+	if quota > 0 {
+		warnPercent := int64(server.Config.Pipeline.LogQuota.WarnPercent)
+		usage.Exceeded = size >= quota
+		usage.Warning = usage.Exceeded || (warnPercent > 0 && size*100 >= quota*warnPercent)
+	}
+	return usage
+}
+
+// checkLogQuotas checks the log quotas of the repo and its org. It returns an
+// error if a quota is exceeded and new pipelines are blocked, and warnings for
+// quotas close to their limit. Failures to compute the usage never block.
+func checkLogQuotas(_store store.Store, repo *model.Repo) ([]*types.PipelineError, error) {
+	if repoLogQuota(repo) <= 0 && server.Config.Pipeline.LogQuota.Org <= 0 {
+		exists, err := orgLogQuotaExists(_store)
+		if err != nil {
+			log.Error().Err(err).Str("repo", repo.FullName).Msg("cannot check for org log quotas")
+			return nil, nil
+		}
+		if !exists {
+			return nil, nil
+		}
+	}
+
+	type quota struct {
+		owner string
+		usage func() (*model.StorageUsage, error)
+	}
+	quotas := []quota{{
+		owner: "repo " + repo.FullName,
+		usage: func() (*model.StorageUsage, error) {
+			if repoLogQuota(repo) <= 0 {
+				return &model.StorageUsage{}, nil
+			}
+			return RepoStorageUsage(_store, repo)
+		},
+	}, {
+		owner: "org " + repo.Owner,
+		usage: func() (*model.StorageUsage, error) {
+			org, err := _store.OrgGet(repo.OrgID)
+			if err != nil {
+				return nil, err
+			}
+			if orgLogQuota(org) <= 0 {
+				return &model.StorageUsage{}, nil
+			}
+			return OrgStorageUsage(_store, org)
+		},
+	}}
+
+	var warnings []*types.PipelineError
+	for _, q := range quotas {
+		usage, err := q.usage()
+		if err != nil {
+			log.Error().Err(err).Str("repo", repo.FullName).Msgf("cannot check log quota of %s", q.owner)
+			continue
+		}
+		if usage.Exceeded && server.Config.Pipeline.LogQuota.Action == LogQuotaActionBlock {
+			return nil, fmt.Errorf("log storage of %s exceeds its quota (%s of %s)",
+				q.owner, units.BytesSize(float64(usage.LogBytes)), units.BytesSize(float64(usage.LogQuota)))
+		}
+		if usage.Warning {
+			warnings = append(warnings, &types.PipelineError{
+				Type: types.PipelineErrorTypeGeneric,
+				Message: fmt.Sprintf("log storage of %s uses %s of its %s quota",
+					q.owner, units.BytesSize(float64(usage.LogBytes)), units.BytesSize(float64(usage.LogQuota))),
+				IsWarning: true,
+			})
+		}
+	}
+	return warnings, nil
+}
+
+// PurgeLogQuotas deletes the logs of the oldest pipelines of repos and orgs
+// exceeding their log quota until they are within it again and returns the
+// number of steps whose logs were deleted.
+func PurgeLogQuotas(ctx context.Context, _store store.Store) (int, error) {
+	orgs, err := _store.OrgList(&model.ListOptions{All: true})
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, org := range orgs {
+		if err := ctx.Err(); err != nil {
+			return purged, err
+		}
+
+		repos, err := _store.OrgRepoList(org, &model.ListOptions{All: true})
+		if err != nil {
+			return purged, err
+		}
+
+		// repo quotas first, purging them might bring the org within its quota
+		for _, repo := range repos {
+			quota := repoLogQuota(repo)
+			if quota <= 0 {
+				continue
+			}
+			size, err := _store.RepoLogSize(repo.ID)
+			if err != nil {
+				return purged, err
+			}
+			n, err := purgeLogs(_store, []*model.Repo{repo}, size-quota)
+			purged += n
+			if err != nil {
+				return purged, err
+			}
+		}
+
+		quota := orgLogQuota(org)
+		if quota <= 0 {
+			continue
+		}
+		size, err := _store.OrgLogSize(org.ID)
+		if err != nil {
+			return purged, err
+		}
+		n, err := purgeLogs(_store, repos, size-quota)
+		purged += n
+		if err != nil {
+			return purged, err
+		}
+	}
+
+	return purged, nil
+}
+
+// purgeLogs deletes the logs of the oldest finished pipelines of the repos until
+// at least the given number of bytes is freed.
+func purgeLogs(_store store.Store, repos []*model.Repo, excess int64) (int, error) {
+	if excess <= 0 {
+		return 0, nil
+	}
+
+	var pipelines []*model.Pipeline
+	for _, repo := range repos {
+		list, err := _store.GetPipelineList(repo, &model.ListOptions{All: true}, nil)
+		if err != nil {
+			return 0, err
+		}
+		pipelines = append(pipelines, list...)
+	}
+	slices.SortFunc(pipelines, func(a, b *model.Pipeline) int {
+		return cmp.Compare(a.Created, b.Created)
+	})
+
+	purged := 0
+	for _, pipeline := range pipelines {
+		if excess <= 0 {
+			break
+		}
+		switch pipeline.Status {
+		case model.StatusPending, model.StatusRunning, model.StatusBlocked:
+			continue
+		}
+
+		steps, err := _store.StepList(pipeline)
+		if err != nil {
+			return purged, err
+		}
+		for _, step := range steps {
+			if step.LogSize == 0 {
+				continue
+			}
+			if err := server.Config.Services.LogStore.LogDelete(step); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return purged, err
+			}
+			size := step.LogSize
+			if err := _store.StepResetLogSize(step); err != nil {
+				return purged, err
+			}
+			excess -= size
+			purged++
+		}
+	}
+	return purged, nil
+}
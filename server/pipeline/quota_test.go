@@ -0,0 +1,125 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server"
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/store/mocks"
+)
+
+func setLogQuota(t *testing.T, repo, org int64, action string) {
+	old := server.Config.Pipeline.LogQuota
+	t.Cleanup(func() { server.Config.Pipeline.LogQuota = old })
+	server.Config.Pipeline.LogQuota.Repo = repo
+	server.Config.Pipeline.LogQuota.Org = org
+	server.Config.Pipeline.LogQuota.WarnPercent = 80
+	server.Config.Pipeline.LogQuota.Action = action
+	ResetOrgLogQuotasCache()
+}
+
+func TestStorageUsage(t *testing.T) {
+	setLogQuota(t, 0, 0, LogQuotaActionBlock)
+
+	assert.Equal(t, &model.StorageUsage{LogBytes: 10}, storageUsage(10, 0))
+	assert.Equal(t, &model.StorageUsage{LogBytes: 79, LogQuota: 100}, storageUsage(79, 100))
+	assert.Equal(t, &model.StorageUsage{LogBytes: 80, LogQuota: 100, Warning: true}, storageUsage(80, 100))
+	assert.Equal(t, &model.StorageUsage{LogBytes: 100, LogQuota: 100, Warning: true, Exceeded: true}, storageUsage(100, 100))
+}
+
+func TestCheckLogQuotas(t *testing.T) {
+	repo := &model.Repo{ID: 1, OrgID: 2, FullName: "octocat/hello-world", Owner: "octocat"}
+	org := &model.Org{ID: 2, Name: "octocat"}
+
+	t.Run("no quotas", func(t *testing.T) {
+		setLogQuota(t, 0, 0, LogQuotaActionBlock)
+		s := mocks.NewStore(t)
+		s.On("OrgLogQuotaExists").Return(false, nil).Once()
+
+		warnings, err := checkLogQuotas(s, repo)
+		assert.NoError(t, err)
+		assert.Empty(t, warnings)
+
+		// cached, no further queries
+		warnings, err = checkLogQuotas(s, repo)
+		assert.NoError(t, err)
+		assert.Empty(t, warnings)
+	})
+
+	t.Run("org quota without defaults", func(t *testing.T) {
+		setLogQuota(t, 0, 0, LogQuotaActionBlock)
+		s := mocks.NewStore(t)
+		s.On("OrgLogQuotaExists").Return(true, nil)
+		s.On("OrgGet", org.ID).Return(&model.Org{ID: 2, Name: "octocat", LogQuota: 1000}, nil)
+		s.On("OrgLogSize", org.ID).Return(int64(1000), nil)
+
+		_, err := checkLogQuotas(s, repo)
+		assert.EqualError(t, err, "log storage of org octocat exceeds its quota (1000B of 1000B)")
+	})
+
+	t.Run("warning", func(t *testing.T) {
+		setLogQuota(t, 100, 1000, LogQuotaActionBlock)
+		s := mocks.NewStore(t)
+		s.On("RepoLogSize", repo.ID).Return(int64(90), nil)
+		s.On("OrgGet", org.ID).Return(org, nil)
+		s.On("OrgLogSize", org.ID).Return(int64(100), nil)
+
+		warnings, err := checkLogQuotas(s, repo)
+		assert.NoError(t, err)
+		if assert.Len(t, warnings, 1) {
+			assert.True(t, warnings[0].IsWarning)
+			assert.Equal(t, "log storage of repo octocat/hello-world uses 90B of its 100B quota", warnings[0].Message)
+		}
+	})
+
+	t.Run("block", func(t *testing.T) {
+		setLogQuota(t, 0, 1000, LogQuotaActionBlock)
+		s := mocks.NewStore(t)
+		s.On("OrgGet", org.ID).Return(org, nil)
+		s.On("OrgLogSize", org.ID).Return(int64(1000), nil)
+
+		_, err := checkLogQuotas(s, repo)
+		assert.EqualError(t, err, "log storage of org octocat exceeds its quota (1000B of 1000B)")
+	})
+
+	t.Run("purge does not block", func(t *testing.T) {
+		setLogQuota(t, 0, 1000, LogQuotaActionPurge)
+		s := mocks.NewStore(t)
+		s.On("OrgGet", org.ID).Return(org, nil)
+		s.On("OrgLogSize", org.ID).Return(int64(1000), nil)
+
+		warnings, err := checkLogQuotas(s, repo)
+		assert.NoError(t, err)
+		assert.Len(t, warnings, 1)
+	})
+
+	t.Run("repo quota overrides default", func(t *testing.T) {
+		setLogQuota(t, 100, 0, LogQuotaActionBlock)
+		s := mocks.NewStore(t)
+		s.On("RepoLogSize", repo.ID).Return(int64(150), nil)
+		s.On("OrgGet", org.ID).Return(org, nil)
+
+		warnings, err := checkLogQuotas(s, &model.Repo{ID: 1, OrgID: 2, LogQuota: 200})
+		assert.NoError(t, err)
+		assert.Empty(t, warnings)
+	})
+}
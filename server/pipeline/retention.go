@@ -0,0 +1,160 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server"
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/store"
+)
+
+// RetentionPolicy defines which finished pipelines of a repo are kept. Zero
+// values disable the respective limit.
+type RetentionPolicy struct {
+	// KeepPerBranch is the number of most recent pipelines kept per branch.
+	KeepPerBranch int
+	// MaxAge is the age after which pipelines are deleted.
+	MaxAge time.Duration
+}
+
+func (p RetentionPolicy) IsZero() bool {
+	return p.KeepPerBranch <= 0 && p.MaxAge <= 0
+}
+
+// RepoRetentionPolicy returns the retention policy of the repo, limits the repo
+// doesn't set itself are taken from the given default.
+func RepoRetentionPolicy(repo *model.Repo, defaultPolicy RetentionPolicy) RetentionPolicy {
+	policy := defaultPolicy
+	if repo.RetentionKeepPerBranch > 0 {
+		policy.KeepPerBranch = repo.RetentionKeepPerBranch
+	}
+	if repo.RetentionDays > 0 {
+		policy.MaxAge = time.Duration(repo.RetentionDays) * 24 * time.Hour
+	}
+	return policy
+}
+
+// ApplyRetention deletes the pipelines, including their logs, which are not kept
+// by the retention policy of their repo and returns the number of deleted
+// pipelines. Active pipelines and the latest successful pipeline on the default
+// branch are always kept.
+func ApplyRetention(ctx context.Context, _store store.Store, defaultPolicy RetentionPolicy) (int, error) {
+	repos, err := _store.RepoListAll(false, &model.ListOptions{All: true})
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, repo := range repos {
+		if err := ctx.Err(); err != nil {
+			return deleted, err
+		}
+
+		policy := RepoRetentionPolicy(repo, defaultPolicy)
+		if policy.IsZero() {
+			continue
+		}
+
+		pipelines, err := _store.GetPipelineList(repo, &model.ListOptions{All: true}, nil)
+		if err != nil {
+			return deleted, err
+		}
+
+		for _, pipeline := range expiredPipelines(repo, pipelines, policy, time.Now()) {
+			if err := deletePipeline(_store, pipeline); err != nil {
+				log.Error().Err(err).Str("repo", repo.FullName).Int64("number", pipeline.Number).Msg("cannot delete pipeline")
+				continue
+			}
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+// expiredPipelines returns the pipelines not kept by the policy, the pipelines
+// have to be ordered by number descending.
+func expiredPipelines(repo *model.Repo, pipelines []*model.Pipeline, policy RetentionPolicy, now time.Time) []*model.Pipeline {
+	var (
+		expired        []*model.Pipeline
+		perBranch      = make(map[string]int)
+		keptSuccessful = false
+	)
+	for _, pipeline := range pipelines {
+		group := retentionGroup(pipeline)
+		perBranch[group]++
+
+		switch pipeline.Status {
+		case model.StatusPending, model.StatusRunning, model.StatusBlocked:
+			continue
+		}
+		if !keptSuccessful && pipeline.Status == model.StatusSuccess && isDefaultBranchPipeline(repo, pipeline) {
+			keptSuccessful = true
+			continue
+		}
+
+		tooMany := policy.KeepPerBranch > 0 && perBranch[group] > policy.KeepPerBranch
+		tooOld := policy.MaxAge > 0 && now.Sub(time.Unix(pipeline.Created, 0)) > policy.MaxAge
+		if tooMany || tooOld {
+			expired = append(expired, pipeline)
+		}
+	}
+	return expired
+}
+
+// retentionGroup returns the key pipelines are counted by, pull requests and
+// tags are counted separately from the branch they target.
+func retentionGroup(pipeline *model.Pipeline) string {
+	switch pipeline.Event {
+	case model.EventPull, model.EventPullClosed, model.EventTag, model.EventRelease:
+		return string(pipeline.Event) + ":" + pipeline.Ref
+	}
+	return pipeline.Branch
+}
+
+func isDefaultBranchPipeline(repo *model.Repo, pipeline *model.Pipeline) bool {
+	switch pipeline.Event {
+	case model.EventPush, model.EventManual, model.EventCron, model.EventDeploy:
+		return pipeline.Branch == repo.Branch
+	}
+	return false
+}
+
+func deletePipeline(_store store.Store, pipeline *model.Pipeline) error {
+	steps, err := _store.StepList(pipeline)
+	if err != nil {
+		return err
+	}
+	for _, step := range steps {
+		// steps which never ran have no logs
+		if lErr := server.Config.Services.LogStore.LogDelete(step); lErr != nil && !errors.Is(lErr, fs.ErrNotExist) {
+			err = errors.Join(err, lErr)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return _store.DeletePipeline(pipeline)
+}
@@ -0,0 +1,88 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package pipeline
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+)
+
+func TestExpiredPipelines(t *testing.T) {
+	now := time.Now()
+	repo := &model.Repo{Branch: "main"}
+	pipeline := func(number int64, event model.WebhookEvent, branch string, status model.StatusValue, age time.Duration) *model.Pipeline {
+		return &model.Pipeline{
+			Number:  number,
+			Event:   event,
+			Branch:  branch,
+			Ref:     "refs/pull/1/head",
+			Status:  status,
+			Created: now.Add(-age).Unix(),
+		}
+	}
+	numbers := func(pipelines []*model.Pipeline) []int64 {
+		var n []int64
+		for _, p := range pipelines {
+			n = append(n, p.Number)
+		}
+		return n
+	}
+
+	pipelines := []*model.Pipeline{
+		pipeline(9, model.EventPush, "main", model.StatusFailure, time.Hour),
+		pipeline(8, model.EventPull, "main", model.StatusSuccess, time.Hour),
+		pipeline(7, model.EventPush, "dev", model.StatusSuccess, time.Hour),
+		pipeline(6, model.EventPush, "main", model.StatusRunning, 72*time.Hour),
+		pipeline(5, model.EventPush, "main", model.StatusFailure, 72*time.Hour),
+		pipeline(4, model.EventPush, "dev", model.StatusSuccess, 72*time.Hour),
+		pipeline(3, model.EventPush, "main", model.StatusSuccess, 72*time.Hour),
+		pipeline(2, model.EventPull, "main", model.StatusFailure, 72*time.Hour),
+		pipeline(1, model.EventPush, "main", model.StatusSuccess, 72*time.Hour),
+	}
+
+	t.Run("keep per branch", func(t *testing.T) {
+		expired := expiredPipelines(repo, pipelines, RetentionPolicy{KeepPerBranch: 1}, now)
+		assert.Equal(t, []int64{5, 4, 2, 1}, numbers(expired))
+	})
+
+	t.Run("max age", func(t *testing.T) {
+		expired := expiredPipelines(repo, pipelines, RetentionPolicy{MaxAge: 24 * time.Hour}, now)
+		assert.Equal(t, []int64{5, 4, 2, 1}, numbers(expired))
+	})
+
+	t.Run("keep latest successful", func(t *testing.T) {
+		old := []*model.Pipeline{
+			pipeline(3, model.EventPull, "main", model.StatusSuccess, 72*time.Hour),
+			pipeline(2, model.EventPush, "main", model.StatusSuccess, 72*time.Hour),
+			pipeline(1, model.EventPush, "main", model.StatusSuccess, 72*time.Hour),
+		}
+		expired := expiredPipelines(repo, old, RetentionPolicy{MaxAge: 24 * time.Hour}, now)
+		assert.Equal(t, []int64{3, 1}, numbers(expired))
+	})
+}
+
+func TestRepoRetentionPolicy(t *testing.T) {
+	defaultPolicy := RetentionPolicy{KeepPerBranch: 10, MaxAge: time.Hour}
+	assert.Equal(t, defaultPolicy, RepoRetentionPolicy(&model.Repo{}, defaultPolicy))
+	assert.Equal(t, RetentionPolicy{KeepPerBranch: 3, MaxAge: 48 * time.Hour},
+		RepoRetentionPolicy(&model.Repo{RetentionKeepPerBranch: 3, RetentionDays: 2}, defaultPolicy))
+}
@@ -16,6 +16,9 @@ package pipeline
 
 import (
 	"context"
+	"maps"
+	"slices"
+	"time"
 
 	"github.com/rs/zerolog/log"
 
@@ -33,13 +36,24 @@ func start(ctx context.Context, forge forge.Forge, store store.Store, activePipe
 		log.Error().Err(err).Msg("failed to cancel previous pipelines")
 	}
 
+	// call to cancel pipelines of the same concurrency groups if needed
+	if err := cancelConcurrencyGroups(ctx, forge, store, activePipeline, repo, user, pipelineItems); err != nil {
+		// should be not breaking
+		log.Error().Err(err).Msg("failed to cancel pipelines of the same concurrency groups")
+	}
+
 	publishPipeline(ctx, forge, activePipeline, repo, user)
 
-	if err := queuePipeline(ctx, repo, pipelineItems); err != nil {
+	if err := queuePipeline(ctx, store, activePipeline, repo, pipelineItems); err != nil {
 		log.Error().Err(err).Msg("queuePipeline")
 		return nil, err
 	}
 
+	if err := recordSecretUsage(store, activePipeline, repo, pipelineItems); err != nil {
+		// should be not breaking
+		log.Error().Err(err).Msg("failed to record secret usage")
+	}
+
 	return activePipeline, nil
 }
 
@@ -57,3 +71,18 @@ func publishPipeline(ctx context.Context, forge forge.Forge, pipeline *model.Pip
 	publishToTopic(pipeline, repo)
 	updatePipelineStatus(ctx, forge, pipeline, repo, repoUser)
 }
+
+// recordSecretUsage records the usage of the stored secrets referenced by the workflows of a started pipeline.
+func recordSecretUsage(store store.Store, activePipeline *model.Pipeline, repo *model.Repo, pipelineItems []*stepbuilder.Item) error {
+	ids := make(map[int64]struct{})
+	for _, item := range pipelineItems {
+		for _, secret := range item.UsedSecrets {
+			// secrets provided by an external secret service are not stored
+			if secret.ID != 0 {
+				ids[secret.ID] = struct{}{}
+			}
+		}
+	}
+
+	return store.SecretRecordUsage(slices.Sorted(maps.Keys(ids)), repo.ID, activePipeline.Number, time.Now().Unix())
+}
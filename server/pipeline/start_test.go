@@ -0,0 +1,41 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/pipeline/stepbuilder"
+	"go.woodpecker-ci.org/woodpecker/v2/server/store/mocks"
+)
+
+func TestRecordSecretUsage(t *testing.T) {
+	store := mocks.NewStore(t)
+	store.On("SecretRecordUsage", []int64{1, 2}, int64(3), int64(4), mock.AnythingOfType("int64")).Once().Return(nil)
+
+	items := []*stepbuilder.Item{
+		{UsedSecrets: []*model.Secret{{ID: 2, Name: "token"}, {ID: 1, Name: "deploy_key"}}},
+		{UsedSecrets: []*model.Secret{{ID: 1, Name: "deploy_key"}, {Name: "external"}}},
+		{},
+	}
+	assert.NoError(t, recordSecretUsage(store, &model.Pipeline{Number: 4}, &model.Repo{ID: 3}, items))
+}
@@ -0,0 +1,65 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package stepbuilder
+
+import (
+	yaml_types "go.woodpecker-ci.org/woodpecker/v2/pipeline/frontend/yaml/types"
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+)
+
+// cpuQuotaPeriod is the default CFS period of docker in microseconds,
+// a cpu_quota of this value equals one CPU core.
+const cpuQuotaPeriod = 100000
+
+// workflowResources returns the resources a workflow requests from an agent.
+// They are derived from the limits of the largest step plus the limits of all
+// services, values of the resources block of the workflow take precedence.
+func workflowResources(parsed *yaml_types.Workflow) model.Resources {
+	var steps model.Resources
+	for _, step := range parsed.Steps.ContainerList {
+		r := containerResources(step)
+		steps.CPU = max(steps.CPU, r.CPU)
+		steps.Memory = max(steps.Memory, r.Memory)
+	}
+
+	resources := steps
+	for _, service := range parsed.Services.ContainerList {
+		resources = resources.Add(containerResources(service))
+	}
+
+	if parsed.Resources != nil {
+		if parsed.Resources.CPU > 0 {
+			resources.CPU = int64(parsed.Resources.CPU * 1000) //nolint:mnd
+		}
+		if parsed.Resources.Memory > 0 {
+			resources.Memory = int64(parsed.Resources.Memory)
+		}
+		if parsed.Resources.Disk > 0 {
+			resources.Disk = int64(parsed.Resources.Disk)
+		}
+	}
+
+	return resources
+}
+
+func containerResources(container *yaml_types.Container) model.Resources {
+	return model.Resources{
+		CPU:    int64(container.CPUQuota) * 1000 / cpuQuotaPeriod, //nolint:mnd
+		Memory: int64(container.MemLimit),
+	}
+}
@@ -0,0 +1,88 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package stepbuilder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.woodpecker-ci.org/woodpecker/v2/pipeline/frontend/yaml"
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+)
+
+func TestWorkflowResources(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		expected model.Resources
+	}{
+		{
+			name: "no resources",
+			config: `
+steps:
+  build:
+    image: scratch
+`,
+			expected: model.Resources{},
+		},
+		{
+			name: "largest step plus services",
+			config: `
+steps:
+  build:
+    image: scratch
+    mem_limit: 2GiB
+    cpu_quota: 200000
+  test:
+    image: scratch
+    mem_limit: 1GiB
+services:
+  database:
+    image: scratch
+    mem_limit: 512MiB
+    cpu_quota: 50000
+`,
+			expected: model.Resources{CPU: 2500, Memory: 2560 << 20},
+		},
+		{
+			name: "resources block takes precedence",
+			config: `
+steps:
+  build:
+    image: scratch
+    mem_limit: 2GiB
+    cpu_quota: 200000
+resources:
+  cpu: 4
+  disk: 10GiB
+`,
+			expected: model.Resources{CPU: 4000, Memory: 2 << 30, Disk: 10 << 30},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := yaml.ParseString(tt.config)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tt.expected, workflowResources(parsed))
+		})
+	}
+}
@@ -0,0 +1,94 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package stepbuilder
+
+import (
+	"maps"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+
+	forge_types "go.woodpecker-ci.org/woodpecker/v2/server/forge/types"
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+)
+
+// RequestedSecrets returns the names of the secrets the configs request with
+// from_secret or the deprecated secrets list of a step, ordered by name.
+func RequestedSecrets(configs []*forge_types.FileMeta) []string {
+	names := make(map[string]struct{})
+	for _, config := range configs {
+		collectSecretNames(config.Data, names)
+	}
+	return slices.Sorted(maps.Keys(names))
+}
+
+// usedSecrets returns the secrets of the builder the workflow config references.
+func (b *StepBuilder) usedSecrets(data string) []*model.Secret {
+	names := make(map[string]struct{})
+	collectSecretNames([]byte(data), names)
+
+	var used []*model.Secret
+	for _, sec := range b.Secs {
+		if _, ok := names[sec.Name]; ok {
+			used = append(used, sec)
+		}
+	}
+	return used
+}
+
+func collectSecretNames(data []byte, names map[string]struct{}) {
+	var walk func(node any)
+	walk = func(node any) {
+		switch node := node.(type) {
+		case map[string]any:
+			for key, value := range node {
+				switch {
+				case key == "from_secret":
+					if name, ok := value.(string); ok {
+						names[name] = struct{}{}
+					}
+				case key == "secrets":
+					list, _ := value.([]any)
+					for _, item := range list {
+						switch item := item.(type) {
+						case string:
+							names[item] = struct{}{}
+						case map[string]any:
+							if name, ok := item["source"].(string); ok {
+								names[name] = struct{}{}
+							}
+						}
+					}
+				default:
+					walk(value)
+				}
+			}
+		case []any:
+			for _, item := range node {
+				walk(item)
+			}
+		}
+	}
+
+	var parsed any
+	if err := yaml.Unmarshal(data, &parsed); err != nil {
+		// reported by the linter later
+		return
+	}
+	walk(parsed)
+}
@@ -0,0 +1,106 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package stepbuilder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	forge_types "go.woodpecker-ci.org/woodpecker/v2/server/forge/types"
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+)
+
+func TestRequestedSecrets(t *testing.T) {
+	configs := []*forge_types.FileMeta{
+		{Name: "build.yaml", Data: []byte(`
+steps:
+  build:
+    image: golang
+    environment:
+      TOKEN:
+        from_secret: token
+  publish:
+    image: plugins/docker
+    settings:
+      password:
+        from_secret: docker_password
+    secrets: [ legacy, { source: legacy_source, target: target } ]
+`)},
+		{Name: "deploy.yaml", Data: []byte(`
+steps:
+  deploy:
+    image: alpine
+    environment:
+      KEY:
+        from_secret: token
+`)},
+		{Name: "broken.yaml", Data: []byte(`steps: [`)},
+	}
+
+	assert.Equal(t, []string{"docker_password", "legacy", "legacy_source", "token"}, RequestedSecrets(configs))
+}
+
+func TestUsedSecrets(t *testing.T) {
+	t.Parallel()
+
+	token := &model.Secret{ID: 1, Name: "token", Value: "abc", Events: []model.WebhookEvent{model.EventPush}}
+	deployKey := &model.Secret{ID: 2, Name: "deploy_key", Value: "def", Events: []model.WebhookEvent{model.EventPush}}
+	unused := &model.Secret{ID: 3, Name: "unused", Value: "ghi", Events: []model.WebhookEvent{model.EventPush}}
+
+	b := StepBuilder{
+		Forge: getMockForge(t),
+		Repo:  &model.Repo{},
+		Curr: &model.Pipeline{
+			Event: model.EventPush,
+		},
+		Last:  &model.Pipeline{},
+		Netrc: &model.Netrc{},
+		Secs:  []*model.Secret{token, deployKey, unused},
+		Regs:  []*model.Registry{},
+		Configs: []*forge_types.FileMeta{
+			{Name: "build", Data: []byte(`
+when:
+  event: push
+steps:
+  build:
+    image: scratch
+    environment:
+      TOKEN:
+        from_secret: token
+`)},
+			{Name: "deploy", Data: []byte(`
+when:
+  event: tag
+steps:
+  deploy:
+    image: scratch
+    environment:
+      KEY:
+        from_secret: deploy_key
+`)},
+		},
+	}
+
+	items, err := b.Build()
+	assert.NoError(t, err)
+	if assert.Len(t, items, 1) {
+		// the deploy workflow is filtered, so its secret is not used
+		assert.Equal(t, []*model.Secret{token}, items[0].UsedSecrets)
+	}
+}
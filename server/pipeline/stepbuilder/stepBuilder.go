@@ -74,11 +74,15 @@ type StepBuilder struct {
 }
 
 type Item struct {
-	Workflow  *model.Workflow
-	Labels    map[string]string
-	DependsOn []string
-	RunsOn    []string
-	Config    *backend_types.Config
+	Workflow         *model.Workflow
+	Labels           map[string]string
+	DependsOn        []string
+	RunsOn           []string
+	Priority         *int
+	Config           *backend_types.Config
+	CancelInProgress bool
+	Resources        model.Resources
+	UsedSecrets      []*model.Secret
 }
 
 func (b *StepBuilder) Build() (items []*Item, errorsAndWarnings error) {
@@ -158,7 +162,7 @@ func (b *StepBuilder) evaluateJsonnet(envs map[string]string) error {
 			}
 
 			importer := jsonnet.MemoryImporter{
-				map[string]jsonnet.Contents{
+				Data: map[string]jsonnet.Contents{
 					"env.jsonnet": jsonnet.MakeContents(string(envJson)),
 				},
 			}
@@ -236,15 +240,22 @@ func (b *StepBuilder) genItemForWorkflow(workflowMetadata metadata.Metadata, wor
 	}
 
 	item = &Item{
-		Workflow:  workflow,
-		Config:    ir,
-		Labels:    parsed.Labels,
-		DependsOn: parsed.DependsOn,
-		RunsOn:    parsed.RunsOn,
+		Workflow:    workflow,
+		Config:      ir,
+		Labels:      parsed.Labels,
+		DependsOn:   parsed.DependsOn,
+		RunsOn:      parsed.RunsOn,
+		Priority:    parsed.Priority,
+		Resources:   workflowResources(parsed),
+		UsedSecrets: b.usedSecrets(substituted),
 	}
 	if item.Labels == nil {
 		item.Labels = map[string]string{}
 	}
+	if parsed.Concurrency != nil {
+		workflow.ConcurrencyGroup = parsed.Concurrency.Group
+		item.CancelInProgress = parsed.Concurrency.CancelInProgress
+	}
 
 	return item, errorsAndWarnings
 }
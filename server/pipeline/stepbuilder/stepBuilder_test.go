@@ -303,6 +303,84 @@ runs_on:
 	}
 }
 
+func TestConcurrency(t *testing.T) {
+	t.Parallel()
+
+	b := StepBuilder{
+		Forge: getMockForge(t),
+		Repo:  &model.Repo{},
+		Curr: &model.Pipeline{
+			Event:  model.EventPush,
+			Branch: "main",
+		},
+		Last:  &model.Pipeline{},
+		Netrc: &model.Netrc{},
+		Secs:  []*model.Secret{},
+		Regs:  []*model.Registry{},
+		Host:  "",
+		Configs: []*forge_types.FileMeta{
+			{Data: []byte(`
+when:
+  event: push
+steps:
+  deploy:
+    image: scratch
+
+concurrency:
+  group: deploy-${CI_COMMIT_BRANCH}
+  cancel_in_progress: true
+`)},
+		},
+	}
+
+	pipelineItems, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pipelineItems[0].Workflow.ConcurrencyGroup != "deploy-main" {
+		t.Fatal("Should evaluate the concurrency group")
+	}
+	if !pipelineItems[0].CancelInProgress {
+		t.Fatal("Should cancel in progress")
+	}
+}
+
+func TestResources(t *testing.T) {
+	t.Parallel()
+
+	b := StepBuilder{
+		Forge: getMockForge(t),
+		Repo:  &model.Repo{},
+		Curr: &model.Pipeline{
+			Event: model.EventPush,
+		},
+		Last:  &model.Pipeline{},
+		Netrc: &model.Netrc{},
+		Secs:  []*model.Secret{},
+		Regs:  []*model.Registry{},
+		Host:  "",
+		Configs: []*forge_types.FileMeta{
+			{Data: []byte(`
+when:
+  event: push
+steps:
+  build:
+    image: scratch
+resources:
+  cpu: 0.5
+  memory: 2GiB
+  disk: 10GiB
+`)},
+		},
+	}
+
+	pipelineItems, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, model.Resources{CPU: 500, Memory: 2 << 30, Disk: 10 << 30}, pipelineItems[0].Resources)
+}
+
 func TestPipelineName(t *testing.T) {
 	t.Parallel()
 
@@ -31,6 +31,13 @@ func UpdateWorkflowToStatusSkipped(store store.Store, workflow model.Workflow) (
 	return &workflow, store.WorkflowUpdate(&workflow)
 }
 
+func UpdateWorkflowToStatusExpired(store store.Store, workflow model.Workflow, err string, finished int64) (*model.Workflow, error) {
+	workflow.State = model.StatusFailure
+	workflow.Error = err
+	workflow.Finished = finished
+	return &workflow, store.WorkflowUpdate(&workflow)
+}
+
 func UpdateWorkflowStatusToDone(store store.Store, workflow model.Workflow, state rpc.WorkflowState) (*model.Workflow, error) {
 	workflow.Finished = state.Finished
 	workflow.Error = state.Error
@@ -0,0 +1,235 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package pubsub
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// postgresChunkSize is the size of the message chunks sent by NOTIFY,
+	// as the payload of a notification is limited to 8000 bytes.
+	postgresChunkSize = 7000
+	// postgresMaxChunks limits the chunks of a message, so a malformed chunk
+	// count can't make the receivers allocate unbounded memory.
+	postgresMaxChunks = 2000
+	// postgresChunkTimeout is the time after which incomplete messages are dropped.
+	postgresChunkTimeout = time.Minute
+)
+
+type postgres struct {
+	db       *sql.DB
+	listener *pq.Listener
+	channel  string
+	subs     *subscribers
+
+	sync.Mutex
+	partial map[string]*partialMessage
+}
+
+type partialMessage struct {
+	chunks   []string
+	received int
+	created  time.Time
+}
+
+// NewPostgres creates a publisher that shares the messages with other servers
+// through PostgreSQL LISTEN/NOTIFY on the given channel.
+func NewPostgres(ctx context.Context, datasource, channel string) (Publisher, error) {
+	db, err := sql.Open("postgres", datasource)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("could not connect to postgres: %w", err)
+	}
+
+	listener := pq.NewListener(datasource, time.Second, time.Minute, func(_ pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Error().Err(err).Msg("pubsub: postgres listener failed")
+		}
+	})
+	if err := listener.Listen(channel); err != nil {
+		return nil, err
+	}
+
+	p := &postgres{
+		db:       db,
+		listener: listener,
+		channel:  channel,
+		subs:     newSubscribers(),
+		partial:  make(map[string]*partialMessage),
+	}
+	go p.listen(ctx)
+	return p, nil
+}
+
+func (p *postgres) Publish(message Message) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		log.Error().Err(err).Msg("pubsub: can't marshal message")
+		return
+	}
+
+	chunks := encodeChunks(data, postgresChunkSize)
+	if len(chunks) > postgresMaxChunks {
+		log.Error().Msgf("pubsub: message of %d bytes is too large for postgres", len(data))
+		return
+	}
+	for _, chunk := range chunks {
+		if _, err := p.db.Exec("SELECT pg_notify($1, $2)", p.channel, chunk); err != nil {
+			log.Error().Err(err).Msg("pubsub: can't notify postgres")
+			return
+		}
+	}
+}
+
+func (p *postgres) Subscribe(c context.Context, receiver Receiver) {
+	p.subs.subscribe(c, receiver)
+}
+
+func (p *postgres) listen(ctx context.Context) {
+	defer func() {
+		if err := p.listener.Close(); err != nil {
+			log.Error().Err(err).Msg("pubsub: can't close postgres listener")
+		}
+		if err := p.db.Close(); err != nil {
+			log.Error().Err(err).Msg("pubsub: can't close postgres connection")
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case notification := <-p.listener.Notify:
+			// nil is sent after the connection was re-established
+			if notification == nil {
+				continue
+			}
+			if data := p.assemble(notification.Extra); data != nil {
+				var message Message
+				if err := json.Unmarshal(data, &message); err != nil {
+					log.Error().Err(err).Msg("pubsub: can't unmarshal message")
+					continue
+				}
+				p.subs.deliver(message)
+			}
+		case <-time.After(postgresChunkTimeout):
+			// check the connection from time to time, as proposed by pq
+			go func() {
+				if err := p.listener.Ping(); err != nil {
+					log.Error().Err(err).Msg("pubsub: postgres listener ping failed")
+				}
+			}()
+		}
+	}
+}
+
+// assemble adds the chunk to its message and returns the message data once
+// all of its chunks were received.
+func (p *postgres) assemble(chunk string) []byte {
+	id, index, count, data, err := decodeChunk(chunk)
+	if err != nil {
+		log.Error().Err(err).Msg("pubsub: invalid notification")
+		return nil
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	for key, m := range p.partial {
+		if time.Since(m.created) > postgresChunkTimeout {
+			delete(p.partial, key)
+		}
+	}
+
+	m, ok := p.partial[id]
+	if !ok {
+		m = &partialMessage{chunks: make([]string, count), created: time.Now()}
+		p.partial[id] = m
+	}
+	if index >= len(m.chunks) || m.chunks[index] != "" {
+		return nil
+	}
+	m.chunks[index] = data
+	m.received++
+	if m.received < len(m.chunks) {
+		return nil
+	}
+
+	delete(p.partial, id)
+	decoded, err := base64.StdEncoding.DecodeString(strings.Join(m.chunks, ""))
+	if err != nil {
+		log.Error().Err(err).Msg("pubsub: invalid notification")
+		return nil
+	}
+	return decoded
+}
+
+// encodeChunks splits the base64 encoded data into chunks of the given size,
+// each prefixed with the message id, the chunk index and the chunk count.
+func encodeChunks(data []byte, size int) []string {
+	encoded := base64.StdEncoding.EncodeToString(data)
+
+	b := make([]byte, 8) //nolint:mnd
+	_, _ = rand.Read(b)
+	id := hex.EncodeToString(b)
+
+	count := (len(encoded) + size - 1) / size
+	if count == 0 {
+		count = 1
+	}
+	chunks := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		end := min((i+1)*size, len(encoded))
+		chunks = append(chunks, fmt.Sprintf("%s %d %d %s", id, i, count, encoded[i*size:end]))
+	}
+	return chunks
+}
+
+func decodeChunk(chunk string) (id string, index, count int, data string, err error) {
+	const fields = 4 // id, index, count and data
+	parts := strings.SplitN(chunk, " ", fields)
+	if len(parts) != fields {
+		return "", 0, 0, "", fmt.Errorf("malformed chunk")
+	}
+	if index, err = strconv.Atoi(parts[1]); err != nil {
+		return "", 0, 0, "", err
+	}
+	if count, err = strconv.Atoi(parts[2]); err != nil {
+		return "", 0, 0, "", err
+	}
+	if index < 0 || count <= 0 || count > postgresMaxChunks || index >= count {
+		return "", 0, 0, "", fmt.Errorf("chunk %d of %d is out of range", index, count)
+	}
+	return parts[0], index, count, parts[3], nil
+}
@@ -0,0 +1,50 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package pubsub
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresChunks(t *testing.T) {
+	p := &postgres{partial: make(map[string]*partialMessage)}
+
+	data := bytes.Repeat([]byte("woodpecker "), 100)
+	chunks := encodeChunks(data, 64)
+	assert.Greater(t, len(chunks), 1)
+
+	// chunks may arrive in any order and twice
+	for i := len(chunks) - 1; i > 0; i-- {
+		assert.Nil(t, p.assemble(chunks[i]))
+		assert.Nil(t, p.assemble(chunks[i]))
+	}
+	assert.Equal(t, data, p.assemble(chunks[0]))
+	assert.Empty(t, p.partial)
+
+	chunks = encodeChunks([]byte("{}"), 64)
+	assert.Len(t, chunks, 1)
+	assert.Equal(t, []byte("{}"), p.assemble(chunks[0]))
+
+	assert.Nil(t, p.assemble("malformed"))
+	assert.Nil(t, p.assemble("id 2 2 data"))
+	assert.Nil(t, p.assemble("id 0 1000000000 data"))
+	assert.Empty(t, p.partial)
+}
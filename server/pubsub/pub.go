@@ -29,25 +29,38 @@ type Message struct {
 
 	// Labels represents the key-value pairs the entry is labeled with.
 	Labels map[string]string `json:"labels,omitempty"`
+
+	// Required messages are never dropped by publishers that drop messages
+	// when they can't keep up, publishing them may wait instead.
+	Required bool `json:"-"`
 }
 
 // Receiver receives published messages.
 type Receiver func(Message)
 
-type Publisher struct {
+// Publisher publishes messages to the subscribed receivers.
+type Publisher interface {
+	// Publish publishes the message to all receivers.
+	Publish(message Message)
+
+	// Subscribe subscribes the receiver until the context is done.
+	Subscribe(c context.Context, receiver Receiver)
+}
+
+type memory struct {
 	sync.Mutex
 
 	subs map[*Receiver]struct{}
 }
 
 // New creates an in-memory publisher.
-func New() *Publisher {
-	return &Publisher{
+func New() Publisher {
+	return &memory{
 		subs: make(map[*Receiver]struct{}),
 	}
 }
 
-func (p *Publisher) Publish(message Message) {
+func (p *memory) Publish(message Message) {
 	p.Lock()
 	for s := range p.subs {
 		go (*s)(message)
@@ -55,7 +68,7 @@ func (p *Publisher) Publish(message Message) {
 	p.Unlock()
 }
 
-func (p *Publisher) Subscribe(c context.Context, receiver Receiver) {
+func (p *memory) Subscribe(c context.Context, receiver Receiver) {
 	p.Lock()
 	p.subs[&receiver] = struct{}{}
 	p.Unlock()
@@ -0,0 +1,131 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	redisConnectTimeout = 10 * time.Second
+	// redisQueueSize is the number of messages buffered for publishing, further
+	// messages are dropped so publishers never wait for redis, unless they are
+	// required.
+	redisQueueSize = 1024
+)
+
+type redisPublisher struct {
+	ctx     context.Context
+	client  *redis.Client
+	channel string
+	subs    *subscribers
+	queue   chan []byte
+}
+
+// NewRedis creates a publisher that shares the messages with other servers
+// through the Redis publish/subscribe protocol on the given channel. Messages
+// are published asynchronously, the client reconnects on its own.
+func NewRedis(ctx context.Context, options *redis.Options, channel string) (Publisher, error) {
+	client := redis.NewClient(options)
+
+	pingCtx, cancel := context.WithTimeout(ctx, redisConnectTimeout)
+	defer cancel()
+	if err := client.Ping(pingCtx).Err(); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("could not connect to redis: %w", err)
+	}
+
+	p := &redisPublisher{
+		ctx:     ctx,
+		client:  client,
+		channel: channel,
+		subs:    newSubscribers(),
+		queue:   make(chan []byte, redisQueueSize),
+	}
+	subscription := client.Subscribe(ctx, channel)
+	context.AfterFunc(ctx, func() {
+		_ = subscription.Close()
+		_ = client.Close()
+	})
+
+	go p.publish()
+	go p.receive(subscription)
+	return p, nil
+}
+
+func (p *redisPublisher) Publish(message Message) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		log.Error().Err(err).Msg("pubsub: can't marshal message")
+		return
+	}
+
+	select {
+	case p.queue <- data:
+		return
+	default:
+	}
+
+	if !message.Required {
+		log.Warn().Msg("pubsub: redis publish queue is full, dropping message")
+		return
+	}
+	select {
+	case p.queue <- data:
+	case <-p.ctx.Done():
+	}
+}
+
+// publish sends the queued messages in order.
+func (p *redisPublisher) publish() {
+	for {
+		var data []byte
+		select {
+		case <-p.ctx.Done():
+			return
+		case data = <-p.queue:
+		}
+
+		if err := p.client.Publish(p.ctx, p.channel, data).Err(); err != nil && p.ctx.Err() == nil {
+			log.Error().Err(err).Msg("pubsub: can't publish to redis")
+		}
+	}
+}
+
+func (p *redisPublisher) Subscribe(c context.Context, receiver Receiver) {
+	p.subs.subscribe(c, receiver)
+}
+
+// receive delivers the messages of the subscription, which is restored by the
+// client after a reconnect, until it is closed.
+func (p *redisPublisher) receive(subscription *redis.PubSub) {
+	for msg := range subscription.Channel() {
+		var message Message
+		if err := json.Unmarshal([]byte(msg.Payload), &message); err != nil {
+			log.Error().Err(err).Msg("pubsub: can't unmarshal message")
+			continue
+		}
+		p.subs.deliver(message)
+	}
+}
@@ -0,0 +1,187 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package pubsub
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedis(t *testing.T) {
+	t.Run("password", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		server.RequireAuth("secret")
+
+		_, err := NewRedis(context.Background(), &redis.Options{Addr: server.Addr(), Password: "wrong"}, "test")
+		assert.Error(t, err)
+		testRedis(t, server, &redis.Options{Addr: server.Addr(), Password: "secret"})
+	})
+
+	t.Run("url", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		server.RequireUserAuth("woodpecker", "secret")
+
+		options, err := redis.ParseURL("redis://woodpecker:secret@" + server.Addr() + "/2")
+		require.NoError(t, err)
+		testRedis(t, server, options)
+	})
+
+	t.Run("tls", func(t *testing.T) {
+		cert, pool := selfSignedCert(t)
+		server, err := miniredis.RunTLS(&tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12})
+		require.NoError(t, err)
+		t.Cleanup(server.Close)
+
+		testRedis(t, server, &redis.Options{Addr: server.Addr(), TLSConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}})
+	})
+}
+
+func testRedis(t *testing.T, server *miniredis.Miniredis, options *redis.Options) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	first, err := NewRedis(ctx, options, "test")
+	require.NoError(t, err)
+	second, err := NewRedis(ctx, options, "test")
+	require.NoError(t, err)
+
+	received := make(chan Message, 1)
+	go second.Subscribe(ctx, func(message Message) { received <- message })
+
+	assert.Eventually(t, func() bool { return server.PubSubNumSub("test")["test"] == 2 }, time.Second, 10*time.Millisecond)
+	// the subscription of the receiver is set up asynchronously
+	time.Sleep(50 * time.Millisecond)
+
+	message := Message{
+		Data:   []byte("test"),
+		Labels: map[string]string{"repo": "1"},
+	}
+	first.Publish(message)
+
+	select {
+	case got := <-received:
+		assert.Equal(t, message, got)
+	case <-time.After(time.Second):
+		t.Fatal("message was not received")
+	}
+}
+
+func TestRedisReconnect(t *testing.T) {
+	server := miniredis.RunT(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	publisher, err := NewRedis(ctx, &redis.Options{Addr: server.Addr(), MaxRetryBackoff: 50 * time.Millisecond}, "test")
+	require.NoError(t, err)
+	received := make(chan Message, 1)
+	go publisher.Subscribe(ctx, func(message Message) { received <- message })
+	assert.Eventually(t, func() bool { return server.PubSubNumSub("test")["test"] == 1 }, time.Second, 10*time.Millisecond)
+
+	server.Close()
+	require.NoError(t, server.Restart())
+
+	// the subscription is restored after the restart
+	assert.Eventually(t, func() bool { return server.PubSubNumSub("test")["test"] == 1 }, 5*time.Second, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	publisher.Publish(Message{Data: []byte("after restart")})
+
+	select {
+	case got := <-received:
+		assert.Equal(t, []byte("after restart"), got.Data)
+	case <-time.After(5 * time.Second):
+		t.Fatal("message was not received after reconnect")
+	}
+}
+
+func TestRedisPublishQueue(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// without a publishing goroutine the queue fills up
+	publisher := &redisPublisher{ctx: ctx, queue: make(chan []byte, redisQueueSize)}
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 2*redisQueueSize; i++ {
+			publisher.Publish(Message{Data: []byte("test")})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("publish blocked on a full queue")
+	}
+	assert.Len(t, publisher.queue, redisQueueSize)
+
+	// required messages wait for space in the queue instead of being dropped
+	published := make(chan struct{})
+	go func() {
+		publisher.Publish(Message{Data: []byte("close"), Required: true})
+		close(published)
+	}()
+	select {
+	case <-published:
+		t.Fatal("required message was dropped")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	<-publisher.queue
+	<-published
+	var last []byte
+	for len(publisher.queue) > 0 {
+		last = <-publisher.queue
+	}
+	assert.JSONEq(t, `{"data":"Y2xvc2U="}`, string(last))
+}
+
+func selfSignedCert(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "redis"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, pool
+}
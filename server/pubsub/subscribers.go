@@ -0,0 +1,76 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package pubsub
+
+import (
+	"context"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// maxQueuedMessages is the number of messages a receiver of an external
+// publisher can fall behind before messages are dropped for it.
+const maxQueuedMessages = 1000
+
+// subscribers delivers the messages an external publisher receives to the
+// local receivers. Each receiver gets the messages in the order they arrived,
+// so a slow receiver doesn't block the others.
+type subscribers struct {
+	sync.Mutex
+
+	subs map[*Receiver]chan Message
+}
+
+func newSubscribers() *subscribers {
+	return &subscribers{
+		subs: make(map[*Receiver]chan Message),
+	}
+}
+
+func (s *subscribers) subscribe(c context.Context, receiver Receiver) {
+	messages := make(chan Message, maxQueuedMessages)
+	s.Lock()
+	s.subs[&receiver] = messages
+	s.Unlock()
+
+	for {
+		select {
+		case <-c.Done():
+			s.Lock()
+			delete(s.subs, &receiver)
+			s.Unlock()
+			return
+		case message := <-messages:
+			receiver(message)
+		}
+	}
+}
+
+func (s *subscribers) deliver(message Message) {
+	s.Lock()
+	defer s.Unlock()
+
+	for _, messages := range s.subs {
+		select {
+		case messages <- message:
+		default:
+			log.Warn().Msg("pubsub: receiver is too slow -- dropping message")
+		}
+	}
+}
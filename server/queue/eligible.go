@@ -0,0 +1,79 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package queue
+
+import (
+	"fmt"
+	"time"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+)
+
+// AgentMismatch explains why an agent can't run a task.
+type AgentMismatch struct {
+	AgentID   int64  `json:"agent_id"`
+	AgentName string `json:"agent_name"`
+	Reason    string `json:"reason"`
+} //	@name AgentMismatch
+
+// AgentContactTimeout is how long after its last health report an agent is
+// considered offline. Agents report their health every 10 seconds.
+const AgentContactTimeout = time.Minute
+
+// ExplainAgents returns why each of the agents can't run the task, or nil if at
+// least one of them can. Agents that are busy still count as eligible, agents
+// that are offline at the given time don't.
+func ExplainAgents(task *model.Task, agents []*model.Agent, now time.Time) []AgentMismatch {
+	mismatches := make([]AgentMismatch, 0, len(agents))
+	for _, agent := range agents {
+		reason := "agent is offline"
+		if now.Sub(time.Unix(agent.LastContact, 0)) <= AgentContactTimeout {
+			reason = agentMismatch(task, agent)
+		}
+		if reason == "" {
+			return nil
+		}
+		mismatches = append(mismatches, AgentMismatch{
+			AgentID:   agent.ID,
+			AgentName: agent.Name,
+			Reason:    reason,
+		})
+	}
+	return mismatches
+}
+
+func agentMismatch(task *model.Task, agent *model.Agent) string {
+	if agent.NoSchedule {
+		return "agent does not accept new workflows"
+	}
+	if agent.Labels == nil {
+		return "agent did not poll for workflows yet"
+	}
+	if err := MatchLabels(task.Labels, agent.Labels); err != nil {
+		return err.Error()
+	}
+	if !agent.Resources.Fits(model.Resources{}, task.Resources) {
+		return fmt.Sprintf("agent has not enough resources, workflow requests %s but agent has %s",
+			formatResources(task.Resources), formatResources(agent.Resources))
+	}
+	return ""
+}
+
+func formatResources(r model.Resources) string {
+	return fmt.Sprintf("cpu=%dm memory=%d disk=%d", r.CPU, r.Memory, r.Disk)
+}
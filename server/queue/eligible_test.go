@@ -0,0 +1,51 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+)
+
+func TestExplainAgents(t *testing.T) {
+	task := &model.Task{
+		Labels:    map[string]string{"platform": "linux/amd64"},
+		Resources: model.Resources{CPU: 4000},
+	}
+	now := time.Now()
+	contact := now.Add(-10 * time.Second).Unix()
+	small := &model.Agent{ID: 1, Name: "small", LastContact: contact, Labels: map[string]string{"platform": "linux/amd64"}, Resources: model.Resources{CPU: 2000}}
+	arm := &model.Agent{ID: 2, Name: "arm", LastContact: contact, Labels: map[string]string{"platform": "linux/arm64"}}
+	paused := &model.Agent{ID: 3, Name: "paused", LastContact: contact, NoSchedule: true}
+	offline := &model.Agent{ID: 5, Name: "offline", LastContact: now.Add(-2 * AgentContactTimeout).Unix(), Labels: map[string]string{"platform": "linux/amd64"}}
+
+	mismatches := ExplainAgents(task, []*model.Agent{small, arm, paused, offline}, now)
+	if assert.Len(t, mismatches, 4) {
+		assert.Equal(t, "agent has not enough resources, workflow requests cpu=4000m memory=0 disk=0 but agent has cpu=2000m memory=0 disk=0", mismatches[0].Reason)
+		assert.Equal(t, "label 'platform': agent has 'linux/arm64', want 'linux/amd64'", mismatches[1].Reason)
+		assert.Equal(t, "agent does not accept new workflows", mismatches[2].Reason)
+		assert.Equal(t, "agent is offline", mismatches[3].Reason)
+	}
+
+	big := &model.Agent{ID: 4, Name: "big", LastContact: contact, Labels: map[string]string{"platform": "linux/amd64"}}
+	assert.Nil(t, ExplainAgents(task, []*model.Agent{small, big}, now), "expect no explanation if an agent is eligible")
+}
@@ -0,0 +1,167 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package queue
+
+import (
+	"fmt"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+)
+
+// Queue states of a task.
+const (
+	TaskStatePending        = "pending"
+	TaskStateWaitingOnDeps  = "waiting_on_deps"
+	TaskStateWaitingOnLimit = "waiting_on_limit"
+	TaskStateRunning        = "running"
+)
+
+// Explanation explains why a task is not running yet.
+type Explanation struct {
+	TaskID string `json:"task_id"`
+	// Workflow is the name of the workflow of the task.
+	Workflow string `json:"workflow"`
+	State    string `json:"state"`
+	// Reasons contains why the task is held back independent of the agents.
+	Reasons []string `json:"reasons,omitempty"`
+	// WaitingFor contains the unfinished dependencies of the task.
+	WaitingFor []string           `json:"waiting_for,omitempty"`
+	Agents     []AgentExplanation `json:"agents"`
+} //	@name QueueExplanation
+
+// AgentExplanation explains whether a connected agent can run a task.
+type AgentExplanation struct {
+	AgentID   int64  `json:"agent_id"`
+	AgentName string `json:"agent_name"`
+	Eligible  bool   `json:"eligible"`
+	Reason    string `json:"reason,omitempty"`
+} //	@name AgentExplanation
+
+// Explain explains why the task with the given id is not running yet, based on
+// the queue info and the agents. Only agents with workers that are connected to
+// the queue are taken into account. It returns nil if the task is not queued.
+func Explain(info InfoT, taskID string, agents []*model.Agent) *Explanation {
+	task, state := findTask(info, taskID)
+	if task == nil {
+		return nil
+	}
+
+	e := &Explanation{
+		TaskID: task.ID,
+		State:  state,
+		Agents: []AgentExplanation{},
+	}
+	if state == TaskStateRunning {
+		return e
+	}
+
+	if info.Paused {
+		e.Reasons = append(e.Reasons, "queue is paused")
+	}
+	switch state {
+	case TaskStateWaitingOnDeps:
+		e.WaitingFor = unfinishedDeps(info, task)
+		e.Reasons = append(e.Reasons, "dependencies are still running")
+	case TaskStateWaitingOnLimit:
+		e.Reasons = append(e.Reasons, limitReason(info, task))
+	}
+
+	running := make(map[int64][]*model.Task)
+	for _, t := range info.Running {
+		running[t.AgentID] = append(running[t.AgentID], t)
+	}
+
+	eligible := false
+	for _, agent := range agents {
+		idle := info.IdleWorkers[agent.ID]
+		if idle == 0 && len(running[agent.ID]) == 0 {
+			// agent is not connected
+			continue
+		}
+
+		ae := AgentExplanation{
+			AgentID:   agent.ID,
+			AgentName: agent.Name,
+		}
+		if reason := agentMismatch(task, agent); reason != "" {
+			ae.Reason = reason
+		} else {
+			ae.Eligible = true
+			eligible = true
+
+			var used model.Resources
+			for _, t := range running[agent.ID] {
+				used = used.Add(t.Resources)
+			}
+			switch {
+			case idle == 0:
+				ae.Reason = fmt.Sprintf("agent capacity is full, %d workflows are running", len(running[agent.ID]))
+			case !agent.Resources.Fits(used, task.Resources):
+				ae.Reason = fmt.Sprintf("agent has not enough free resources, workflow requests %s but %s of %s are used",
+					formatResources(task.Resources), formatResources(used), formatResources(agent.Resources))
+			}
+		}
+		e.Agents = append(e.Agents, ae)
+	}
+
+	if !eligible {
+		e.Reasons = append(e.Reasons, "no connected agent can run the workflow")
+	}
+
+	return e
+}
+
+func findTask(info InfoT, taskID string) (*model.Task, string) {
+	for _, l := range []struct {
+		state string
+		tasks []*model.Task
+	}{
+		{TaskStatePending, info.Pending},
+		{TaskStateWaitingOnDeps, info.WaitingOnDeps},
+		{TaskStateWaitingOnLimit, info.WaitingOnLimit},
+		{TaskStateRunning, info.Running},
+	} {
+		for _, task := range l.tasks {
+			if task.ID == taskID {
+				return task, l.state
+			}
+		}
+	}
+	return nil, ""
+}
+
+func unfinishedDeps(info InfoT, task *model.Task) []string {
+	var deps []string
+	for _, dep := range task.Dependencies {
+		if t, _ := findTask(info, dep); t != nil {
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
+
+func limitReason(info InfoT, task *model.Task) string {
+	if task.ConcurrencyGroup != "" {
+		for _, t := range info.Running {
+			if groupKey(t) == groupKey(task) {
+				return fmt.Sprintf("concurrency group '%s' is in use by another workflow", task.ConcurrencyGroup)
+			}
+		}
+	}
+	return "concurrency limit of the repository or organization is reached"
+}
@@ -0,0 +1,75 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package queue
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+)
+
+func TestExplain(t *testing.T) {
+	linux := map[string]string{"platform": "linux/amd64"}
+	build := &model.Task{ID: "1", RepoID: 1, Labels: linux, Resources: model.Resources{CPU: 2000}}
+	deploy := &model.Task{ID: "2", RepoID: 1, Labels: linux, Dependencies: []string{"1"}}
+	release := &model.Task{ID: "3", RepoID: 1, Labels: linux, ConcurrencyGroup: "release"}
+	running := &model.Task{ID: "4", RepoID: 1, Labels: linux, ConcurrencyGroup: "release", AgentID: 1, Resources: model.Resources{CPU: 3000}}
+
+	busy := &model.Agent{ID: 1, Name: "busy", Labels: linux, Resources: model.Resources{CPU: 4000}}
+	full := &model.Agent{ID: 2, Name: "full", Labels: linux}
+	arm := &model.Agent{ID: 3, Name: "arm", Labels: map[string]string{"platform": "linux/arm64"}}
+	offline := &model.Agent{ID: 4, Name: "offline", Labels: linux}
+	agents := []*model.Agent{busy, full, arm, offline}
+
+	info := InfoT{
+		Pending:        []*model.Task{build},
+		WaitingOnDeps:  []*model.Task{deploy},
+		WaitingOnLimit: []*model.Task{release},
+		Running:        []*model.Task{running, {ID: "5", AgentID: 2}},
+		IdleWorkers:    map[int64]int{1: 1, 3: 1},
+		Paused:         true,
+	}
+
+	assert.Nil(t, Explain(info, "6", agents))
+	assert.Equal(t, &Explanation{TaskID: "4", State: TaskStateRunning, Agents: []AgentExplanation{}}, Explain(info, "4", agents))
+
+	e := Explain(info, "1", agents)
+	assert.Equal(t, TaskStatePending, e.State)
+	assert.Equal(t, []string{"queue is paused"}, e.Reasons)
+	assert.Equal(t, []AgentExplanation{
+		{AgentID: 1, AgentName: "busy", Eligible: true, Reason: "agent has not enough free resources, workflow requests cpu=2000m memory=0 disk=0 but cpu=3000m memory=0 disk=0 of cpu=4000m memory=0 disk=0 are used"},
+		{AgentID: 2, AgentName: "full", Eligible: true, Reason: "agent capacity is full, 1 workflows are running"},
+		{AgentID: 3, AgentName: "arm", Reason: "label 'platform': agent has 'linux/arm64', want 'linux/amd64'"},
+	}, e.Agents)
+
+	info.Paused = false
+	e = Explain(info, "2", agents)
+	assert.Equal(t, TaskStateWaitingOnDeps, e.State)
+	assert.Equal(t, []string{"dependencies are still running"}, e.Reasons)
+	assert.Equal(t, []string{"1"}, e.WaitingFor)
+	assert.Equal(t, AgentExplanation{AgentID: 1, AgentName: "busy", Eligible: true}, e.Agents[0])
+
+	e = Explain(info, "3", agents)
+	assert.Equal(t, TaskStateWaitingOnLimit, e.State)
+	assert.Equal(t, []string{"concurrency group 'release' is in use by another workflow"}, e.Reasons)
+
+	e = Explain(info, "1", []*model.Agent{arm})
+	assert.Equal(t, []string{"no connected agent can run the workflow"}, e.Reasons)
+}
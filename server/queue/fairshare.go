@@ -0,0 +1,157 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package queue
+
+import (
+	"container/list"
+	"sort"
+	"time"
+
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/rs/zerolog/log"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/store"
+)
+
+// WeightFn returns the fair-share weight of an org.
+type WeightFn func(orgID int64) int
+
+// TenantShare describes the part of the running work that belongs to an org.
+type TenantShare struct {
+	OrgID     int64       `json:"org_id"`
+	Weight    int         `json:"weight"`
+	Running   int         `json:"running"`
+	Pending   int         `json:"pending"`
+	Share     float64     `json:"share"`
+	FairShare float64     `json:"fair_share"`
+	Repos     []RepoShare `json:"repos"`
+} //	@name TenantShare
+
+// RepoShare describes the part of the running work of an org that belongs to a repo.
+type RepoShare struct {
+	RepoID  int64 `json:"repo_id"`
+	Running int   `json:"running"`
+	Pending int   `json:"pending"`
+} //	@name RepoShare
+
+const orgWeightCacheTTL = time.Minute
+
+// OrgWeightsFromStore returns a WeightFn reading the weight from the org settings.
+// Weights are cached, so the store is rarely hit while the queue is locked.
+func OrgWeightsFromStore(s store.Store) WeightFn {
+	cache := ttlcache.New(ttlcache.WithDisableTouchOnHit[int64, int]())
+	return func(orgID int64) int {
+		item := cache.Get(orgID)
+		if item != nil && !item.IsExpired() {
+			return item.Value()
+		}
+
+		weight := 1
+		org, err := s.OrgGet(orgID)
+		if err != nil {
+			log.Error().Err(err).Int64("org-id", orgID).Msg("queue: could not get org weight")
+		} else {
+			weight = org.Weight
+		}
+		cache.Set(orgID, weight, orgWeightCacheTTL)
+		return weight
+	}
+}
+
+// candidate is a pending task that could be handed out to a worker.
+type candidate struct {
+	element     *list.Element
+	worker      *worker
+	priority    int
+	orgShare    float64
+	repoRunning int
+}
+
+// before reports whether c should be handed out before o. With fair-share enabled the
+// org and repo using the least of their share come first, then the task priority.
+func (c *candidate) before(o *candidate) bool {
+	if c.orgShare != o.orgShare {
+		return c.orgShare < o.orgShare
+	}
+	if c.repoRunning != o.repoRunning {
+		return c.repoRunning < o.repoRunning
+	}
+	return c.priority > o.priority
+}
+
+func (q *fifo) shares() []TenantShare {
+	if q.weight == nil {
+		return nil
+	}
+	u := q.runningUsage()
+
+	tenants := map[int64]*TenantShare{}
+	repos := map[int64]*RepoShare{}
+	repoOrgs := map[int64]int64{}
+	count := func(task *model.Task) (*TenantShare, *RepoShare) {
+		t, ok := tenants[task.OrgID]
+		if !ok {
+			t = &TenantShare{OrgID: task.OrgID, Weight: u.weightOf(task.OrgID)}
+			tenants[task.OrgID] = t
+		}
+		r, ok := repos[task.RepoID]
+		if !ok {
+			r = &RepoShare{RepoID: task.RepoID}
+			repos[task.RepoID] = r
+			repoOrgs[task.RepoID] = task.OrgID
+		}
+		return t, r
+	}
+
+	for _, running := range q.running {
+		t, r := count(running.item)
+		t.Running++
+		r.Running++
+	}
+	for _, l := range []*list.List{q.pending, q.waitingOnDeps, q.waitingOnLimit} {
+		for e := l.Front(); e != nil; e = e.Next() {
+			task, _ := e.Value.(*model.Task)
+			t, r := count(task)
+			t.Pending++
+			r.Pending++
+		}
+	}
+
+	for repoID, r := range repos {
+		t := tenants[repoOrgs[repoID]]
+		t.Repos = append(t.Repos, *r)
+	}
+
+	totalWeight := 0
+	for _, t := range tenants {
+		totalWeight += t.Weight
+	}
+
+	shares := make([]TenantShare, 0, len(tenants))
+	for _, t := range tenants {
+		if len(q.running) > 0 {
+			t.Share = float64(t.Running) / float64(len(q.running))
+		}
+		t.FairShare = float64(t.Weight) / float64(totalWeight)
+		sort.Slice(t.Repos, func(i, j int) bool { return t.Repos[i].RepoID < t.Repos[j].RepoID })
+		shares = append(shares, *t)
+	}
+	sort.Slice(shares, func(i, j int) bool { return shares[i].OrgID < shares[j].OrgID })
+	return shares
+}
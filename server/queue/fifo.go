@@ -38,34 +38,68 @@ type worker struct {
 	filter  FilterFn
 	channel chan *model.Task
 	stop    context.CancelCauseFunc
+	// gone is set once the worker stopped polling
+	gone bool
 }
 
+// assignment is a task reserved for a worker until the task is leased.
+type assignment struct {
+	task   *model.Task
+	worker *worker
+	entry  *entry
+}
+
+// Results of leasing the task of an assignment.
+const (
+	leaseAcquired = iota
+	leaseTaken
+	leaseFailed
+)
+
 type fifo struct {
 	sync.Mutex
 
-	ctx           context.Context
-	workers       map[*worker]struct{}
-	running       map[string]*entry
-	pending       *list.List
-	waitingOnDeps *list.List
-	extension     time.Duration
-	paused        bool
+	ctx            context.Context
+	workers        map[*worker]struct{}
+	running        map[string]*entry
+	pending        *list.List
+	waitingOnDeps  *list.List
+	waitingOnLimit *list.List
+	enqueued       map[string]time.Time
+	pendingSince   map[string]time.Time
+	extension      time.Duration
+	aging          time.Duration
+	weight         WeightFn
+	limit          LimitFn
+	resources      ResourcesFn
+	leaser         Leaser
+	paused         bool
 }
 
 // processTimeInterval is the time till the queue rearranges things,
 // as the agent pull in  10 milliseconds we should also give them work asap.
 const processTimeInterval = 100 * time.Millisecond
 
+// DefaultPriorityAging is the default time a pending task has to wait to gain one priority level.
+const DefaultPriorityAging = 6 * time.Second
+
 // New returns a new fifo queue.
-func New(ctx context.Context) Queue {
+func New(ctx context.Context, opts ...Option) Queue {
 	q := &fifo{
-		ctx:           ctx,
-		workers:       map[*worker]struct{}{},
-		running:       map[string]*entry{},
-		pending:       list.New(),
-		waitingOnDeps: list.New(),
-		extension:     time.Minute * 10, //nolint:mnd
-		paused:        false,
+		ctx:            ctx,
+		workers:        map[*worker]struct{}{},
+		running:        map[string]*entry{},
+		pending:        list.New(),
+		waitingOnDeps:  list.New(),
+		waitingOnLimit: list.New(),
+		enqueued:       map[string]time.Time{},
+		pendingSince:   map[string]time.Time{},
+		extension:      time.Minute * 10, //nolint:mnd
+		aging:          DefaultPriorityAging,
+		paused:         false,
+	}
+	for _, opt := range opts {
+		opt(q)
 	}
 	go q.process()
 	return q
@@ -74,7 +108,7 @@ func New(ctx context.Context) Queue {
 // Push pushes a task to the tail of this queue.
 func (q *fifo) Push(_ context.Context, task *model.Task) error {
 	q.Lock()
-	q.pending.PushBack(task)
+	q.pushPending(task)
 	q.Unlock()
 	return nil
 }
@@ -83,12 +117,24 @@ func (q *fifo) Push(_ context.Context, task *model.Task) error {
 func (q *fifo) PushAtOnce(_ context.Context, tasks []*model.Task) error {
 	q.Lock()
 	for _, task := range tasks {
-		q.pending.PushBack(task)
+		q.pushPending(task)
 	}
 	q.Unlock()
 	return nil
 }
 
+func (q *fifo) pushPending(task *model.Task) {
+	if _, ok := q.enqueued[task.ID]; !ok {
+		// tasks restored from the store after a restart keep their age
+		since := time.Now()
+		if task.Created > 0 {
+			since = time.Unix(task.Created, 0)
+		}
+		q.enqueued[task.ID] = since
+	}
+	q.pending.PushBack(task)
+}
+
 // Poll retrieves and removes a task head of this queue.
 func (q *fifo) Poll(c context.Context, agentID int64, f FilterFn) (*model.Task, error) {
 	q.Lock()
@@ -108,6 +154,7 @@ func (q *fifo) Poll(c context.Context, agentID int64, f FilterFn) (*model.Task,
 		case <-ctx.Done():
 			q.Lock()
 			delete(q.workers, w)
+			w.gone = true
 			q.Unlock()
 			return nil, ctx.Err()
 		case t := <-w.channel:
@@ -135,6 +182,8 @@ func (q *fifo) finished(ids []string, exitStatus model.StatusValue, err error) e
 	q.Lock()
 
 	for _, id := range ids {
+		delete(q.enqueued, id)
+		delete(q.pendingSince, id)
 		taskEntry, ok := q.running[id]
 		if ok {
 			taskEntry.error = err
@@ -161,13 +210,17 @@ func (q *fifo) EvictAtOnce(_ context.Context, ids []string) error {
 	defer q.Unlock()
 
 	for _, id := range ids {
-		var next *list.Element
-		for e := q.pending.Front(); e != nil; e = next {
-			next = e.Next()
-			task, ok := e.Value.(*model.Task)
-			if ok && task.ID == id {
-				q.pending.Remove(e)
-				return nil
+		for _, l := range []*list.List{q.pending, q.waitingOnLimit} {
+			var next *list.Element
+			for e := l.Front(); e != nil; e = next {
+				next = e.Next()
+				task, ok := e.Value.(*model.Task)
+				if ok && task.ID == id {
+					l.Remove(e)
+					delete(q.enqueued, id)
+					delete(q.pendingSince, id)
+					return nil
+				}
 			}
 		}
 	}
@@ -192,14 +245,19 @@ func (q *fifo) Wait(c context.Context, id string) error {
 // Extend extends the task execution deadline.
 func (q *fifo) Extend(_ context.Context, id string) error {
 	q.Lock()
-	defer q.Unlock()
-
 	state, ok := q.running[id]
-	if ok {
-		state.deadline = time.Now().Add(q.extension)
-		return nil
+	if !ok {
+		q.Unlock()
+		return ErrNotFound
 	}
-	return ErrNotFound
+	state.deadline = time.Now().Add(q.extension)
+	deadline := state.deadline
+	q.Unlock()
+
+	if q.leaser != nil {
+		return q.leaser.ExtendLease(id, deadline)
+	}
+	return nil
 }
 
 // Info returns internal queue information.
@@ -209,19 +267,36 @@ func (q *fifo) Info(_ context.Context) InfoT {
 	stats.Stats.Workers = len(q.workers)
 	stats.Stats.Pending = q.pending.Len()
 	stats.Stats.WaitingOnDeps = q.waitingOnDeps.Len()
+	stats.Stats.WaitingOnLimit = q.waitingOnLimit.Len()
 	stats.Stats.Running = len(q.running)
+	stats.Priorities = make(map[string]int, q.pending.Len())
+	stats.IdleWorkers = make(map[int64]int)
+	stats.PendingSince = make(map[string]time.Time, len(q.pendingSince))
+	for id, since := range q.pendingSince {
+		stats.PendingSince[id] = since
+	}
+	for w := range q.workers {
+		stats.IdleWorkers[w.agentID]++
+	}
 
+	now := time.Now()
 	for e := q.pending.Front(); e != nil; e = e.Next() {
 		task, _ := e.Value.(*model.Task)
 		stats.Pending = append(stats.Pending, task)
+		stats.Priorities[task.ID] = q.effectivePriority(task, now)
 	}
 	for e := q.waitingOnDeps.Front(); e != nil; e = e.Next() {
 		task, _ := e.Value.(*model.Task)
 		stats.WaitingOnDeps = append(stats.WaitingOnDeps, task)
 	}
+	for e := q.waitingOnLimit.Front(); e != nil; e = e.Next() {
+		task, _ := e.Value.(*model.Task)
+		stats.WaitingOnLimit = append(stats.WaitingOnLimit, task)
+	}
 	for _, entry := range q.running {
 		stats.Running = append(stats.Running, entry.item)
 	}
+	stats.Shares = q.shares()
 	stats.Paused = q.paused
 
 	q.Unlock()
@@ -273,19 +348,98 @@ func (q *fifo) process() {
 
 		q.resubmitExpiredPipelines()
 		q.filterWaiting()
+		q.filterLimited()
+		var assignments []*assignment
 		for pending, worker := q.assignToWorker(); pending != nil && worker != nil; pending, worker = q.assignToWorker() {
 			task, _ := pending.Value.(*model.Task)
-			task.AgentID = worker.agentID
+			// the task and the worker are reserved until the task is leased
+			a := &assignment{
+				task:   task,
+				worker: worker,
+				entry: &entry{
+					item:     task,
+					done:     make(chan bool),
+					deadline: time.Now().Add(q.extension),
+				},
+			}
 			delete(q.workers, worker)
 			q.pending.Remove(pending)
-			q.running[task.ID] = &entry{
-				item:     task,
-				done:     make(chan bool),
-				deadline: time.Now().Add(q.extension),
-			}
-			worker.channel <- task
+			q.running[task.ID] = a.entry
+			assignments = append(assignments, a)
 		}
 		q.Unlock()
+
+		if len(assignments) > 0 {
+			q.assign(assignments)
+		}
+	}
+}
+
+// assign leases the tasks of the assignments and hands them out to their
+// workers. Leasing needs a round trip to the store, so it is done without
+// holding the lock.
+func (q *fifo) assign(assignments []*assignment) {
+	results := make([]int, len(assignments))
+	for i, a := range assignments {
+		if q.leaser == nil {
+			results[i] = leaseAcquired
+			continue
+		}
+		if i > 0 && results[i-1] == leaseFailed {
+			results[i] = leaseFailed
+			continue
+		}
+		leased, err := q.leaser.Lease(a.task, a.worker.agentID, a.entry.deadline)
+		switch {
+		case err != nil:
+			log.Error().Err(err).Msgf("queue: cannot lease task %s", a.task.ID)
+			results[i] = leaseFailed
+		case !leased:
+			// another server handed out the task, it runs there
+			log.Debug().Msgf("queue: task %s is leased by another server", a.task.ID)
+			results[i] = leaseTaken
+		}
+	}
+
+	var release []string
+	q.Lock()
+	for i, a := range assignments {
+		// the task may have been finished and the worker may have stopped polling meanwhile
+		finished := q.running[a.task.ID] != a.entry
+		switch {
+		case results[i] == leaseTaken:
+			if !finished {
+				delete(q.pendingSince, a.task.ID)
+			}
+			q.returnWorker(a.worker)
+		case results[i] == leaseFailed || finished || a.worker.gone:
+			if results[i] == leaseAcquired && q.leaser != nil {
+				release = append(release, a.task.ID)
+			}
+			if !finished {
+				delete(q.running, a.task.ID)
+				q.pending.PushFront(a.task)
+			}
+			q.returnWorker(a.worker)
+		default:
+			a.task.AgentID = a.worker.agentID
+			delete(q.pendingSince, a.task.ID)
+			a.worker.channel <- a.task
+		}
+	}
+	q.Unlock()
+
+	for _, id := range release {
+		if err := q.leaser.ExtendLease(id, time.Unix(0, 0)); err != nil {
+			log.Error().Err(err).Msgf("queue: cannot release lease of task %s", id)
+		}
+	}
+}
+
+// returnWorker makes a reserved worker available again if it is still polling.
+func (q *fifo) returnWorker(w *worker) {
+	if !w.gone {
+		q.workers[w] = struct{}{}
 	}
 }
 
@@ -309,6 +463,8 @@ func (q *fifo) filterWaiting() {
 			log.Debug().Msgf("queue: waiting due to unmet dependencies %v", task.ID)
 			q.waitingOnDeps.PushBack(task)
 			filtered = append(filtered, e)
+		} else if _, ok := q.pendingSince[task.ID]; !ok {
+			q.pendingSince[task.ID] = time.Now()
 		}
 	}
 
@@ -318,22 +474,64 @@ func (q *fifo) filterWaiting() {
 	}
 }
 
+// assignToWorker picks the pending task with the highest effective priority
+// that can be handed out to one of the waiting workers. Tasks with the same
+// priority are assigned in the order they were pushed. With fair-share enabled,
+// tasks of orgs and repos that use less than their share are preferred.
+// Tasks whose concurrency group is running or whose repo or org reached its
+// concurrency limit are skipped. Tasks are only handed out to workers of agents
+// with enough free resources.
 func (q *fifo) assignToWorker() (*list.Element, *worker) {
+	var assigned *candidate
+
+	now := time.Now()
+	usage := q.runningUsage()
 	var next *list.Element
 	for e := q.pending.Front(); e != nil; e = next {
 		next = e.Next()
 		task, _ := e.Value.(*model.Task)
-		log.Debug().Msgf("queue: trying to assign task: %v with deps %v", task.ID, task.Dependencies)
+		if usage.limited(task) {
+			continue
+		}
+		c := &candidate{
+			element:  e,
+			priority: q.effectivePriority(task, now),
+		}
+		if q.weight != nil {
+			c.orgShare = usage.orgShare(task.OrgID)
+			c.repoRunning = usage.repos[task.RepoID]
+		}
+		if assigned != nil && !c.before(assigned) {
+			continue
+		}
+		log.Debug().Msgf("queue: trying to assign task: %v with deps %v and priority %d", task.ID, task.Dependencies, c.priority)
 
 		for w := range q.workers {
-			if w.filter(task) {
-				log.Debug().Msgf("queue: assigned task: %v with deps %v", task.ID, task.Dependencies)
-				return e, w
+			if w.filter(task) && usage.fits(task, w.agentID) {
+				c.worker = w
+				assigned = c
+				break
 			}
 		}
 	}
 
-	return nil, nil
+	if assigned == nil {
+		return nil, nil
+	}
+
+	task, _ := assigned.element.Value.(*model.Task)
+	log.Debug().Msgf("queue: assigned task: %v with deps %v and priority %d", task.ID, task.Dependencies, assigned.priority)
+	return assigned.element, assigned.worker
+}
+
+// effectivePriority returns the priority of the task raised by one level for
+// every aging interval it has been waiting, so low priority tasks still make progress.
+func (q *fifo) effectivePriority(task *model.Task, now time.Time) int {
+	priority := task.Priority
+	if since, ok := q.enqueued[task.ID]; ok && q.aging > 0 {
+		priority += int(now.Sub(since) / q.aging)
+	}
+	return priority
 }
 
 func (q *fifo) resubmitExpiredPipelines() {
@@ -347,14 +545,15 @@ func (q *fifo) resubmitExpiredPipelines() {
 }
 
 func (q *fifo) depsInQueue(task *model.Task) bool {
-	var next *list.Element
-	for e := q.pending.Front(); e != nil; e = next {
-		next = e.Next()
-		possibleDep, ok := e.Value.(*model.Task)
-		log.Debug().Msgf("queue: pending right now: %v", possibleDep.ID)
-		for _, dep := range task.Dependencies {
-			if ok && possibleDep.ID == dep {
-				return true
+	// dependencies held back by a concurrency limit are still queued
+	for _, l := range []*list.List{q.pending, q.waitingOnLimit} {
+		for e := l.Front(); e != nil; e = e.Next() {
+			possibleDep, ok := e.Value.(*model.Task)
+			log.Debug().Msgf("queue: pending right now: %v", possibleDep.ID)
+			for _, dep := range task.Dependencies {
+				if ok && possibleDep.ID == dep {
+					return true
+				}
 			}
 		}
 	}
@@ -389,12 +588,14 @@ func (q *fifo) updateDepStatusInQueue(taskID string, status model.StatusValue) {
 		}
 	}
 
-	for e := q.waitingOnDeps.Front(); e != nil; e = next {
-		next = e.Next()
-		waiting, ok := e.Value.(*model.Task)
-		for _, dep := range waiting.Dependencies {
-			if ok && taskID == dep {
-				waiting.DepStatus[dep] = status
+	for _, l := range []*list.List{q.waitingOnDeps, q.waitingOnLimit} {
+		for e := l.Front(); e != nil; e = next {
+			next = e.Next()
+			waiting, ok := e.Value.(*model.Task)
+			for _, dep := range waiting.Dependencies {
+				if ok && taskID == dep {
+					waiting.DepStatus[dep] = status
+				}
 			}
 		}
 	}
@@ -402,14 +603,16 @@ func (q *fifo) updateDepStatusInQueue(taskID string, status model.StatusValue) {
 
 func (q *fifo) removeFromPending(taskID string) {
 	log.Debug().Msgf("queue: trying to remove %s", taskID)
-	var next *list.Element
-	for e := q.pending.Front(); e != nil; e = next {
-		next = e.Next()
-		task, _ := e.Value.(*model.Task)
-		if task.ID == taskID {
-			log.Debug().Msgf("queue: %s is removed from pending", taskID)
-			q.pending.Remove(e)
-			return
+	for _, l := range []*list.List{q.pending, q.waitingOnDeps, q.waitingOnLimit} {
+		var next *list.Element
+		for e := l.Front(); e != nil; e = next {
+			next = e.Next()
+			task, _ := e.Value.(*model.Task)
+			if task.ID == taskID {
+				log.Debug().Msgf("queue: %s is removed from pending", taskID)
+				l.Remove(e)
+				return
+			}
 		}
 	}
 }
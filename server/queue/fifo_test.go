@@ -435,6 +435,265 @@ func TestWaitingVsPending(t *testing.T) {
 	assert.Equal(t, 1, info.Stats.Pending)
 }
 
+func TestFifoPriority(t *testing.T) {
+	cron := &model.Task{
+		ID:       "1",
+		Priority: model.TaskPriorityLow,
+	}
+
+	push := &model.Task{
+		ID:       "2",
+		Priority: model.TaskPriorityHigh,
+	}
+
+	q, _ := New(context.Background()).(*fifo)
+	assert.NoError(t, q.PushAtOnce(noContext, []*model.Task{cron, push}))
+
+	got, err := q.Poll(noContext, 1, func(*model.Task) bool { return true })
+	assert.NoError(t, err)
+	assert.Equal(t, push, got, "expect task with the higher priority to be handed out first")
+
+	got, err = q.Poll(noContext, 1, func(*model.Task) bool { return true })
+	assert.NoError(t, err)
+	assert.Equal(t, cron, got)
+}
+
+func TestFifoPriorityAging(t *testing.T) {
+	// the cron task was created long enough ago to overtake the push task,
+	// e.g. because it was restored from the store after a restart
+	cron := &model.Task{
+		ID:       "1",
+		Priority: model.TaskPriorityLow,
+		Created:  time.Now().Add(-2 * time.Minute).Unix(),
+	}
+
+	push := &model.Task{
+		ID:       "2",
+		Priority: model.TaskPriorityHigh,
+	}
+
+	q, _ := New(context.Background(), WithPriorityAging(time.Second)).(*fifo)
+	q.Pause()
+	assert.NoError(t, q.PushAtOnce(noContext, []*model.Task{cron, push}))
+
+	info := q.Info(noContext)
+	assert.GreaterOrEqual(t, info.Priorities[cron.ID], 120)
+	assert.Less(t, info.Priorities[push.ID], 120)
+
+	q.Resume()
+	got, err := q.Poll(noContext, 1, func(*model.Task) bool { return true })
+	assert.NoError(t, err)
+	assert.Equal(t, cron, got, "expect aged task to be handed out first")
+}
+
+func TestFifoFairShare(t *testing.T) {
+	weights := map[int64]int{1: 1, 2: 1}
+	q, _ := New(context.Background(), WithFairShare(func(orgID int64) int { return weights[orgID] })).(*fifo)
+
+	busy1 := &model.Task{ID: "1", OrgID: 1, RepoID: 1}
+	busy2 := &model.Task{ID: "2", OrgID: 1, RepoID: 1}
+	assert.NoError(t, q.PushAtOnce(noContext, []*model.Task{busy1, busy2}))
+	for i := 0; i < 2; i++ {
+		_, err := q.Poll(noContext, 1, func(*model.Task) bool { return true })
+		assert.NoError(t, err)
+	}
+
+	busyOrg := &model.Task{ID: "3", OrgID: 1, RepoID: 1, Priority: model.TaskPriorityHigh}
+	idleOrg := &model.Task{ID: "4", OrgID: 2, RepoID: 2, Priority: model.TaskPriorityLow}
+	assert.NoError(t, q.PushAtOnce(noContext, []*model.Task{busyOrg, idleOrg}))
+
+	got, err := q.Poll(noContext, 1, func(*model.Task) bool { return true })
+	assert.NoError(t, err)
+	assert.Equal(t, idleOrg, got, "expect task of the org without running tasks to be handed out first")
+
+	info := q.Info(noContext)
+	if assert.Len(t, info.Shares, 2) {
+		assert.Equal(t, int64(1), info.Shares[0].OrgID)
+		assert.Equal(t, 2, info.Shares[0].Running)
+		assert.Equal(t, 1, info.Shares[0].Pending)
+		assert.InDelta(t, 2.0/3.0, info.Shares[0].Share, 0.001)
+		assert.InDelta(t, 0.5, info.Shares[0].FairShare, 0.001)
+		assert.Equal(t, int64(2), info.Shares[1].OrgID)
+		assert.Equal(t, 1, info.Shares[1].Running)
+	}
+}
+
+func TestFifoFairShareWeights(t *testing.T) {
+	weights := map[int64]int{1: 3, 2: 1}
+	q, _ := New(context.Background(), WithFairShare(func(orgID int64) int { return weights[orgID] })).(*fifo)
+
+	running := []*model.Task{
+		{ID: "1", OrgID: 1, RepoID: 1},
+		{ID: "2", OrgID: 1, RepoID: 1},
+		{ID: "3", OrgID: 2, RepoID: 2},
+	}
+	assert.NoError(t, q.PushAtOnce(noContext, running))
+	for range running {
+		_, err := q.Poll(noContext, 1, func(*model.Task) bool { return true })
+		assert.NoError(t, err)
+	}
+
+	light := &model.Task{ID: "4", OrgID: 2, RepoID: 2}
+	heavy := &model.Task{ID: "5", OrgID: 1, RepoID: 3}
+	assert.NoError(t, q.PushAtOnce(noContext, []*model.Task{light, heavy}))
+
+	got, err := q.Poll(noContext, 1, func(*model.Task) bool { return true })
+	assert.NoError(t, err)
+	assert.Equal(t, heavy, got, "expect org with the higher weight to get more running tasks")
+}
+
+func TestFifoConcurrencyLimits(t *testing.T) {
+	limits := func(repoID, _ int64) (int, int) {
+		if repoID == 1 {
+			return 1, 0
+		}
+		return 0, 2
+	}
+	q, _ := New(context.Background(), WithConcurrencyLimits(limits)).(*fifo)
+
+	first := &model.Task{ID: "1", OrgID: 1, RepoID: 1}
+	second := &model.Task{ID: "2", OrgID: 1, RepoID: 1}
+	assert.NoError(t, q.PushAtOnce(noContext, []*model.Task{first, second}))
+
+	got, err := q.Poll(noContext, 1, func(*model.Task) bool { return true })
+	assert.NoError(t, err)
+	assert.Equal(t, first, got)
+
+	time.Sleep(2 * processTimeInterval)
+	info := q.Info(noContext)
+	assert.Len(t, info.Pending, 0, "expect task above the repo limit not to be pending")
+	assert.Len(t, info.WaitingOnLimit, 1, "expect task above the repo limit to wait")
+
+	other := &model.Task{ID: "3", OrgID: 1, RepoID: 2}
+	assert.NoError(t, q.Push(noContext, other))
+	got, err = q.Poll(noContext, 1, func(*model.Task) bool { return true })
+	assert.NoError(t, err)
+	assert.Equal(t, other, got, "expect task of another repo to run")
+
+	assert.NoError(t, q.Done(noContext, got.ID, model.StatusSuccess))
+	assert.NoError(t, q.Done(noContext, first.ID, model.StatusSuccess))
+	got, err = q.Poll(noContext, 1, func(*model.Task) bool { return true })
+	assert.NoError(t, err)
+	assert.Equal(t, second, got, "expect waiting task to run once the limit allows")
+}
+
+func TestFifoConcurrencyLimitsDependencies(t *testing.T) {
+	limits := func(repoID, _ int64) (int, int) {
+		if repoID == 1 {
+			return 1, 0
+		}
+		return 0, 0
+	}
+	q, _ := New(context.Background(), WithConcurrencyLimits(limits)).(*fifo)
+
+	running := &model.Task{ID: "1", OrgID: 1, RepoID: 1}
+	assert.NoError(t, q.Push(noContext, running))
+	_, err := q.Poll(noContext, 1, func(*model.Task) bool { return true })
+	assert.NoError(t, err)
+
+	dep := &model.Task{ID: "2", OrgID: 1, RepoID: 1}
+	dependent := &model.Task{
+		ID:           "3",
+		OrgID:        1,
+		RepoID:       2,
+		Dependencies: []string{"2"},
+		DepStatus:    make(map[string]model.StatusValue),
+		RunOn:        []string{"success"},
+	}
+	assert.NoError(t, q.PushAtOnce(noContext, []*model.Task{dep, dependent}))
+
+	time.Sleep(2 * processTimeInterval)
+	info := q.Info(noContext)
+	assert.Len(t, info.WaitingOnLimit, 1, "expect dependency to wait on the repo limit")
+	assert.Len(t, info.WaitingOnDeps, 1, "expect dependent task to wait on its dependency")
+	assert.Len(t, info.Pending, 0)
+
+	assert.NoError(t, q.Done(noContext, running.ID, model.StatusSuccess))
+	got, err := q.Poll(noContext, 1, func(*model.Task) bool { return true })
+	assert.NoError(t, err)
+	assert.Equal(t, dep, got, "expect dependency to run before the dependent task")
+
+	assert.NoError(t, q.Done(noContext, dep.ID, model.StatusSuccess))
+	got, err = q.Poll(noContext, 1, func(*model.Task) bool { return true })
+	assert.NoError(t, err)
+	assert.Equal(t, dependent, got)
+	assert.Equal(t, model.StatusSuccess, got.DepStatus[dep.ID])
+}
+
+func TestFifoConcurrencyLimitsOrg(t *testing.T) {
+	q, _ := New(context.Background(), WithConcurrencyLimits(func(_, _ int64) (int, int) { return 0, 1 })).(*fifo)
+
+	running := &model.Task{ID: "1", OrgID: 1, RepoID: 1}
+	assert.NoError(t, q.Push(noContext, running))
+	_, err := q.Poll(noContext, 1, func(*model.Task) bool { return true })
+	assert.NoError(t, err)
+
+	limited := &model.Task{ID: "2", OrgID: 1, RepoID: 2}
+	assert.NoError(t, q.Push(noContext, limited))
+	time.Sleep(2 * processTimeInterval)
+	assert.Len(t, q.Info(noContext).WaitingOnLimit, 1)
+
+	assert.NoError(t, q.Evict(noContext, limited.ID), "expect waiting task to be evictable")
+	info := q.Info(noContext)
+	assert.Len(t, info.WaitingOnLimit, 0)
+	assert.Len(t, info.Pending, 0)
+}
+
+func TestFifoConcurrencyGroup(t *testing.T) {
+	q, _ := New(context.Background()).(*fifo)
+
+	first := &model.Task{ID: "1", RepoID: 1, ConcurrencyGroup: "deploy-main"}
+	second := &model.Task{ID: "2", RepoID: 1, ConcurrencyGroup: "deploy-main"}
+	otherGroup := &model.Task{ID: "3", RepoID: 1, ConcurrencyGroup: "deploy-dev"}
+	otherRepo := &model.Task{ID: "4", RepoID: 2, ConcurrencyGroup: "deploy-main"}
+	assert.NoError(t, q.PushAtOnce(noContext, []*model.Task{first, second, otherGroup, otherRepo}))
+
+	for _, want := range []*model.Task{first, otherGroup, otherRepo} {
+		got, err := q.Poll(noContext, 1, func(*model.Task) bool { return true })
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+
+	time.Sleep(2 * processTimeInterval)
+	info := q.Info(noContext)
+	if assert.Len(t, info.WaitingOnLimit, 1) {
+		assert.Equal(t, second, info.WaitingOnLimit[0], "expect task of a running concurrency group to wait")
+	}
+
+	assert.NoError(t, q.Done(noContext, first.ID, model.StatusSuccess))
+	got, err := q.Poll(noContext, 1, func(*model.Task) bool { return true })
+	assert.NoError(t, err)
+	assert.Equal(t, second, got, "expect waiting task to run once the group is free")
+}
+
+func TestFifoAgentResources(t *testing.T) {
+	agents := map[int64]model.Resources{
+		1: {CPU: 2000, Memory: 4 << 30},
+		2: {CPU: 32000, Memory: 64 << 30},
+	}
+	q, _ := New(context.Background(), WithAgentResources(func(agentID int64) model.Resources { return agents[agentID] })).(*fifo)
+
+	heavy := &model.Task{ID: "1", Resources: model.Resources{CPU: 8000, Memory: 16 << 30}}
+	assert.NoError(t, q.Push(noContext, heavy))
+
+	ctx, cancel := context.WithTimeout(noContext, 3*processTimeInterval)
+	defer cancel()
+	_, err := q.Poll(ctx, 1, func(*model.Task) bool { return true })
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "expect small agent not to get the heavy task")
+
+	got, err := q.Poll(noContext, 2, func(*model.Task) bool { return true })
+	assert.NoError(t, err)
+	assert.Equal(t, heavy, got)
+
+	light := &model.Task{ID: "2", Resources: model.Resources{CPU: 1000}}
+	other := &model.Task{ID: "3", Resources: model.Resources{CPU: 30000}}
+	assert.NoError(t, q.PushAtOnce(noContext, []*model.Task{other, light}))
+
+	got, err = q.Poll(noContext, 2, func(*model.Task) bool { return true })
+	assert.NoError(t, err)
+	assert.Equal(t, light, got, "expect task to be skipped if the agent has not enough free resources")
+}
+
 func TestShouldRun(t *testing.T) {
 	task := &model.Task{
 		ID:           "2",
@@ -504,3 +763,28 @@ func TestShouldRun(t *testing.T) {
 	}
 	assert.True(t, task.ShouldRun(), "on failure, tasks should run on skipped deps, something failed higher up the chain")
 }
+
+func TestFifoPendingSince(t *testing.T) {
+	q, _ := New(context.Background()).(*fifo)
+
+	build := &model.Task{ID: "1"}
+	deploy := &model.Task{ID: "2", Dependencies: []string{"1"}, DepStatus: make(map[string]model.StatusValue)}
+	assert.NoError(t, q.PushAtOnce(noContext, []*model.Task{build, deploy}))
+
+	time.Sleep(2 * processTimeInterval)
+	info := q.Info(noContext)
+	assert.Contains(t, info.PendingSince, build.ID)
+	assert.NotContains(t, info.PendingSince, deploy.ID, "expect task waiting on deps not to be pending")
+
+	got, err := q.Poll(noContext, 1, func(*model.Task) bool { return true })
+	assert.NoError(t, err)
+	assert.Equal(t, build, got)
+	assert.NotContains(t, q.Info(noContext).PendingSince, build.ID, "expect running task not to be pending")
+
+	assert.NoError(t, q.Done(noContext, build.ID, model.StatusSuccess))
+	time.Sleep(2 * processTimeInterval)
+	assert.Contains(t, q.Info(noContext).PendingSince, deploy.ID)
+
+	assert.NoError(t, q.Evict(noContext, deploy.ID))
+	assert.Empty(t, q.Info(noContext).PendingSince)
+}
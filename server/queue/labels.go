@@ -0,0 +1,178 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package queue
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// agentWildcard is the agent label value that accepts any task label value.
+const agentWildcard = "*"
+
+// literalPrefix marks a task label value as literal value instead of a selector.
+const literalPrefix = "="
+
+// MatchLabels checks the labels of a task against the labels of an agent and
+// returns an error explaining the first task label the agent does not satisfy.
+//
+// Task label values are selectors:
+//   - "value" requires the agent label to equal the value
+//   - "!value" requires the agent label to be missing or to differ from the value
+//   - "in(a,b)" and "notin(a,b)" require the agent label to be in or not in the list
+//   - "exists" and "!exists" require the agent label to be set or missing
+//   - ">n", ">=n", "<n" and "<=n" compare the agent label as number
+//
+// Values starting with "=" are matched literally without the "=", e.g. "=!false"
+// requires the agent label to be "!false".
+//
+// Empty task label values are ignored and the agent label value "*" matches
+// every selector that requires the label to be set.
+func MatchLabels(taskLabels, agentLabels map[string]string) error {
+	keys := make([]string, 0, len(taskLabels))
+	for key := range taskLabels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := taskLabels[key]
+		if value == "" {
+			continue
+		}
+
+		agentValue, ok := agentLabels[key]
+		var err error
+		if literal, isLiteral := strings.CutPrefix(value, literalPrefix); isLiteral {
+			err = matchValue(literal, agentValue, ok)
+		} else {
+			err = matchSelector(strings.TrimSpace(value), agentValue, ok)
+		}
+		if err != nil {
+			return fmt.Errorf("label '%s': %w", key, err)
+		}
+	}
+	return nil
+}
+
+func matchValue(value, agentValue string, exists bool) error {
+	if !exists {
+		return fmt.Errorf("agent does not have the label, want '%s'", value)
+	}
+	if agentValue != agentWildcard && agentValue != value {
+		return fmt.Errorf("agent has '%s', want '%s'", agentValue, value)
+	}
+	return nil
+}
+
+func matchSelector(selector, agentValue string, exists bool) error {
+	switch {
+	case selector == "exists":
+		if !exists {
+			return fmt.Errorf("agent does not have the label")
+		}
+		return nil
+
+	case selector == "!exists":
+		if exists {
+			return fmt.Errorf("agent has the label with value '%s'", agentValue)
+		}
+		return nil
+
+	case strings.HasPrefix(selector, "notin(") && strings.HasSuffix(selector, ")"):
+		values := selectorList(selector, "notin(")
+		if exists && agentValue != agentWildcard && slices.Contains(values, agentValue) {
+			return fmt.Errorf("agent has '%s', want none of %s", agentValue, strings.Join(values, ", "))
+		}
+		return nil
+
+	case strings.HasPrefix(selector, "in(") && strings.HasSuffix(selector, ")"):
+		values := selectorList(selector, "in(")
+		if !exists {
+			return fmt.Errorf("agent does not have the label, want one of %s", strings.Join(values, ", "))
+		}
+		if agentValue != agentWildcard && !slices.Contains(values, agentValue) {
+			return fmt.Errorf("agent has '%s', want one of %s", agentValue, strings.Join(values, ", "))
+		}
+		return nil
+
+	case strings.HasPrefix(selector, "!"):
+		value := selector[1:]
+		if exists && agentValue != agentWildcard && agentValue == value {
+			return fmt.Errorf("agent has '%s', want anything else", agentValue)
+		}
+		return nil
+
+	case strings.HasPrefix(selector, ">") || strings.HasPrefix(selector, "<"):
+		return matchComparison(selector, agentValue, exists)
+
+	default:
+		return matchValue(selector, agentValue, exists)
+	}
+}
+
+func matchComparison(selector, agentValue string, exists bool) error {
+	operator := selector[:1]
+	if strings.HasPrefix(selector[1:], "=") {
+		operator = selector[:2]
+	}
+	want, err := strconv.ParseFloat(strings.TrimSpace(selector[len(operator):]), 64)
+	if err != nil {
+		return fmt.Errorf("invalid number in selector '%s'", selector)
+	}
+
+	if !exists {
+		return fmt.Errorf("agent does not have the label, want %s", selector)
+	}
+	if agentValue == agentWildcard {
+		return nil
+	}
+	got, err := strconv.ParseFloat(agentValue, 64)
+	if err != nil {
+		return fmt.Errorf("agent has '%s', want a number %s", agentValue, selector)
+	}
+
+	var ok bool
+	switch operator {
+	case ">":
+		ok = got > want
+	case ">=":
+		ok = got >= want
+	case "<":
+		ok = got < want
+	case "<=":
+		ok = got <= want
+	}
+	if !ok {
+		return fmt.Errorf("agent has '%s', want %s", agentValue, selector)
+	}
+	return nil
+}
+
+func selectorList(selector, prefix string) []string {
+	var values []string
+	for _, value := range strings.Split(strings.TrimSuffix(strings.TrimPrefix(selector, prefix), ")"), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
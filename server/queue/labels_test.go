@@ -0,0 +1,77 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package queue
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchLabels(t *testing.T) {
+	agentLabels := map[string]string{
+		"platform": "linux/amd64",
+		"arch":     "arm64",
+		"gpu":      "true",
+		"cores":    "16",
+		"location": "*",
+	}
+
+	tests := []struct {
+		name       string
+		taskLabels map[string]string
+		err        string
+	}{
+		{name: "equal", taskLabels: map[string]string{"platform": "linux/amd64"}},
+		{name: "not equal", taskLabels: map[string]string{"platform": "linux/arm64"}, err: "label 'platform': agent has 'linux/amd64', want 'linux/arm64'"},
+		{name: "missing", taskLabels: map[string]string{"zone": "eu"}, err: "label 'zone': agent does not have the label, want 'eu'"},
+		{name: "empty value is ignored", taskLabels: map[string]string{"zone": ""}},
+		{name: "wildcard", taskLabels: map[string]string{"location": "europe"}},
+		{name: "negation", taskLabels: map[string]string{"gpu": "!false"}},
+		{name: "negation of missing label", taskLabels: map[string]string{"zone": "!eu"}},
+		{name: "negation fails", taskLabels: map[string]string{"gpu": "!true"}, err: "label 'gpu': agent has 'true', want anything else"},
+		{name: "in", taskLabels: map[string]string{"arch": "in(amd64, arm64)"}},
+		{name: "in fails", taskLabels: map[string]string{"arch": "in(amd64,riscv64)"}, err: "label 'arch': agent has 'arm64', want one of amd64, riscv64"},
+		{name: "notin", taskLabels: map[string]string{"arch": "notin(amd64,riscv64)"}},
+		{name: "notin fails", taskLabels: map[string]string{"arch": "notin(arm64)"}, err: "label 'arch': agent has 'arm64', want none of arm64"},
+		{name: "exists", taskLabels: map[string]string{"gpu": "exists"}},
+		{name: "exists fails", taskLabels: map[string]string{"zone": "exists"}, err: "label 'zone': agent does not have the label"},
+		{name: "not exists", taskLabels: map[string]string{"zone": "!exists"}},
+		{name: "greater or equal", taskLabels: map[string]string{"cores": ">=16"}},
+		{name: "greater fails", taskLabels: map[string]string{"cores": ">16"}, err: "label 'cores': agent has '16', want >16"},
+		{name: "less", taskLabels: map[string]string{"cores": "<32"}},
+		{name: "comparison with non number", taskLabels: map[string]string{"arch": ">2"}, err: "label 'arch': agent has 'arm64', want a number >2"},
+		{name: "invalid comparison", taskLabels: map[string]string{"cores": ">=many"}, err: "label 'cores': invalid number in selector '>=many'"},
+		{name: "literal negation", taskLabels: map[string]string{"gpu": "=!false"}, err: "label 'gpu': agent has 'true', want '!false'"},
+		{name: "literal exists", taskLabels: map[string]string{"zone": "=exists"}, err: "label 'zone': agent does not have the label, want 'exists'"},
+		{name: "literal comparison", taskLabels: map[string]string{"cores": "=>5"}, err: "label 'cores': agent has '16', want '>5'"},
+		{name: "literal plain value", taskLabels: map[string]string{"arch": "=arm64"}},
+		{name: "first mismatch in key order", taskLabels: map[string]string{"zone": "eu", "arch": "amd64"}, err: "label 'arch': agent has 'arm64', want 'amd64'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := MatchLabels(tt.taskLabels, agentLabels)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package queue
+
+import (
+	"container/list"
+	"time"
+
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/rs/zerolog/log"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/store"
+)
+
+// LimitFn returns the maximum number of concurrently running tasks of a repo
+// and of its org. Zero means unlimited.
+type LimitFn func(repoID, orgID int64) (repoLimit, orgLimit int)
+
+const concurrencyLimitCacheTTL = time.Minute
+
+// ConcurrencyLimitsFromStore returns a LimitFn reading the limits from the repo
+// and org settings. Limits are cached, so the store is rarely hit while the queue is locked.
+func ConcurrencyLimitsFromStore(s store.Store) LimitFn {
+	repos := ttlcache.New(ttlcache.WithDisableTouchOnHit[int64, int]())
+	orgs := ttlcache.New(ttlcache.WithDisableTouchOnHit[int64, int]())
+
+	return func(repoID, orgID int64) (int, int) {
+		repoLimit := cached(repos, repoID, func() (int, error) {
+			repo, err := s.GetRepo(repoID)
+			if err != nil {
+				return 0, err
+			}
+			return repo.MaxConcurrent, nil
+		})
+		orgLimit := cached(orgs, orgID, func() (int, error) {
+			org, err := s.OrgGet(orgID)
+			if err != nil {
+				return 0, err
+			}
+			return org.MaxConcurrent, nil
+		})
+		return repoLimit, orgLimit
+	}
+}
+
+func cached(cache *ttlcache.Cache[int64, int], id int64, get func() (int, error)) int {
+	item := cache.Get(id)
+	if item != nil && !item.IsExpired() {
+		return item.Value()
+	}
+
+	value, err := get()
+	if err != nil {
+		log.Error().Err(err).Int64("id", id).Msg("queue: could not get concurrency limit")
+	}
+	cache.Set(id, value, concurrencyLimitCacheTTL)
+	return value
+}
+
+// filterLimited holds back pending tasks of concurrency groups with a running task
+// and of repos and orgs that already run as many tasks as their concurrency limit allows.
+func (q *fifo) filterLimited() {
+	// resubmit all limited tasks to pending, running tasks may have finished
+	for e := q.waitingOnLimit.Front(); e != nil; e = e.Next() {
+		q.pending.PushBack(e.Value)
+	}
+	q.waitingOnLimit = list.New()
+
+	u := q.runningUsage()
+	var next *list.Element
+	for e := q.pending.Front(); e != nil; e = next {
+		next = e.Next()
+		task, _ := e.Value.(*model.Task)
+		if u.limited(task) {
+			log.Debug().Msgf("queue: waiting due to concurrency limit %v", task.ID)
+			q.waitingOnLimit.PushBack(task)
+			q.pending.Remove(e)
+			delete(q.pendingSince, task.ID)
+		}
+	}
+}
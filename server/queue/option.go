@@ -0,0 +1,63 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package queue
+
+import "time"
+
+// Option configures a queue option.
+type Option func(*fifo)
+
+// WithPriorityAging configures the time a pending task has to wait to gain
+// one priority level. A zero duration disables aging.
+func WithPriorityAging(aging time.Duration) Option {
+	return func(q *fifo) {
+		q.aging = aging
+	}
+}
+
+// WithFairShare enables fair-share scheduling, which balances running tasks
+// across orgs by the given weights and across the repos of an org.
+func WithFairShare(weight WeightFn) Option {
+	return func(q *fifo) {
+		q.weight = weight
+	}
+}
+
+// WithAgentResources only hands out tasks to agents with enough free resources
+// for the resources requested by the task.
+func WithAgentResources(resources ResourcesFn) Option {
+	return func(q *fifo) {
+		q.resources = resources
+	}
+}
+
+// WithConcurrencyLimits limits the number of concurrently running tasks
+// per repo and org. Tasks above the limit wait in the queue.
+func WithConcurrencyLimits(limit LimitFn) Option {
+	return func(q *fifo) {
+		q.limit = limit
+	}
+}
+
+// WithLeaser leases tasks before they are handed out, so multiple servers can
+// share their tasks.
+func WithLeaser(leaser Leaser) Option {
+	return func(q *fifo) {
+		q.leaser = leaser
+	}
+}
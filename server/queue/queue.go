@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"time"
 
 	"go.woodpecker-ci.org/woodpecker/v2/server/model"
 )
@@ -32,16 +33,28 @@ var (
 
 // InfoT provides runtime information.
 type InfoT struct {
-	Pending       []*model.Task `json:"pending"`
-	WaitingOnDeps []*model.Task `json:"waiting_on_deps"`
-	Running       []*model.Task `json:"running"`
-	Stats         struct {
-		Workers       int `json:"worker_count"`
-		Pending       int `json:"pending_count"`
-		WaitingOnDeps int `json:"waiting_on_deps_count"`
-		Running       int `json:"running_count"`
+	Pending        []*model.Task `json:"pending"`
+	WaitingOnDeps  []*model.Task `json:"waiting_on_deps"`
+	WaitingOnLimit []*model.Task `json:"waiting_on_limit"`
+	Running        []*model.Task `json:"running"`
+	Stats          struct {
+		Workers        int `json:"worker_count"`
+		Pending        int `json:"pending_count"`
+		WaitingOnDeps  int `json:"waiting_on_deps_count"`
+		WaitingOnLimit int `json:"waiting_on_limit_count"`
+		Running        int `json:"running_count"`
 	} `json:"stats"`
-	Paused bool `json:"paused"`
+	// Priorities contains the effective priority of each pending task by task id.
+	Priorities map[string]int `json:"priorities"`
+	// Shares contains the fair-share state of each org, if fair-share is enabled.
+	Shares []TenantShare `json:"shares,omitempty"`
+	// NoEligibleAgent contains why each agent can't run a pending task, for tasks no agent can run.
+	NoEligibleAgent map[string][]AgentMismatch `json:"no_eligible_agent,omitempty"`
+	// PendingSince contains since when each task without unfinished dependencies waits for an agent.
+	PendingSince map[string]time.Time `json:"pending_since"`
+	// IdleWorkers contains the number of workers waiting for a task by agent id.
+	IdleWorkers map[int64]int `json:"idle_workers"`
+	Paused      bool          `json:"paused"`
 } //	@name InfoT
 
 func (t *InfoT) String() string {
@@ -59,6 +72,10 @@ func (t *InfoT) String() string {
 		sb.WriteString("\t" + task.String())
 	}
 
+	for _, task := range t.WaitingOnLimit {
+		sb.WriteString("\t" + task.String())
+	}
+
 	return sb.String()
 }
 
@@ -0,0 +1,56 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package queue
+
+import (
+	"time"
+
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/rs/zerolog/log"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/store"
+)
+
+// ResourcesFn returns the resources an agent can allocate to tasks.
+type ResourcesFn func(agentID int64) model.Resources
+
+const agentResourcesCacheTTL = time.Minute
+
+// AgentResourcesFromStore returns a ResourcesFn reading the resources agents registered with.
+// Resources are cached, so the store is rarely hit while the queue is locked.
+func AgentResourcesFromStore(s store.Store) ResourcesFn {
+	cache := ttlcache.New(ttlcache.WithDisableTouchOnHit[int64, model.Resources]())
+
+	return func(agentID int64) model.Resources {
+		item := cache.Get(agentID)
+		if item != nil && !item.IsExpired() {
+			return item.Value()
+		}
+
+		var resources model.Resources
+		agent, err := s.AgentFind(agentID)
+		if err != nil {
+			log.Error().Err(err).Int64("agent", agentID).Msg("queue: could not get agent resources")
+		} else {
+			resources = agent.Resources
+		}
+		cache.Set(agentID, resources, agentResourcesCacheTTL)
+		return resources
+	}
+}
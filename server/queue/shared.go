@@ -0,0 +1,237 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package queue
+
+import (
+	"container/list"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/store"
+)
+
+// sharedSyncInterval is the time till a server picks up the changes other
+// servers made to the shared tasks.
+const sharedSyncInterval = time.Second
+
+// Leaser leases tasks before they are handed out to an agent, so multiple
+// servers that share their tasks never hand out a task twice.
+type Leaser interface {
+	// Lease leases the task for the agent until the given time. It returns
+	// false if the task is leased by another server or does not exist anymore.
+	Lease(task *model.Task, agentID int64, until time.Time) (bool, error)
+	// ExtendLease extends the lease of the task until the given time.
+	ExtendLease(id string, until time.Time) error
+}
+
+type storeLeaser struct {
+	store store.Store
+	owner string
+}
+
+func (l *storeLeaser) Lease(task *model.Task, agentID int64, until time.Time) (bool, error) {
+	return l.store.TaskLease(task.ID, l.owner, agentID, until)
+}
+
+func (l *storeLeaser) ExtendLease(id string, until time.Time) error {
+	return l.store.TaskExtendLease(id, until)
+}
+
+// NewShared returns a queue whose tasks are shared with other servers through
+// the TaskStore, so multiple servers can run behind a load balancer. Every
+// server schedules all tasks, but a task is leased in the store before it is
+// handed out, and tasks handed out by other servers count as running.
+func NewShared(ctx context.Context, s store.Store, opts ...Option) Queue {
+	l := &storeLeaser{store: s, owner: serverID()}
+	q, _ := New(ctx, append(opts, WithLeaser(l))...).(*fifo)
+
+	sq := &sharedQueue{
+		persistentQueue: &persistentQueue{q, s},
+		fifo:            q,
+	}
+	go sq.sync(ctx)
+	return sq
+}
+
+type sharedQueue struct {
+	*persistentQueue
+	fifo *fifo
+}
+
+// Poll retrieves and removes a task head of this queue. Unlike with the
+// persistent queue, the task stays in the store as it's leased there.
+func (q *sharedQueue) Poll(c context.Context, agentID int64, f FilterFn) (*model.Task, error) {
+	return q.fifo.Poll(c, agentID, f)
+}
+
+// Done signals the task is complete and removes it from the store.
+func (q *sharedQueue) Done(c context.Context, id string, exitStatus model.StatusValue) error {
+	if err := q.fifo.Done(c, id, exitStatus); err != nil {
+		return err
+	}
+	return q.store.TaskDelete(id)
+}
+
+func (q *sharedQueue) sync(ctx context.Context) {
+	for {
+		select {
+		case <-time.After(sharedSyncInterval):
+		case <-ctx.Done():
+			return
+		}
+
+		if err := q.syncOnce(); err != nil {
+			log.Error().Err(err).Msg("queue: cannot sync shared tasks")
+		}
+	}
+}
+
+func (q *sharedQueue) syncOnce() error {
+	// tasks are inserted to the store before they are pushed to the queue, so
+	// known tasks that are missing in the store afterwards have been finished
+	known := q.fifo.taskIDs()
+	tasks, err := q.store.TaskList()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range q.fifo.syncTasks(known, tasks, time.Now()) {
+		status, err := q.finishedStatus(id)
+		if err := q.fifo.finished([]string{id}, status, err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// finishedStatus returns the status of a task another server removed from the
+// store, based on the status of its workflow as task ids are workflow ids.
+func (q *sharedQueue) finishedStatus(id string) (model.StatusValue, error) {
+	workflowID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return model.StatusFailure, ErrCancel
+	}
+	workflow, err := q.store.WorkflowLoad(workflowID)
+	if err != nil || workflow.Running() {
+		// workflow was canceled before it finished
+		return model.StatusFailure, ErrCancel
+	}
+	return workflow.State, nil
+}
+
+// taskIDs returns the ids of all tasks in the queue.
+func (q *fifo) taskIDs() map[string]struct{} {
+	q.Lock()
+	defer q.Unlock()
+
+	ids := make(map[string]struct{}, len(q.running))
+	for id := range q.running {
+		ids[id] = struct{}{}
+	}
+	for _, l := range []*list.List{q.pending, q.waitingOnDeps, q.waitingOnLimit} {
+		for e := l.Front(); e != nil; e = e.Next() {
+			task, _ := e.Value.(*model.Task)
+			ids[task.ID] = struct{}{}
+		}
+	}
+	return ids
+}
+
+// syncTasks syncs the queue with the tasks in the store. Tasks that are unknown
+// or whose lease expired are pending, tasks that are leased are running. It
+// returns the ids of the known tasks that have been removed from the store.
+func (q *fifo) syncTasks(known map[string]struct{}, tasks []*model.Task, now time.Time) []string {
+	q.Lock()
+	defer q.Unlock()
+
+	stored := make(map[string]struct{}, len(tasks))
+	for _, task := range tasks {
+		stored[task.ID] = struct{}{}
+		leased := task.LeaseExpires > now.Unix()
+
+		if state, ok := q.running[task.ID]; ok {
+			// the lease may have been extended by another server
+			if until := time.Unix(task.LeaseExpires, 0); leased && until.After(state.deadline) {
+				state.deadline = until
+			}
+			if state.item.AgentID == 0 {
+				state.item.AgentID = task.AgentID
+			}
+			continue
+		}
+
+		l, e := q.findQueued(task.ID)
+		if !leased {
+			if e == nil {
+				q.pushPending(task)
+			}
+			continue
+		}
+
+		// another server handed out the task
+		if e != nil {
+			l.Remove(e)
+		}
+		delete(q.pendingSince, task.ID)
+		q.running[task.ID] = &entry{
+			item:     task,
+			done:     make(chan bool),
+			deadline: time.Unix(task.LeaseExpires, 0),
+		}
+	}
+
+	var removed []string
+	for id := range known {
+		if _, ok := stored[id]; ok {
+			continue
+		}
+		if _, ok := q.running[id]; ok {
+			removed = append(removed, id)
+		} else if _, e := q.findQueued(id); e != nil {
+			removed = append(removed, id)
+		}
+	}
+	return removed
+}
+
+// findQueued returns the list and element of a task that waits in the queue.
+func (q *fifo) findQueued(id string) (*list.List, *list.Element) {
+	for _, l := range []*list.List{q.pending, q.waitingOnDeps, q.waitingOnLimit} {
+		for e := l.Front(); e != nil; e = e.Next() {
+			if task, _ := e.Value.(*model.Task); task.ID == id {
+				return l, e
+			}
+		}
+	}
+	return nil, nil
+}
+
+// serverID returns an id that is unique for each server sharing the tasks.
+func serverID() string {
+	host, _ := os.Hostname()
+	b := make([]byte, 4) //nolint:mnd
+	_, _ = rand.Read(b)
+	return host + "-" + hex.EncodeToString(b)
+}
@@ -0,0 +1,224 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package queue
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/store/mocks"
+	"go.woodpecker-ci.org/woodpecker/v2/server/store/types"
+)
+
+// taskStore is an in-memory task store shared by the queues of multiple servers.
+type taskStore struct {
+	*mocks.Store
+	sync.Mutex
+	tasks map[string]model.Task
+}
+
+func newTaskStore() *taskStore {
+	return &taskStore{Store: new(mocks.Store), tasks: map[string]model.Task{}}
+}
+
+func (s *taskStore) TaskList() ([]*model.Task, error) {
+	s.Lock()
+	defer s.Unlock()
+	tasks := make([]*model.Task, 0, len(s.tasks))
+	for _, task := range s.tasks {
+		tasks = append(tasks, &task)
+	}
+	return tasks, nil
+}
+
+func (s *taskStore) TaskInsert(task *model.Task) error {
+	s.Lock()
+	defer s.Unlock()
+	s.tasks[task.ID] = *task
+	return nil
+}
+
+func (s *taskStore) TaskDelete(id string) error {
+	s.Lock()
+	defer s.Unlock()
+	if _, ok := s.tasks[id]; !ok {
+		return types.RecordNotExist
+	}
+	delete(s.tasks, id)
+	return nil
+}
+
+func (s *taskStore) TaskLease(id, owner string, agentID int64, until time.Time) (bool, error) {
+	s.Lock()
+	defer s.Unlock()
+	task, ok := s.tasks[id]
+	if !ok || task.LeaseExpires >= time.Now().Unix() {
+		return false, nil
+	}
+	task.LeaseOwner, task.LeaseExpires, task.AgentID = owner, until.Unix(), agentID
+	s.tasks[id] = task
+	return true, nil
+}
+
+func (s *taskStore) TaskExtendLease(id string, until time.Time) error {
+	s.Lock()
+	defer s.Unlock()
+	if task, ok := s.tasks[id]; ok && task.LeaseExpires > 0 {
+		task.LeaseExpires = until.Unix()
+		s.tasks[id] = task
+	}
+	return nil
+}
+
+func (s *taskStore) WorkflowLoad(id int64) (*model.Workflow, error) {
+	return &model.Workflow{ID: id, State: model.StatusSuccess}, nil
+}
+
+func TestSharedQueue(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := newTaskStore()
+	first := NewShared(ctx, s)
+	second := NewShared(ctx, s)
+
+	build := &model.Task{ID: "1", DepStatus: map[string]model.StatusValue{}}
+	deploy := &model.Task{ID: "2", Dependencies: []string{"1"}, DepStatus: map[string]model.StatusValue{}}
+	assert.NoError(t, first.PushAtOnce(ctx, []*model.Task{build, deploy}))
+
+	// wait till the second server knows the tasks
+	time.Sleep(2 * sharedSyncInterval)
+	assert.Len(t, second.Info(ctx).Pending, 1)
+	assert.Len(t, second.Info(ctx).WaitingOnDeps, 1)
+
+	polled := make(chan *model.Task, 2)
+	poll := func(q Queue, agentID int64) {
+		pollCtx, cancel := context.WithTimeout(ctx, 2*sharedSyncInterval)
+		defer cancel()
+		if task, err := q.Poll(pollCtx, agentID, func(*model.Task) bool { return true }); err == nil {
+			polled <- task
+		}
+	}
+
+	var wg sync.WaitGroup
+	for agentID, q := range []Queue{first, second, first, second} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			poll(q, int64(agentID))
+		}()
+	}
+	wg.Wait()
+	close(polled)
+
+	var got []string
+	for task := range polled {
+		got = append(got, task.ID)
+	}
+	assert.Equal(t, []string{"1"}, got, "expect task to be handed out once and its dependent to wait")
+	assert.Len(t, first.Info(ctx).Running, 1, "expect task of other server to count as running")
+	assert.Len(t, second.Info(ctx).Running, 1, "expect task of other server to count as running")
+
+	// the agent reports to any of the servers
+	assert.NoError(t, second.Done(ctx, "1", model.StatusSuccess))
+
+	pollCtx, pollCancel := context.WithTimeout(ctx, 3*sharedSyncInterval)
+	defer pollCancel()
+	task, err := first.Poll(pollCtx, 1, func(*model.Task) bool { return true })
+	if assert.NoError(t, err) {
+		assert.Equal(t, "2", task.ID, "expect dependent task to run once the task is done")
+	}
+	assert.NoError(t, first.Extend(ctx, "2"))
+
+	time.Sleep(2 * sharedSyncInterval)
+	info := second.Info(ctx)
+	assert.Len(t, info.Pending, 0)
+	if assert.Len(t, info.Running, 1) {
+		assert.Equal(t, "2", info.Running[0].ID)
+		assert.EqualValues(t, 1, info.Running[0].AgentID)
+	}
+}
+
+// blockingLeaser leases every task, but only once it is unblocked.
+type blockingLeaser struct {
+	leasing chan string
+	unblock chan bool
+}
+
+func (l *blockingLeaser) Lease(task *model.Task, _ int64, _ time.Time) (bool, error) {
+	l.leasing <- task.ID
+	return <-l.unblock, nil
+}
+
+func (l *blockingLeaser) ExtendLease(string, time.Time) error {
+	return nil
+}
+
+func TestFifoLeaseWithoutLock(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	leaser := &blockingLeaser{leasing: make(chan string), unblock: make(chan bool)}
+	q := New(ctx, WithLeaser(leaser))
+
+	assert.NoError(t, q.Push(ctx, &model.Task{ID: "1"}))
+	polled := make(chan *model.Task)
+	go func() {
+		task, _ := q.Poll(ctx, 1, func(*model.Task) bool { return true })
+		polled <- task
+	}()
+	assert.Equal(t, "1", <-leaser.leasing)
+
+	// the queue is usable while the lease is taken
+	assert.NoError(t, q.Push(ctx, &model.Task{ID: "2"}))
+	info := q.Info(ctx)
+	assert.Len(t, info.Pending, 1)
+	assert.Len(t, info.Running, 1)
+
+	leaser.unblock <- true
+	task := <-polled
+	assert.Equal(t, "1", task.ID)
+	assert.EqualValues(t, 1, task.AgentID)
+}
+
+func TestFifoLeaseTakenReturnsWorker(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	leaser := &blockingLeaser{leasing: make(chan string), unblock: make(chan bool)}
+	q := New(ctx, WithLeaser(leaser))
+
+	assert.NoError(t, q.Push(ctx, &model.Task{ID: "1"}))
+	polled := make(chan *model.Task)
+	go func() {
+		task, _ := q.Poll(ctx, 1, func(*model.Task) bool { return true })
+		polled <- task
+	}()
+	assert.Equal(t, "1", <-leaser.leasing)
+	leaser.unblock <- false
+
+	// the task runs on another server, the worker gets the next task
+	assert.NoError(t, q.Push(ctx, &model.Task{ID: "2"}))
+	assert.Equal(t, "2", <-leaser.leasing)
+	leaser.unblock <- true
+	assert.Equal(t, "2", (<-polled).ID)
+	assert.Len(t, q.Info(ctx).Running, 2)
+}
@@ -0,0 +1,113 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package queue
+
+import (
+	"fmt"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+)
+
+// usage counts the running tasks per org, repo and concurrency group and the
+// resources used per agent, to balance new work across them and to enforce
+// their concurrency limits.
+type usage struct {
+	weight    WeightFn
+	limit     LimitFn
+	resources ResourcesFn
+	weights   map[int64]int
+	limits    map[int64]limits
+	orgs      map[int64]int
+	repos     map[int64]int
+	groups    map[string]int
+	agents    map[int64]model.Resources
+}
+
+type limits struct {
+	repo int
+	org  int
+}
+
+func (q *fifo) runningUsage() *usage {
+	u := &usage{
+		weight:    q.weight,
+		limit:     q.limit,
+		resources: q.resources,
+		weights:   map[int64]int{},
+		limits:    map[int64]limits{},
+		orgs:      map[int64]int{},
+		repos:     map[int64]int{},
+		groups:    map[string]int{},
+		agents:    map[int64]model.Resources{},
+	}
+	for _, running := range q.running {
+		u.orgs[running.item.OrgID]++
+		u.repos[running.item.RepoID]++
+		if running.item.ConcurrencyGroup != "" {
+			u.groups[groupKey(running.item)]++
+		}
+		u.agents[running.item.AgentID] = u.agents[running.item.AgentID].Add(running.item.Resources)
+	}
+	return u
+}
+
+func (u *usage) weightOf(orgID int64) int {
+	weight, ok := u.weights[orgID]
+	if !ok {
+		weight = max(u.weight(orgID), 1)
+		u.weights[orgID] = weight
+	}
+	return weight
+}
+
+// orgShare returns the running tasks of an org normalized by its weight.
+func (u *usage) orgShare(orgID int64) float64 {
+	return float64(u.orgs[orgID]) / float64(u.weightOf(orgID))
+}
+
+// limited reports whether a task of the same concurrency group is running, or
+// the repo or org of the task already runs as many tasks as its concurrency limit allows.
+func (u *usage) limited(task *model.Task) bool {
+	if task.ConcurrencyGroup != "" && u.groups[groupKey(task)] > 0 {
+		return true
+	}
+	if u.limit == nil {
+		return false
+	}
+
+	repoID, orgID := task.RepoID, task.OrgID
+	l, ok := u.limits[repoID]
+	if !ok {
+		l.repo, l.org = u.limit(repoID, orgID)
+		u.limits[repoID] = l
+	}
+	return (l.repo > 0 && u.repos[repoID] >= l.repo) || (l.org > 0 && u.orgs[orgID] >= l.org)
+}
+
+// fits reports whether the agent has enough free resources for the task.
+func (u *usage) fits(task *model.Task, agentID int64) bool {
+	if u.resources == nil || task.Resources.IsZero() {
+		return true
+	}
+	return u.resources(agentID).Fits(u.agents[agentID], task.Resources)
+}
+
+// groupKey scopes the concurrency group of a task to its repo.
+func groupKey(task *model.Task) string {
+	return fmt.Sprintf("%d/%s", task.RepoID, task.ConcurrencyGroup)
+}
@@ -60,7 +60,9 @@ func apiRoutes(e *gin.RouterGroup) {
 				{
 					org.Use(session.MustOrgMember(true))
 					org.DELETE("", session.MustAdmin(), api.DeleteOrg)
+					org.PATCH("", session.MustAdmin(), api.PatchOrg)
 					org.GET("", api.GetOrg)
+					org.GET("/usage", api.GetOrgStorageUsage)
 
 					org.GET("/secrets", api.GetOrgSecretList)
 					org.POST("/secrets", api.PostOrgSecret)
@@ -95,6 +97,7 @@ func apiRoutes(e *gin.RouterGroup) {
 					repo.Use(session.MustPull)
 
 					repo.GET("", api.GetRepo)
+					repo.GET("/usage", api.GetRepoStorageUsage)
 
 					repo.GET("/branches", api.GetRepoBranches)
 					repo.GET("/pull_requests", api.GetRepoPullRequests)
@@ -104,6 +107,7 @@ func apiRoutes(e *gin.RouterGroup) {
 					repo.DELETE("/pipelines/:number", session.MustRepoAdmin(), api.DeletePipeline)
 					repo.GET("/pipelines/:number", api.GetPipeline)
 					repo.GET("/pipelines/:number/config", api.GetPipelineConfig)
+					repo.GET("/pipelines/:number/explain", session.MustRepoAdmin(), api.ExplainPipeline)
 
 					// requires push permissions
 					repo.POST("/pipelines/:number", session.MustPush, api.PostPipeline)
@@ -111,6 +115,8 @@ func apiRoutes(e *gin.RouterGroup) {
 					repo.POST("/pipelines/:number/approve", session.MustPush, api.PostApproval)
 					repo.POST("/pipelines/:number/decline", session.MustPush, api.PostDecline)
 
+					repo.GET("/logs/search", session.MustPush, api.SearchLogs)
+					repo.GET("/logs/:number/archive", api.GetPipelineLogArchive)
 					repo.GET("/logs/:number/:stepId", api.GetStepLogs)
 					repo.DELETE("/logs/:number/:stepId", session.MustPush, api.DeleteStepLogs)
 
@@ -18,11 +18,13 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/google/tink/go/subtle/random"
 	"github.com/rs/zerolog/log"
-	"golang.org/x/crypto/bcrypt"
 	"golang.org/x/crypto/sha3"
 )
 
@@ -30,22 +32,58 @@ const (
 	AesAlgo            = "aes"
 	Sha256Size         = 32
 	AESGCMSIVNonceSize = 12
+
+	// aesKeyIDSize is the number of bytes of the key hash used as key id
+	aesKeyIDSize = 4
+	// aesTagSize is the size of the authentication tag of AES-GCM
+	aesTagSize = 16
+	// aesKeyIDSeparator separates the key id from the ciphertext, it is not part
+	// of the base64 alphabet so that ciphertexts without key id can be told apart
+	aesKeyIDSeparator = ":"
 )
 
-type aesEncryptionService struct {
-	keyId  string
+type aesKey struct {
+	id     string
 	cipher cipher.AEAD
 }
 
-func NewAes(password string) (Service, error) {
+type aesEncryptionService struct {
+	// primary encrypts and decrypts, all keys decrypt
+	primary *aesKey
+	keys    []*aesKey
+}
+
+// NewAes returns an AES encryption service encrypting with the key derived from
+// the given password. Keys derived from the old passwords are only used to
+// decrypt ciphertexts encrypted before a key rotation.
+func NewAes(password string, oldPasswords ...string) (Service, error) {
 	log.Info().Msg("initializing AES encryption service")
 
+	service := new(aesEncryptionService)
+	for _, password := range append([]string{password}, oldPasswords...) {
+		key, err := newAesKey(password)
+		if err != nil {
+			return nil, err
+		}
+		if service.key(key.id) != nil {
+			return nil, fmt.Errorf("duplicate encryption key with id %s", key.id)
+		}
+		service.keys = append(service.keys, key)
+	}
+	service.primary = service.keys[0]
+
+	log.Info().Str("key-id", service.primary.id).Int("old-keys", len(oldPasswords)).
+		Msg("AES encryption service has been initialized")
+	return service, nil
+}
+
+func newAesKey(password string) (*aesKey, error) {
 	key, err := hash([]byte(password))
 	if err != nil {
 		return nil, newKeyGenerationError(err)
 	}
 
-	keyHash, err := bcrypt.GenerateFromPassword(key, bcrypt.DefaultCost)
+	keyHash, err := hash(key)
 	if err != nil {
 		return nil, newKeyGenerationIdError(err)
 	}
@@ -60,13 +98,19 @@ func NewAes(password string) (Service, error) {
 		return nil, newCipherLoadingError(err)
 	}
 
-	service := aesEncryptionService{
-		keyId:  string(keyHash),
+	return &aesKey{
+		id:     hex.EncodeToString(keyHash[:aesKeyIDSize]),
 		cipher: aead,
-	}
+	}, nil
+}
 
-	log.Info().Msg("AES encryption service has been initialized")
-	return &service, nil
+func (svc *aesEncryptionService) key(id string) *aesKey {
+	for _, key := range svc.keys {
+		if key.id == id {
+			return key
+		}
+	}
+	return nil
 }
 
 func hash(data []byte) ([]byte, error) {
@@ -93,29 +137,57 @@ func (svc *aesEncryptionService) Encrypt(plaintext, associatedData string) (stri
 	aad := []byte(associatedData)
 
 	nonce := random.GetRandomBytes(uint32(AESGCMSIVNonceSize))
-	ciphertext := svc.cipher.Seal(nil, nonce, msg, aad)
+	ciphertext := svc.primary.cipher.Seal(nil, nonce, msg, aad)
 
 	result := make([]byte, 0, AESGCMSIVNonceSize+len(ciphertext))
 	result = append(result, nonce...)
 	result = append(result, ciphertext...)
 
-	return base64.RawStdEncoding.EncodeToString(result), nil
+	return svc.primary.id + aesKeyIDSeparator + base64.RawStdEncoding.EncodeToString(result), nil
+}
+
+func isAesCiphertext(ciphertext string) bool {
+	// ciphertexts encrypted before key ids were introduced have none
+	if id, data, ok := strings.Cut(ciphertext, aesKeyIDSeparator); ok {
+		if _, err := hex.DecodeString(id); err != nil || len(id) != 2*aesKeyIDSize {
+			return false
+		}
+		ciphertext = data
+	}
+	return decodesTo(ciphertext, AESGCMSIVNonceSize+aesTagSize)
 }
 
 func (svc *aesEncryptionService) Decrypt(ciphertext, associatedData string) (string, error) {
+	keys := svc.keys
+	// ciphertexts encrypted before key ids were introduced have to be tried with every key
+	if id, data, ok := strings.Cut(ciphertext, aesKeyIDSeparator); ok {
+		key := svc.key(id)
+		if key == nil {
+			return "", newUnknownKeyError(id)
+		}
+		keys = []*aesKey{key}
+		ciphertext = data
+	}
+
 	bytes, err := base64.RawStdEncoding.DecodeString(ciphertext)
 	if err != nil {
 		return "", newBase64DecryptionError(err)
 	}
+	if len(bytes) < AESGCMSIVNonceSize {
+		return "", newDecryptionError(errors.New("ciphertext too short"))
+	}
 
 	nonce := bytes[:AESGCMSIVNonceSize]
 	message := bytes[AESGCMSIVNonceSize:]
 
-	plaintext, err := svc.cipher.Open(nil, nonce, message, []byte(associatedData))
-	if err != nil {
-		return "", newDecryptionError(err)
+	for _, key := range keys {
+		var plaintext []byte
+		plaintext, err = key.cipher.Open(nil, nonce, message, []byte(associatedData))
+		if err == nil {
+			return string(plaintext), nil
+		}
 	}
-	return string(plaintext), nil
+	return "", newDecryptionError(err)
 }
 
 func newHashCalculationError(e error) error {
@@ -134,6 +206,10 @@ func newCipherLoadingError(e error) error {
 	return fmt.Errorf("failed loading encryption cipher: %w", e)
 }
 
+func newUnknownKeyError(id string) error {
+	return fmt.Errorf("decryption error: unknown key id %s", id)
+}
+
 func newBase64DecryptionError(e error) error {
 	return fmt.Errorf("Base64 decryption failed: %w", e)
 }
@@ -15,6 +15,8 @@
 package encryption
 
 import (
+	"encoding/base64"
+	"strings"
 	"testing"
 
 	"github.com/google/tink/go/subtle/random"
@@ -57,3 +59,63 @@ func TestAdditionalInfoMismatch(t *testing.T) {
 	_, err = aes.Decrypt(cipher, "id2")
 	assert.ErrorContains(t, err, "cipher: message authentication failed")
 }
+
+func TestKeyRotation(t *testing.T) {
+	oldAes, err := NewAes("old key")
+	assert.NoError(t, err)
+	oldCipher, err := oldAes.Encrypt("secret value", "id")
+	assert.NoError(t, err)
+
+	aes, err := NewAes("new key", "older key", "old key")
+	assert.NoError(t, err)
+
+	output, err := aes.Decrypt(oldCipher, "id")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret value", output)
+
+	// ciphertexts without key id are tried with all keys
+	_, legacyCipher, _ := strings.Cut(oldCipher, aesKeyIDSeparator)
+	output, err = aes.Decrypt(legacyCipher, "id")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret value", output)
+
+	newCipher, err := aes.Encrypt("secret value", "id")
+	assert.NoError(t, err)
+	assert.NotEqual(t, strings.Split(oldCipher, aesKeyIDSeparator)[0], strings.Split(newCipher, aesKeyIDSeparator)[0])
+
+	_, err = oldAes.Decrypt(newCipher, "id")
+	assert.ErrorContains(t, err, "unknown key id")
+
+	_, err = NewAes("key", "key")
+	assert.ErrorContains(t, err, "duplicate encryption key")
+}
+
+func TestCheckAlgo(t *testing.T) {
+	aes, err := NewAes("key")
+	assert.NoError(t, err)
+	tinkCiphertext := base64.RawStdEncoding.EncodeToString(make([]byte, tinkMinCiphertextSize))
+
+	assert.NoError(t, CheckAlgo(aes, "plain text"))
+	assert.NoError(t, CheckAlgo(aes, "aes_abcd:ciphertext"))
+	assert.ErrorContains(t, CheckAlgo(aes, Header(TinkAlgo)+tinkCiphertext), "value is encrypted with tink, but aes is configured")
+
+	// plain text values which only start with a header are no ciphertexts
+	for _, value := range []string{"tink_", "tink_password", "age_of_empires", "kms_token.value"} {
+		assert.NoError(t, CheckAlgo(aes, value), value)
+	}
+}
+
+func TestIsEncrypted(t *testing.T) {
+	aes, err := NewAes("key")
+	assert.NoError(t, err)
+	ciphertext, err := aes.Encrypt("secret value", "id")
+	assert.NoError(t, err)
+	_, legacyCiphertext, _ := strings.Cut(ciphertext, aesKeyIDSeparator)
+
+	assert.True(t, IsEncrypted(AesAlgo, Header(AesAlgo)+ciphertext))
+	assert.True(t, IsEncrypted(AesAlgo, Header(AesAlgo)+legacyCiphertext))
+	assert.False(t, IsEncrypted(AesAlgo, ciphertext))
+	assert.False(t, IsEncrypted(AesAlgo, "aes_key"))
+	assert.False(t, IsEncrypted(AesAlgo, "aes_zzzzzzzz:"+legacyCiphertext))
+	assert.False(t, IsEncrypted(TinkAlgo, Header(AesAlgo)+ciphertext))
+}
@@ -0,0 +1,124 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package encryption
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"filippo.io/age"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	AgeAlgo = "age"
+
+	// ageVersionLine starts the header of every age file
+	ageVersionLine = "age-encryption.org/v1\n"
+)
+
+type ageEncryptionService struct {
+	recipient  age.Recipient
+	identities []age.Identity
+}
+
+// NewAge returns an encryption service using the X25519 identities of the
+// given age identity file. Values are encrypted to the recipient of the first
+// identity and decrypted with any of them, so keys are rotated by adding a new
+// identity at the top of the file.
+func NewAge(identityFile string) (Service, error) {
+	log.Info().Msg("initializing age encryption service")
+
+	file, err := os.Open(identityFile)
+	if err != nil {
+		return nil, newKeyLoadingError(err)
+	}
+	defer file.Close()
+
+	identities, err := age.ParseIdentities(file)
+	if err != nil {
+		return nil, newKeyLoadingError(err)
+	}
+	primary, ok := identities[0].(*age.X25519Identity)
+	if !ok {
+		return nil, newKeyLoadingError(errors.New("first identity is not a X25519 identity"))
+	}
+
+	log.Info().Str("recipient", primary.Recipient().String()).Int("identities", len(identities)).
+		Msg("age encryption service has been initialized")
+	return &ageEncryptionService{
+		recipient:  primary.Recipient(),
+		identities: identities,
+	}, nil
+}
+
+func (svc *ageEncryptionService) Algo() string {
+	return AgeAlgo
+}
+
+// Encrypt encrypts the plaintext prefixed with the associated data, as age
+// has no notion of associated data. The prefix is verified on decryption.
+func (svc *ageEncryptionService) Encrypt(plaintext, associatedData string) (string, error) {
+	var buf bytes.Buffer
+	w, err := age.Encrypt(&buf, svc.recipient)
+	if err != nil {
+		return "", fmt.Errorf("encryption error: %w", err)
+	}
+	if _, err := w.Write(binary.AppendUvarint(nil, uint64(len(associatedData)))); err != nil {
+		return "", fmt.Errorf("encryption error: %w", err)
+	}
+	if _, err := io.WriteString(w, associatedData+plaintext); err != nil {
+		return "", fmt.Errorf("encryption error: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("encryption error: %w", err)
+	}
+	return base64.RawStdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+func isAgeCiphertext(ciphertext string) bool {
+	data, err := base64.RawStdEncoding.DecodeString(ciphertext)
+	return err == nil && bytes.HasPrefix(data, []byte(ageVersionLine))
+}
+
+func (svc *ageEncryptionService) Decrypt(ciphertext, associatedData string) (string, error) {
+	data, err := base64.RawStdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", newBase64DecryptionError(err)
+	}
+
+	r, err := age.Decrypt(bytes.NewReader(data), svc.identities...)
+	if err != nil {
+		return "", newDecryptionError(err)
+	}
+	message, err := io.ReadAll(r)
+	if err != nil {
+		return "", newDecryptionError(err)
+	}
+
+	size, n := binary.Uvarint(message)
+	if n <= 0 || uint64(len(message)-n) < size || string(message[n:n+int(size)]) != associatedData {
+		return "", newDecryptionError(errors.New("associated data mismatch"))
+	}
+	return string(message[n+int(size):]), nil
+}
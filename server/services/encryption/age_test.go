@@ -0,0 +1,77 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package encryption
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAge(t *testing.T) {
+	oldIdentity, err := age.GenerateX25519Identity()
+	assert.NoError(t, err)
+
+	svc, err := NewAge(writeIdentities(t, oldIdentity))
+	assert.NoError(t, err)
+	assert.Equal(t, AgeAlgo, svc.Algo())
+
+	ciphertext, err := svc.Encrypt("secret value", "id1")
+	assert.NoError(t, err)
+	assert.NotContains(t, ciphertext, "secret value")
+	assert.True(t, isAgeCiphertext(ciphertext))
+
+	plaintext, err := svc.Decrypt(ciphertext, "id1")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret value", plaintext)
+
+	_, err = svc.Decrypt(ciphertext, "id2")
+	assert.ErrorContains(t, err, "associated data mismatch")
+	_, err = svc.Decrypt(ciphertext, "id")
+	assert.ErrorContains(t, err, "associated data mismatch")
+
+	// a new identity on top encrypts, the old one still decrypts
+	newIdentity, err := age.GenerateX25519Identity()
+	assert.NoError(t, err)
+	rotated, err := NewAge(writeIdentities(t, newIdentity, oldIdentity))
+	assert.NoError(t, err)
+
+	plaintext, err = rotated.Decrypt(ciphertext, "id1")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret value", plaintext)
+
+	ciphertext, err = rotated.Encrypt("new value", "id1")
+	assert.NoError(t, err)
+	_, err = svc.Decrypt(ciphertext, "id1")
+	assert.Error(t, err)
+}
+
+func writeIdentities(t *testing.T, identities ...*age.X25519Identity) string {
+	lines := make([]string, 0, len(identities))
+	for _, identity := range identities {
+		lines = append(lines, identity.String())
+	}
+
+	path := filepath.Join(t.TempDir(), "identities.txt")
+	assert.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600))
+	return path
+}
@@ -0,0 +1,248 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package encryption
+
+import (
+	"context"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/tink/go/subtle/random"
+	"github.com/rs/zerolog/log"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/services/utils"
+)
+
+const (
+	KmsAlgo = "kms"
+
+	kmsTimeout = 10 * time.Second
+
+	// kmsDataKeySize is the size of the AES-256 data keys
+	kmsDataKeySize = 32
+	// kmsDataKeyTTL is how long a data key encrypts new values
+	kmsDataKeyTTL = 24 * time.Hour
+	// kmsDataKeyCacheSize is the number of decrypted data keys kept in memory
+	kmsDataKeyCacheSize = 256
+	// kmsDataKeyAssociatedData is sent as associated data with the data keys
+	kmsDataKeyAssociatedData = "woodpecker-data-key"
+	// kmsSeparator separates the encrypted data key from the ciphertext, it is
+	// not part of the base64 alphabet
+	kmsSeparator = "."
+)
+
+type kmsDataKey struct {
+	// encrypted is the data key encrypted by the extension, base64 encoded
+	encrypted string
+	cipher    cipher.AEAD
+	created   time.Time
+}
+
+type kmsEncryptionService struct {
+	endpoint   string
+	privateKey crypto.PrivateKey
+
+	mu      sync.Mutex
+	current *kmsDataKey
+	keys    map[string]cipher.AEAD
+}
+
+type kmsRequest struct {
+	Plaintext      string `json:"plaintext,omitempty"`
+	Ciphertext     string `json:"ciphertext,omitempty"`
+	AssociatedData string `json:"associated_data"`
+}
+
+type kmsResponse struct {
+	Plaintext  string `json:"plaintext"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// NewKMS returns an encryption service which encrypts values with data keys
+// that are themselves encrypted by an HTTP extension (envelope encryption), so
+// that the master key never leaves the key management service. A data key is
+// used for a day and decrypted data keys are cached, so the extension is only
+// called once per data key. Requests are signed with the given private key.
+func NewKMS(endpoint string, privateKey crypto.PrivateKey) Service {
+	log.Info().Str("endpoint", endpoint).Msg("initializing KMS encryption service")
+
+	return &kmsEncryptionService{
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		privateKey: privateKey,
+		keys:       make(map[string]cipher.AEAD),
+	}
+}
+
+func (svc *kmsEncryptionService) Algo() string {
+	return KmsAlgo
+}
+
+func (svc *kmsEncryptionService) Encrypt(plaintext, associatedData string) (string, error) {
+	key, err := svc.dataKey()
+	if err != nil {
+		return "", fmt.Errorf("encryption error: %w", err)
+	}
+
+	nonce := random.GetRandomBytes(uint32(AESGCMSIVNonceSize))
+	ciphertext := key.cipher.Seal(nonce, nonce, []byte(plaintext), []byte(associatedData))
+	return key.encrypted + kmsSeparator + base64.RawStdEncoding.EncodeToString(ciphertext), nil
+}
+
+func (svc *kmsEncryptionService) Decrypt(ciphertext, associatedData string) (string, error) {
+	encryptedKey, data, ok := strings.Cut(ciphertext, kmsSeparator)
+	if !ok {
+		return "", newDecryptionError(errors.New("missing data key"))
+	}
+	bytes, err := base64.RawStdEncoding.DecodeString(data)
+	if err != nil {
+		return "", newBase64DecryptionError(err)
+	}
+	if len(bytes) < AESGCMSIVNonceSize {
+		return "", newDecryptionError(errors.New("ciphertext too short"))
+	}
+
+	aead, err := svc.decryptDataKey(encryptedKey)
+	if err != nil {
+		return "", newDecryptionError(err)
+	}
+	plaintext, err := aead.Open(nil, bytes[:AESGCMSIVNonceSize], bytes[AESGCMSIVNonceSize:], []byte(associatedData))
+	if err != nil {
+		return "", newDecryptionError(err)
+	}
+	return string(plaintext), nil
+}
+
+func isKmsCiphertext(ciphertext string) bool {
+	encryptedKey, data, ok := strings.Cut(ciphertext, kmsSeparator)
+	return ok && decodesTo(encryptedKey, 1) && decodesTo(data, AESGCMSIVNonceSize+aesTagSize)
+}
+
+// dataKey returns the data key for new values, a new one is generated and
+// encrypted by the extension once the current one expired.
+func (svc *kmsEncryptionService) dataKey() (*kmsDataKey, error) {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+
+	if svc.current != nil && time.Since(svc.current.created) < kmsDataKeyTTL {
+		return svc.current, nil
+	}
+
+	plainKey := random.GetRandomBytes(kmsDataKeySize)
+	response, err := svc.send("encrypt", &kmsRequest{
+		Plaintext:      base64.StdEncoding.EncodeToString(plainKey),
+		AssociatedData: kmsDataKeyAssociatedData,
+	})
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newKmsCipher(plainKey)
+	if err != nil {
+		return nil, err
+	}
+
+	svc.current = &kmsDataKey{
+		encrypted: base64.RawStdEncoding.EncodeToString([]byte(response.Ciphertext)),
+		cipher:    aead,
+		created:   time.Now(),
+	}
+	svc.cache(svc.current.encrypted, aead)
+	return svc.current, nil
+}
+
+// decryptDataKey returns the cipher of an encrypted data key, only keys which
+// are not cached yet are decrypted by the extension.
+func (svc *kmsEncryptionService) decryptDataKey(encryptedKey string) (cipher.AEAD, error) {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+
+	if aead, ok := svc.keys[encryptedKey]; ok {
+		return aead, nil
+	}
+
+	ciphertext, err := base64.RawStdEncoding.DecodeString(encryptedKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data key: %w", err)
+	}
+	response, err := svc.send("decrypt", &kmsRequest{
+		Ciphertext:     string(ciphertext),
+		AssociatedData: kmsDataKeyAssociatedData,
+	})
+	if err != nil {
+		return nil, err
+	}
+	plainKey, err := base64.StdEncoding.DecodeString(response.Plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data key: %w", err)
+	}
+	aead, err := newKmsCipher(plainKey)
+	if err != nil {
+		return nil, err
+	}
+
+	svc.cache(encryptedKey, aead)
+	return aead, nil
+}
+
+// cache stores a decrypted data key, evicting another one if the cache is full.
+// The caller must hold svc.mu.
+func (svc *kmsEncryptionService) cache(encryptedKey string, aead cipher.AEAD) {
+	if len(svc.keys) >= kmsDataKeyCacheSize {
+		for key := range svc.keys {
+			if svc.current == nil || key != svc.current.encrypted {
+				delete(svc.keys, key)
+				break
+			}
+		}
+	}
+	svc.keys[encryptedKey] = aead
+}
+
+func newKmsCipher(key []byte) (cipher.AEAD, error) {
+	if len(key) != kmsDataKeySize {
+		return nil, fmt.Errorf("invalid data key size %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, newCipherLoadingError(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, newCipherLoadingError(err)
+	}
+	return aead, nil
+}
+
+func (svc *kmsEncryptionService) send(operation string, request *kmsRequest) (*kmsResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), kmsTimeout)
+	defer cancel()
+
+	response := new(kmsResponse)
+	status, err := utils.Send(ctx, http.MethodPost, svc.endpoint+"/"+operation, svc.privateKey, request, response)
+	if err != nil {
+		return nil, fmt.Errorf("kms request failed (%d): %w", status, err)
+	}
+	return response, nil
+}
@@ -0,0 +1,123 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package encryption
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-ap/httpsig"
+	"github.com/stretchr/testify/assert"
+)
+
+// newLocalKMS starts a stand-in for a key management service extension which
+// keeps its key in memory and counts the requests it received.
+func newLocalKMS(t *testing.T, pubKey ed25519.PublicKey, requests *atomic.Int32) *httptest.Server {
+	local, err := NewAes("kms master key")
+	assert.NoError(t, err)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		keystore := httpsig.NewMemoryKeyStore()
+		keystore.SetKey("woodpecker-ci-plugins", pubKey)
+		verifier := httpsig.NewVerifier(keystore)
+		verifier.SetRequiredHeaders([]string{"(request-target)", "date"})
+		if _, err := verifier.Verify(r); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var request kmsRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var response kmsResponse
+		switch r.URL.Path {
+		case "/encrypt":
+			response.Ciphertext, err = local.Encrypt(request.Plaintext, request.AssociatedData)
+		case "/decrypt":
+			response.Plaintext, err = local.Decrypt(request.Ciphertext, request.AssociatedData)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(err.Error()))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+}
+
+func TestKMS(t *testing.T) {
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	var requests atomic.Int32
+	server := newLocalKMS(t, pubKey, &requests)
+	defer server.Close()
+
+	svc := NewKMS(server.URL+"/", privKey)
+	assert.Equal(t, KmsAlgo, svc.Algo())
+
+	ciphertext, err := svc.Encrypt("secret value", "id1")
+	assert.NoError(t, err)
+	assert.NotContains(t, ciphertext, "secret value")
+	assert.True(t, isKmsCiphertext(ciphertext))
+
+	plaintext, err := svc.Decrypt(ciphertext, "id1")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret value", plaintext)
+
+	_, err = svc.Decrypt(ciphertext, "id2")
+	assert.ErrorContains(t, err, "decryption error")
+
+	// the data key is reused and cached, only its encryption called the extension
+	other, err := svc.Encrypt("other value", "id2")
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Split(ciphertext, kmsSeparator)[0], strings.Split(other, kmsSeparator)[0])
+	assert.EqualValues(t, 1, requests.Load())
+
+	// another instance decrypts the data key once
+	restarted := NewKMS(server.URL, privKey)
+	for _, value := range []struct{ ciphertext, associatedData, plaintext string }{
+		{ciphertext, "id1", "secret value"},
+		{other, "id2", "other value"},
+	} {
+		plaintext, err = restarted.Decrypt(value.ciphertext, value.associatedData)
+		assert.NoError(t, err)
+		assert.Equal(t, value.plaintext, plaintext)
+	}
+	assert.EqualValues(t, 2, requests.Load())
+
+	// requests signed with another key are rejected
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	_, err = NewKMS(server.URL, otherKey).Encrypt("secret value", "id1")
+	assert.ErrorContains(t, err, "(401)")
+	_, err = NewKMS(server.URL, otherKey).Decrypt(ciphertext, "id1")
+	assert.ErrorContains(t, err, "(401)")
+}
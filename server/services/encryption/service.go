@@ -14,8 +14,60 @@
 
 package encryption
 
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// formats recognize the ciphertexts of the algorithms of all encryption
+// providers following their header, see Header.
+var formats = map[string]func(ciphertext string) bool{
+	AesAlgo:  isAesCiphertext,
+	TinkAlgo: isTinkCiphertext,
+	AgeAlgo:  isAgeCiphertext,
+	KmsAlgo:  isKmsCiphertext,
+}
+
 type Service interface {
 	Algo() string
 	Encrypt(plaintext, associatedData string) (string, error)
 	Decrypt(ciphertext, associatedData string) (string, error)
 }
+
+// Header returns the prefix which records the algorithm of a stored ciphertext.
+func Header(algo string) string {
+	return algo + "_"
+}
+
+// IsEncrypted returns whether the value is a ciphertext of the algorithm,
+// i.e. it starts with the header of the algorithm followed by a ciphertext in
+// the format of the algorithm. Plain text values which merely start with the
+// header are not. The format of unknown algorithms is not checked.
+func IsEncrypted(algo, value string) bool {
+	ciphertext, ok := strings.CutPrefix(value, Header(algo))
+	if !ok {
+		return false
+	}
+	isCiphertext, ok := formats[algo]
+	return !ok || isCiphertext(ciphertext)
+}
+
+// CheckAlgo returns an error if the value was encrypted by another provider
+// than the configured one, so it is neither read as plain text nor encrypted
+// a second time.
+func CheckAlgo(svc Service, value string) error {
+	for algo := range formats {
+		if algo != svc.Algo() && IsEncrypted(algo, value) {
+			return fmt.Errorf("value is encrypted with %s, but %s is configured", algo, svc.Algo())
+		}
+	}
+	return nil
+}
+
+// decodesTo returns whether the value is unpadded standard base64 of at least
+// the given number of bytes, the encoding of all ciphertexts.
+func decodesTo(value string, minSize int) bool {
+	data, err := base64.RawStdEncoding.DecodeString(value)
+	return err == nil && len(data) >= minSize
+}
@@ -0,0 +1,103 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package encryption
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/tink"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	TinkAlgo = "tink"
+
+	// tinkMinCiphertextSize is the size of the nonce and tag of AES-GCM, the
+	// smallest overhead of the AEAD primitives of Tink
+	tinkMinCiphertextSize = 28
+)
+
+type tinkEncryptionService struct {
+	primitive tink.AEAD
+}
+
+// NewTink returns an encryption service using the AEAD primitive of the Tink
+// keyset stored as JSON in the given file. The primary key of the keyset
+// encrypts, all keys of the keyset decrypt, so keys are rotated by adding a
+// new primary key to the keyset.
+func NewTink(keysetFile string) (Service, error) {
+	log.Info().Msg("initializing Tink encryption service")
+
+	file, err := os.Open(keysetFile)
+	if err != nil {
+		return nil, newKeyLoadingError(err)
+	}
+	defer file.Close()
+
+	handle, err := insecurecleartextkeyset.Read(keyset.NewJSONReader(file))
+	if err != nil {
+		return nil, newKeyLoadingError(err)
+	}
+
+	primitive, err := aead.New(handle)
+	if err != nil {
+		return nil, newCipherLoadingError(err)
+	}
+
+	log.Info().Uint32("key-id", handle.KeysetInfo().GetPrimaryKeyId()).Int("keys", len(handle.KeysetInfo().GetKeyInfo())).
+		Msg("Tink encryption service has been initialized")
+	return &tinkEncryptionService{primitive: primitive}, nil
+}
+
+func (svc *tinkEncryptionService) Algo() string {
+	return TinkAlgo
+}
+
+func (svc *tinkEncryptionService) Encrypt(plaintext, associatedData string) (string, error) {
+	ciphertext, err := svc.primitive.Encrypt([]byte(plaintext), []byte(associatedData))
+	if err != nil {
+		return "", fmt.Errorf("encryption error: %w", err)
+	}
+	return base64.RawStdEncoding.EncodeToString(ciphertext), nil
+}
+
+func isTinkCiphertext(ciphertext string) bool {
+	return decodesTo(ciphertext, tinkMinCiphertextSize)
+}
+
+func (svc *tinkEncryptionService) Decrypt(ciphertext, associatedData string) (string, error) {
+	bytes, err := base64.RawStdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", newBase64DecryptionError(err)
+	}
+
+	plaintext, err := svc.primitive.Decrypt(bytes, []byte(associatedData))
+	if err != nil {
+		return "", newDecryptionError(err)
+	}
+	return string(plaintext), nil
+}
+
+func newKeyLoadingError(e error) error {
+	return fmt.Errorf("failed loading encryption key: %w", e)
+}
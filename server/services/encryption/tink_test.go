@@ -0,0 +1,77 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package encryption
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/keyset"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTink(t *testing.T) {
+	manager := keyset.NewManager()
+	keyID, err := manager.Add(aead.AES256GCMKeyTemplate())
+	assert.NoError(t, err)
+	assert.NoError(t, manager.SetPrimary(keyID))
+	keysetFile := writeKeyset(t, manager)
+
+	svc, err := NewTink(keysetFile)
+	assert.NoError(t, err)
+	assert.Equal(t, TinkAlgo, svc.Algo())
+
+	ciphertext, err := svc.Encrypt("secret value", "id1")
+	assert.NoError(t, err)
+	assert.True(t, isTinkCiphertext(ciphertext))
+	plaintext, err := svc.Decrypt(ciphertext, "id1")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret value", plaintext)
+
+	_, err = svc.Decrypt(ciphertext, "id2")
+	assert.Error(t, err)
+
+	// a new primary key still decrypts values of the previous one
+	keyID, err = manager.Add(aead.AES256GCMKeyTemplate())
+	assert.NoError(t, err)
+	assert.NoError(t, manager.SetPrimary(keyID))
+	rotated, err := NewTink(writeKeyset(t, manager))
+	assert.NoError(t, err)
+
+	plaintext, err = rotated.Decrypt(ciphertext, "id1")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret value", plaintext)
+
+	_, err = NewTink(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func writeKeyset(t *testing.T, manager *keyset.Manager) string {
+	handle, err := manager.Handle()
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "keyset.json")
+	file, err := os.Create(path)
+	assert.NoError(t, err)
+	defer file.Close()
+	assert.NoError(t, insecurecleartextkeyset.Write(handle, keyset.NewJSONWriter(file)))
+	return path
+}
@@ -0,0 +1,276 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package log
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"time"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+)
+
+// Archive formats.
+const (
+	ArchiveTarGz = "tar.gz"
+	ArchiveZip   = "zip"
+)
+
+// ArchiveOptions configures a log archive.
+type ArchiveOptions struct {
+	// Format is the archive format, ArchiveTarGz or ArchiveZip.
+	Format string
+	// StripANSI removes ANSI escape sequences like colors from the logs.
+	StripANSI bool
+}
+
+// ansiEscape matches CSI sequences like colors and OSC sequences like titles.
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)`)
+
+// pathUnsafe matches characters which are replaced in file names.
+var pathUnsafe = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+type archiveManifest struct {
+	Repo      string             `json:"repo"`
+	Number    int64              `json:"number"`
+	Status    model.StatusValue  `json:"status"`
+	Event     model.WebhookEvent `json:"event"`
+	Commit    string             `json:"commit"`
+	Branch    string             `json:"branch"`
+	Created   int64              `json:"created"`
+	Started   int64              `json:"started"`
+	Finished  int64              `json:"finished"`
+	Workflows []archiveWorkflow  `json:"workflows"`
+}
+
+type archiveWorkflow struct {
+	Name  string            `json:"name"`
+	State model.StatusValue `json:"state"`
+	Error string            `json:"error,omitempty"`
+	Steps []archiveStep     `json:"steps"`
+}
+
+type archiveStep struct {
+	ID       int64             `json:"id"`
+	Name     string            `json:"name"`
+	State    model.StatusValue `json:"state"`
+	ExitCode int               `json:"exit_code"`
+	Error    string            `json:"error,omitempty"`
+	File     string            `json:"file"`
+}
+
+// archiveWriter abstracts the tar.gz and zip archive formats.
+type archiveWriter interface {
+	// add adds a file with the content written by write.
+	add(name string, modified time.Time, write func(io.Writer) error) error
+	Close() error
+}
+
+// WriteArchive writes an archive with a plain text log file per step of the
+// workflows of the pipeline and a manifest with the states of the steps. The
+// logs of a step are read in pages and written to the archive as they are read.
+func WriteArchive(w io.Writer, service Service, repo *model.Repo, pipeline *model.Pipeline, workflows []*model.Workflow, opts ArchiveOptions) error {
+	var archive archiveWriter
+	switch opts.Format {
+	case ArchiveTarGz:
+		archive = newTarGzWriter(w)
+	case ArchiveZip:
+		archive = &zipWriter{zip.NewWriter(w)}
+	default:
+		return fmt.Errorf("unknown archive format '%s'", opts.Format)
+	}
+
+	dir := pathUnsafe.ReplaceAllString(fmt.Sprintf("%s-%d", repo.FullName, pipeline.Number), "_")
+	manifest := archiveManifest{
+		Repo:     repo.FullName,
+		Number:   pipeline.Number,
+		Status:   pipeline.Status,
+		Event:    pipeline.Event,
+		Commit:   pipeline.Commit,
+		Branch:   pipeline.Branch,
+		Created:  pipeline.Created,
+		Started:  pipeline.Started,
+		Finished: pipeline.Finished,
+	}
+
+	for _, workflow := range workflows {
+		wf := archiveWorkflow{Name: workflow.Name, State: workflow.State, Error: workflow.Error}
+		for _, step := range workflow.Children {
+			file := path.Join(
+				fmt.Sprintf("%d-%s", workflow.PID, pathUnsafe.ReplaceAllString(workflow.Name, "_")),
+				fmt.Sprintf("%d-%s.log", step.PID, pathUnsafe.ReplaceAllString(step.Name, "_")),
+			)
+			wf.Steps = append(wf.Steps, archiveStep{
+				ID:       step.ID,
+				Name:     step.Name,
+				State:    step.State,
+				ExitCode: step.ExitCode,
+				Error:    step.Error,
+				File:     file,
+			})
+
+			err := archive.add(path.Join(dir, file), unixOrNow(step.Finished), func(w io.Writer) error {
+				err := FindPages(service, step, func(entries []*model.LogEntry) (bool, error) {
+					return true, writeLogText(w, entries, opts.StripANSI)
+				})
+				if err != nil {
+					return fmt.Errorf("could not load logs of step %d: %w", step.ID, err)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		manifest.Workflows = append(manifest.Workflows, wf)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = archive.add(path.Join(dir, "manifest.json"), unixOrNow(pipeline.Finished), func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+// unixOrNow returns the time of the timestamp or the current time for steps
+// and pipelines which have not finished yet.
+func unixOrNow(timestamp int64) time.Time {
+	if timestamp == 0 {
+		return time.Now()
+	}
+	return time.Unix(timestamp, 0)
+}
+
+// writeLogText writes the output lines of the log entries as plain text.
+func writeLogText(w io.Writer, entries []*model.LogEntry, stripANSI bool) error {
+	for _, entry := range entries {
+		var line []byte
+		switch entry.Type {
+		case model.LogEntryStdout, model.LogEntryStderr, model.LogEntryTruncated:
+			line = entry.Data
+		case model.LogEntrySectionStart:
+			line = append([]byte("::group::"), entry.Data...)
+		case model.LogEntrySectionEnd:
+			line = []byte("::endgroup::")
+		default:
+			continue
+		}
+		if stripANSI {
+			line = ansiEscape.ReplaceAll(line, nil)
+		}
+		if _, err := w.Write(line); err != nil {
+			return err
+		}
+		if !bytes.HasSuffix(line, []byte{'\n'}) {
+			if _, err := w.Write([]byte{'\n'}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type tarGzWriter struct {
+	gz  *gzip.Writer
+	tar *tar.Writer
+}
+
+func newTarGzWriter(w io.Writer) *tarGzWriter {
+	gz := gzip.NewWriter(w)
+	return &tarGzWriter{gz: gz, tar: tar.NewWriter(gz)}
+}
+
+// add writes the file to a temporary file first, as the size is part of the
+// tar header before the content.
+func (w *tarGzWriter) add(name string, modified time.Time, write func(io.Writer) error) error {
+	tmp, err := os.CreateTemp("", "woodpecker-log-archive-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	buf := bufio.NewWriter(tmp)
+	if err := write(buf); err != nil {
+		return err
+	}
+	if err := buf.Flush(); err != nil {
+		return err
+	}
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	err = w.tar.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     size,
+		Mode:     0o644, //nolint:mnd
+		ModTime:  modified,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w.tar, tmp)
+	return err
+}
+
+func (w *tarGzWriter) Close() error {
+	if err := w.tar.Close(); err != nil {
+		return err
+	}
+	return w.gz.Close()
+}
+
+type zipWriter struct {
+	*zip.Writer
+}
+
+func (w *zipWriter) add(name string, modified time.Time, write func(io.Writer) error) error {
+	f, err := w.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: modified,
+	})
+	if err != nil {
+		return err
+	}
+	return write(f)
+}
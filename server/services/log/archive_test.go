@@ -0,0 +1,147 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package log
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+)
+
+func TestWriteArchive(t *testing.T) {
+	repo := &model.Repo{FullName: "octocat/hello-world"}
+	pipeline := &model.Pipeline{Number: 5, Status: model.StatusFailure}
+	workflows := []*model.Workflow{{
+		PID:   1,
+		Name:  "build & test",
+		State: model.StatusFailure,
+		Children: []*model.Step{
+			{ID: 1, PID: 2, Name: "build", State: model.StatusSuccess},
+			{ID: 2, PID: 3, Name: "test", State: model.StatusFailure, ExitCode: 1},
+		},
+	}}
+	service := &memoryService{logs: map[int64][]*model.LogEntry{
+		1: {{Data: []byte("\x1b[32mok\x1b[0m")}, {Data: []byte("done\n")}},
+		2: {{Data: []byte("\x1b]0;title\x07FAIL")}, {Type: model.LogEntryExitCode, Data: []byte("1")}},
+	}}
+
+	for _, test := range []struct {
+		format string
+		read   func(t *testing.T, data []byte) map[string]string
+	}{
+		{format: ArchiveTarGz, read: readTarGz},
+		{format: ArchiveZip, read: readZip},
+	} {
+		t.Run(test.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := WriteArchive(&buf, service, repo, pipeline, workflows, ArchiveOptions{Format: test.format, StripANSI: true})
+			require.NoError(t, err)
+
+			files := test.read(t, buf.Bytes())
+			assert.Equal(t, "ok\ndone\n", files["octocat_hello-world-5/1-build_test/2-build.log"])
+			assert.Equal(t, "FAIL\n", files["octocat_hello-world-5/1-build_test/3-test.log"])
+
+			var manifest archiveManifest
+			require.NoError(t, json.Unmarshal([]byte(files["octocat_hello-world-5/manifest.json"]), &manifest))
+			assert.Equal(t, model.StatusFailure, manifest.Status)
+			if assert.Len(t, manifest.Workflows, 1) && assert.Len(t, manifest.Workflows[0].Steps, 2) {
+				assert.Equal(t, 1, manifest.Workflows[0].Steps[1].ExitCode)
+				assert.Equal(t, "1-build_test/3-test.log", manifest.Workflows[0].Steps[1].File)
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	err := WriteArchive(&buf, service, repo, pipeline, workflows, ArchiveOptions{Format: ArchiveTarGz})
+	require.NoError(t, err)
+	assert.Equal(t, "\x1b[32mok\x1b[0m\ndone\n", readTarGz(t, buf.Bytes())["octocat_hello-world-5/1-build_test/2-build.log"])
+
+	assert.Error(t, WriteArchive(&buf, service, repo, pipeline, workflows, ArchiveOptions{Format: "rar"}))
+}
+
+func TestWriteArchivePages(t *testing.T) {
+	repo := &model.Repo{FullName: "octocat/hello-world"}
+	pipeline := &model.Pipeline{Number: 5}
+	workflows := []*model.Workflow{{PID: 1, Name: "build", Children: []*model.Step{{ID: 1, PID: 2, Name: "build"}}}}
+
+	var lines []string
+	for i := 0; i < 2500; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	service := &indexedService{memoryService: memoryService{logs: map[int64][]*model.LogEntry{1: logLines(1, lines...)}}}
+
+	for _, test := range []struct {
+		format string
+		read   func(t *testing.T, data []byte) map[string]string
+	}{
+		{format: ArchiveTarGz, read: readTarGz},
+		{format: ArchiveZip, read: readZip},
+	} {
+		t.Run(test.format, func(t *testing.T) {
+			service.finds = 0
+			var buf bytes.Buffer
+			require.NoError(t, WriteArchive(&buf, service, repo, pipeline, workflows, ArchiveOptions{Format: test.format}))
+			assert.Equal(t, strings.Join(lines, "\n")+"\n", test.read(t, buf.Bytes())["octocat_hello-world-5/1-build/2-build.log"])
+			assert.Equal(t, 3, service.finds)
+		})
+	}
+}
+
+func readTarGz(t *testing.T, data []byte) map[string]string {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	files := make(map[string]string)
+	r := tar.NewReader(gz)
+	for {
+		header, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return files
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		files[header.Name] = string(content)
+	}
+}
+
+func readZip(t *testing.T, data []byte) map[string]string {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	files := make(map[string]string)
+	for _, f := range r.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		_ = rc.Close()
+		files[f.Name] = string(content)
+	}
+	return files
+}
@@ -0,0 +1,77 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package log
+
+import (
+	"context"
+	"fmt"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/store"
+)
+
+// BackfillOptions configures the backfill of the log sizes of steps.
+type BackfillOptions struct {
+	// AfterStepID resumes a backfill after the step with this id.
+	AfterStepID int64
+	// Progress is called after the log size of a step was counted.
+	Progress func(step *model.Step, size int64)
+}
+
+// BackfillSizes counts the log size of the finished steps without one, e.g.
+// because their logs were stored before log sizes were tracked. Like the logs
+// received from agents, the size is the sum of the data of the log entries.
+func BackfillSizes(ctx context.Context, s store.Store, service Service, opts BackfillOptions) error {
+	after := opts.AfterStepID
+	for {
+		steps, err := s.StepListAfter(after, migrateBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(steps) == 0 {
+			return nil
+		}
+
+		for _, step := range steps {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			after = step.ID
+			if step.LogSize > 0 || step.Running() {
+				continue
+			}
+
+			entries, err := service.LogFind(step)
+			if err != nil {
+				return fmt.Errorf("could not read logs of step %d: %w", step.ID, err)
+			}
+			var size int64
+			for _, entry := range entries {
+				size += int64(len(entry.Data))
+			}
+			if size > 0 {
+				if err := s.StepAddLogSize(step, size, int64(len(entries))); err != nil {
+					return fmt.Errorf("could not update log size of step %d: %w", step.ID, err)
+				}
+			}
+			if opts.Progress != nil {
+				opts.Progress(step, size)
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package log
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/store/mocks"
+)
+
+func TestBackfillSizes(t *testing.T) {
+	steps := []*model.Step{
+		{ID: 1, State: model.StatusSuccess},
+		{ID: 2, State: model.StatusSuccess, LogSize: 42},
+		{ID: 3, State: model.StatusRunning},
+		{ID: 4, State: model.StatusFailure},
+	}
+	s := mocks.NewStore(t)
+	s.On("StepListAfter", mock.Anything, migrateBatchSize).Return(func(id int64, _ int) ([]*model.Step, error) {
+		var after []*model.Step
+		for _, step := range steps {
+			if step.ID > id {
+				after = append(after, step)
+			}
+		}
+		return after, nil
+	})
+	s.On("StepAddLogSize", steps[0], int64(7), int64(2)).Return(nil).Once()
+
+	service := &memoryService{logs: map[int64][]*model.LogEntry{
+		1: {{StepID: 1, Data: []byte("abc")}, {StepID: 1, Data: []byte("defg")}},
+		2: {{StepID: 2, Data: []byte("ignored")}},
+		3: {{StepID: 3, Data: []byte("running")}},
+	}}
+
+	var counted []int64
+	progress := func(step *model.Step, _ int64) { counted = append(counted, step.ID) }
+	err := BackfillSizes(context.Background(), s, service, BackfillOptions{Progress: progress})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 4}, counted)
+
+	// resume after the first step
+	counted = nil
+	err = BackfillSizes(context.Background(), s, service, BackfillOptions{AfterStepID: 1, Progress: progress})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{4}, counted)
+}
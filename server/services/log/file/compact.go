@@ -0,0 +1,337 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package file
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/services/log"
+)
+
+// The logs of finished steps are compacted into blocks of gzip compressed
+// JSON lines (<id>.json.gz) and an index (<id>.idx) of the blocks. Each block
+// is a separate gzip member, so a block can be read without decompressing
+// the ones before it.
+//
+// All entries are moved into the blocks. To publish the blocks, the JSON lines
+// file is first renamed to <id>.json.old, then the index is written and
+// finally the old file is removed. Should the server stop in between, the old
+// file is restored or removed by recoverCompaction.
+const (
+	blockEntries = 1000
+	blockSize    = 1024 * 1024
+)
+
+// block is an index record describing a compressed block.
+type block struct {
+	Offset  int64
+	Size    int64
+	Entries int64
+	MinLine int64
+	MaxLine int64
+}
+
+func (l logStore) compactedPath(id int64) string {
+	return filepath.Join(l.base, fmt.Sprintf("%d.json.gz", id))
+}
+
+func (l logStore) indexPath(id int64) string {
+	return filepath.Join(l.base, fmt.Sprintf("%d.idx", id))
+}
+
+func (l logStore) oldPath(id int64) string {
+	return filepath.Join(l.base, fmt.Sprintf("%d.json.old", id))
+}
+
+// recoverCompaction cleans up after a compaction which was interrupted while
+// publishing the blocks. The caller must hold the lock.
+func (l logStore) recoverCompaction(stepID int64) error {
+	if _, err := os.Stat(l.oldPath(stepID)); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	_, err := os.Stat(l.indexPath(stepID))
+	if err == nil {
+		// the index was written, so the old entries are part of the blocks
+		return os.Remove(l.oldPath(stepID))
+	}
+	if !os.IsNotExist(err) {
+		return err
+	}
+	return os.Rename(l.oldPath(stepID), l.filePath(stepID))
+}
+
+// stepFiles are the log files of a step opened at the same time, so they are
+// consistent even if the logs are compacted while reading them.
+type stepFiles struct {
+	index      []block
+	compressed *os.File
+	json       *os.File
+}
+
+func (l logStore) open(stepID int64) (*stepFiles, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.recoverCompaction(stepID); err != nil {
+		return nil, err
+	}
+
+	files := &stepFiles{}
+	index, err := readIndex(l.indexPath(stepID))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		if files.compressed, err = os.Open(l.compactedPath(stepID)); err != nil {
+			return nil, err
+		}
+		files.index = index
+	}
+
+	if files.json, err = os.Open(l.filePath(stepID)); err != nil && !os.IsNotExist(err) {
+		files.close()
+		return nil, err
+	}
+	return files, nil
+}
+
+func (f *stepFiles) close() {
+	if f.compressed != nil {
+		_ = f.compressed.Close()
+	}
+	if f.json != nil {
+		_ = f.json.Close()
+	}
+}
+
+// readCompacted calls fn for the entries of the blocks which may contain
+// entries selected by the range. Blocks which are skipped entirely by the
+// offset are only counted.
+func (f *stepFiles) readCompacted(r log.Range, skipped *int, fn func(*model.LogEntry) bool) (bool, error) {
+	for _, b := range f.index {
+		if r.ToLine != 0 && b.MinLine > int64(r.ToLine) || b.MaxLine < int64(r.FromLine) {
+			continue
+		}
+		if r.Contains(int(b.MinLine)) && r.Contains(int(b.MaxLine)) && *skipped+int(b.Entries) <= r.Offset {
+			*skipped += int(b.Entries)
+			continue
+		}
+
+		gz, err := gzip.NewReader(io.NewSectionReader(f.compressed, b.Offset, b.Size))
+		if err != nil {
+			return false, err
+		}
+		more, err := readEntries(gz, fn)
+		_ = gz.Close()
+		if err != nil || !more {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func readIndex(path string) ([]block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	index := make([]block, len(data)/binary.Size(block{}))
+	if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, index); err != nil {
+		return nil, fmt.Errorf("invalid log index %s: %w", path, err)
+	}
+	return index, nil
+}
+
+func (l logStore) removeCompacted(stepID int64) (bool, error) {
+	removed := false
+	for _, path := range []string{l.indexPath(stepID), l.compactedPath(stepID), l.oldPath(stepID)} {
+		err := os.Remove(path)
+		if err == nil {
+			removed = true
+		} else if !os.IsNotExist(err) {
+			return removed, err
+		}
+	}
+	return removed, nil
+}
+
+// compact compresses the JSON lines logs of a step. Logs appended after the
+// compaction are written to a new JSON lines file.
+func (l logStore) compact(stepID int64) error {
+	l.mu.Lock()
+	if err := l.recoverCompaction(stepID); err != nil {
+		l.mu.Unlock()
+		return err
+	}
+	_, compacting := l.compacting[stepID]
+	_, indexErr := os.Stat(l.indexPath(stepID))
+	info, err := os.Stat(l.filePath(stepID))
+	if compacting || indexErr == nil || err != nil {
+		l.mu.Unlock()
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	l.compacting[stepID] = struct{}{}
+	l.mu.Unlock()
+
+	defer func() {
+		l.mu.Lock()
+		delete(l.compacting, stepID)
+		l.mu.Unlock()
+	}()
+
+	in, err := os.Open(l.filePath(stepID))
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.CreateTemp(l.base, fmt.Sprintf(".%d.json.gz.*", stepID))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = out.Close()
+		_ = os.Remove(out.Name())
+	}()
+
+	w := &blockWriter{out: out}
+	if _, err := readEntries(io.LimitReader(in, info.Size()), w.add); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// the logs were deleted while compacting
+	if _, err := os.Stat(l.filePath(stepID)); os.IsNotExist(err) {
+		return nil
+	}
+
+	// add the logs appended while compacting
+	if _, err := in.Seek(info.Size(), io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := readEntries(in, w.add); err != nil {
+		return err
+	}
+	if err := w.flush(); err != nil {
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		return err
+	}
+
+	index := new(bytes.Buffer)
+	if err := binary.Write(index, binary.LittleEndian, w.index); err != nil {
+		return err
+	}
+	if err := os.WriteFile(l.indexPath(stepID)+".tmp", index.Bytes(), 0o600); err != nil {
+		return err
+	}
+	if err := os.Rename(out.Name(), l.compactedPath(stepID)); err != nil {
+		return err
+	}
+
+	// the logs count as compacted once the index exists, so the JSON lines
+	// file must be gone before, otherwise its entries would be read twice
+	if err := os.Rename(l.filePath(stepID), l.oldPath(stepID)); err != nil {
+		return err
+	}
+	if err := os.Rename(l.indexPath(stepID)+".tmp", l.indexPath(stepID)); err != nil {
+		return err
+	}
+	return os.Remove(l.oldPath(stepID))
+}
+
+// blockWriter writes log entries as compressed blocks.
+type blockWriter struct {
+	out    io.Writer
+	offset int64
+	index  []block
+
+	buf     bytes.Buffer
+	current block
+	err     error
+}
+
+func (w *blockWriter) add(entry *model.LogEntry) bool {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		w.err = err
+		return false
+	}
+
+	if w.current.Entries == 0 || int64(entry.Line) < w.current.MinLine {
+		w.current.MinLine = int64(entry.Line)
+	}
+	if w.current.Entries == 0 || int64(entry.Line) > w.current.MaxLine {
+		w.current.MaxLine = int64(entry.Line)
+	}
+	w.current.Entries++
+	w.buf.Write(line)
+	w.buf.WriteByte('\n')
+
+	if w.current.Entries >= blockEntries || w.buf.Len() >= blockSize {
+		w.err = w.flush()
+	}
+	return w.err == nil
+}
+
+func (w *blockWriter) flush() error {
+	if w.err != nil {
+		return w.err
+	}
+	if w.current.Entries == 0 {
+		return nil
+	}
+
+	compressed := new(bytes.Buffer)
+	gz := gzip.NewWriter(compressed)
+	if _, err := gz.Write(w.buf.Bytes()); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	if _, err := w.out.Write(compressed.Bytes()); err != nil {
+		return err
+	}
+
+	w.current.Offset = w.offset
+	w.current.Size = int64(compressed.Len())
+	w.index = append(w.index, w.current)
+	w.offset += w.current.Size
+	w.current = block{}
+	w.buf.Reset()
+	return nil
+}
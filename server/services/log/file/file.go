@@ -2,11 +2,16 @@ package file
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 
 	logger "github.com/rs/zerolog/log"
 	"go.woodpecker-ci.org/woodpecker/v2/pipeline"
@@ -22,6 +27,10 @@ const (
 
 type logStore struct {
 	base string
+
+	// mu guards opening, appending and swapping the log files
+	mu         *sync.Mutex
+	compacting map[int64]struct{}
 }
 
 func NewLogStore(base string) (log.Service, error) {
@@ -34,7 +43,7 @@ func NewLogStore(base string) (log.Service, error) {
 			return nil, err
 		}
 	}
-	return logStore{base: base}, nil
+	return logStore{base: base, mu: &sync.Mutex{}, compacting: make(map[int64]struct{})}, nil
 }
 
 func (l logStore) filePath(id int64) string {
@@ -42,39 +51,77 @@ func (l logStore) filePath(id int64) string {
 }
 
 func (l logStore) LogFind(step *model.Step) ([]*model.LogEntry, error) {
-	filename := l.filePath(step.ID)
-	file, err := os.Open(filename)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil
+	return l.LogFindRange(step, log.Range{})
+}
+
+// LogFindRange returns the selected log entries of the step. Of compacted
+// logs only the blocks containing the selected entries are read.
+func (l logStore) LogFindRange(step *model.Step, r log.Range) ([]*model.LogEntry, error) {
+	var entries []*model.LogEntry
+	skipped := 0
+	collect := func(entry *model.LogEntry) bool {
+		if !r.Contains(entry.Line) {
+			return true
+		}
+		if skipped < r.Offset {
+			skipped++
+			return true
 		}
+		entries = append(entries, entry)
+		return r.Limit == 0 || len(entries) < r.Limit
+	}
+
+	files, err := l.open(step.ID)
+	if err != nil {
 		return nil, err
 	}
+	defer files.close()
+
+	more, err := files.readCompacted(r, &skipped, collect)
+	if err != nil || !more || files.json == nil {
+		return entries, err
+	}
+	// logs appended after the compaction
+	_, err = readEntries(files.json, collect)
+	return entries, err
+}
 
+// LogCompact compresses the logs of a finished step.
+func (l logStore) LogCompact(step *model.Step) error {
+	return l.compact(step.ID)
+}
+
+func readEntries(r io.Reader, fn func(*model.LogEntry) bool) (bool, error) {
 	buf := make([]byte, 0, bufio.MaxScanTokenSize)
-	s := bufio.NewScanner(file)
+	s := bufio.NewScanner(r)
 	s.Buffer(buf, maxLineLength)
 
-	var entries []*model.LogEntry
 	for s.Scan() {
-		j := s.Text()
-		if len(strings.TrimSpace(j)) == 0 {
+		j := s.Bytes()
+		if len(bytes.TrimSpace(j)) == 0 {
 			continue
 		}
 		entry := &model.LogEntry{}
-		err = json.Unmarshal([]byte(j), entry)
-		if err != nil {
-			return nil, err
+		if err := json.Unmarshal(j, entry); err != nil {
+			return false, err
+		}
+		if !fn(entry) {
+			return false, nil
 		}
-		entries = append(entries, entry)
 	}
-
-	return entries, nil
+	return true, s.Err()
 }
 
 func (l logStore) LogAppend(step *model.Step, logEntries []*model.LogEntry) error {
 	path := l.filePath(step.ID)
 
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.recoverCompaction(step.ID); err != nil {
+		return err
+	}
+
 	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
 	if err != nil {
 		logger.Error().Err(err).Msgf("could not open log file %s", path)
@@ -100,5 +147,62 @@ func (l logStore) LogAppend(step *model.Step, logEntries []*model.LogEntry) erro
 }
 
 func (l logStore) LogDelete(step *model.Step) error {
-	return os.Remove(l.filePath(step.ID))
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	removed, err := l.removeCompacted(step.ID)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(l.searchIndexPath(step.ID)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	err = os.Remove(l.filePath(step.ID))
+	if removed && os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (l logStore) LogStepIDs() ([]int64, error) {
+	files, err := os.ReadDir(l.base)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int64]struct{})
+	var ids []int64
+	for _, file := range files {
+		name, _, _ := strings.Cut(file.Name(), ".")
+		id, err := strconv.ParseInt(name, 10, 64)
+		if err != nil || file.IsDir() {
+			continue
+		}
+		if _, ok := seen[id]; !ok {
+			seen[id] = struct{}{}
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+func (l logStore) searchIndexPath(id int64) string {
+	return filepath.Join(l.base, fmt.Sprintf("%d.search", id))
+}
+
+func (l logStore) LogSearchIndexFind(step *model.Step) ([]byte, error) {
+	index, err := os.ReadFile(l.searchIndexPath(step.ID))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return index, err
+}
+
+func (l logStore) LogSearchIndexSave(step *model.Step, index []byte) error {
+	path := l.searchIndexPath(step.ID)
+	if err := os.WriteFile(path+".tmp", index, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
 }
@@ -0,0 +1,137 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package file
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/services/log"
+)
+
+func TestLogStore(t *testing.T) {
+	base := t.TempDir()
+	s, err := NewLogStore(base)
+	require.NoError(t, err)
+	store := s.(logStore)
+
+	step := &model.Step{ID: 1, State: model.StatusRunning}
+	var entries []*model.LogEntry
+	for i := 0; i < 2500; i++ {
+		entries = append(entries, &model.LogEntry{StepID: 1, Line: i, Data: []byte(fmt.Sprintf("line %d", i))})
+	}
+	for i := 0; i < len(entries); i += 100 {
+		require.NoError(t, store.LogAppend(step, entries[i:i+100]))
+	}
+
+	found, err := store.LogFind(step)
+	assert.NoError(t, err)
+	assert.Equal(t, entries, found)
+	assert.NoFileExists(t, store.indexPath(1))
+
+	step.State = model.StatusSuccess
+	assert.NoError(t, store.LogCompact(step))
+	assert.FileExists(t, store.indexPath(1))
+	assert.FileExists(t, store.compactedPath(1))
+	assert.NoFileExists(t, store.filePath(1))
+	assert.NoFileExists(t, store.oldPath(1))
+	// compacted logs are not compacted again
+	assert.NoError(t, store.LogCompact(step))
+
+	index, err := readIndex(store.indexPath(1))
+	assert.NoError(t, err)
+	assert.Len(t, index, 3)
+
+	for _, test := range []struct {
+		r        log.Range
+		from, to int
+	}{
+		{r: log.Range{Offset: 1500, Limit: 10}, from: 1500, to: 1510},
+		{r: log.Range{Offset: 2490}, from: 2490, to: 2500},
+		{r: log.Range{FromLine: 999, ToLine: 1001}, from: 999, to: 1002},
+		{r: log.Range{FromLine: 1000, Offset: 5, Limit: 5}, from: 1005, to: 1010},
+	} {
+		found, err := store.LogFindRange(step, test.r)
+		assert.NoError(t, err)
+		assert.Equal(t, entries[test.from:test.to], found, "%+v", test.r)
+		assert.Equal(t, test.r.Apply(entries), found, "%+v", test.r)
+	}
+
+	found, err = store.LogFindRange(step, log.Range{FromLine: 3000})
+	assert.NoError(t, err)
+	assert.Empty(t, found)
+
+	// logs appended after the compaction are kept in a new file
+	late := &model.LogEntry{StepID: 1, Line: 2500, Data: []byte("late")}
+	assert.NoError(t, store.LogAppend(step, []*model.LogEntry{late}))
+	found, err = store.LogFindRange(step, log.Range{Offset: 2499})
+	assert.NoError(t, err)
+	assert.Equal(t, []*model.LogEntry{entries[2499], late}, found)
+
+	assert.NoError(t, store.LogDelete(step))
+	files, err := os.ReadDir(base)
+	assert.NoError(t, err)
+	assert.Empty(t, files)
+	assert.Error(t, store.LogDelete(step))
+
+	found, err = store.LogFind(step)
+	assert.NoError(t, err)
+	assert.Empty(t, found)
+}
+
+func TestLogStoreInterruptedCompaction(t *testing.T) {
+	s, err := NewLogStore(t.TempDir())
+	require.NoError(t, err)
+	store := s.(logStore)
+
+	step := &model.Step{ID: 1, State: model.StatusSuccess}
+	entries := []*model.LogEntry{
+		{StepID: 1, Line: 0, Data: []byte("first")},
+		{StepID: 1, Line: 1, Data: []byte("second")},
+	}
+	require.NoError(t, store.LogAppend(step, entries))
+	uncompacted, err := os.ReadFile(store.filePath(1))
+	require.NoError(t, err)
+	require.NoError(t, store.LogCompact(step))
+
+	// interrupted after the index was written
+	require.NoError(t, os.WriteFile(store.oldPath(1), uncompacted, 0o600))
+	found, err := store.LogFind(step)
+	assert.NoError(t, err)
+	assert.Equal(t, entries, found)
+	assert.NoFileExists(t, store.oldPath(1))
+
+	// interrupted before the index was written
+	require.NoError(t, os.Remove(store.indexPath(1)))
+	require.NoError(t, os.WriteFile(store.oldPath(1), uncompacted, 0o600))
+	found, err = store.LogFind(step)
+	assert.NoError(t, err)
+	assert.Equal(t, entries, found)
+	assert.NoFileExists(t, store.oldPath(1))
+	assert.FileExists(t, store.filePath(1))
+
+	require.NoError(t, store.LogCompact(step))
+	found, err = store.LogFind(step)
+	assert.NoError(t, err)
+	assert.Equal(t, entries, found)
+}
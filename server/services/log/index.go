@@ -0,0 +1,117 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package log
+
+import (
+	"hash/fnv"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+)
+
+// SearchIndexStore is implemented by services which can keep a search index
+// of the logs of a step, so searches can skip steps not containing the text.
+type SearchIndexStore interface {
+	// LogSearchIndexFind returns the index of the step or nil if there is none.
+	LogSearchIndexFind(step *model.Step) ([]byte, error)
+	LogSearchIndexSave(step *model.Step, index []byte) error
+}
+
+const (
+	indexHashes       = 3
+	indexBitsPerEntry = 10
+	indexMinBits      = 1024
+	indexMaxBits      = 8 * 1024 * 1024
+)
+
+// searchIndex is a bloom filter of the trigrams of the logs of a step.
+type searchIndex []byte
+
+// newSearchIndex builds the index of the log entries.
+func newSearchIndex(entries []*model.LogEntry) searchIndex {
+	trigrams := make(searchIndexBuilder)
+	trigrams.add(entries)
+	return trigrams.build()
+}
+
+// searchIndexBuilder collects the trigrams of log entries read in pages.
+type searchIndexBuilder map[uint64]struct{}
+
+func (trigrams searchIndexBuilder) add(entries []*model.LogEntry) {
+	for _, entry := range entries {
+		for i := 0; i+3 <= len(entry.Data); i++ {
+			trigrams[trigramHash(entry.Data[i:i+3])] = struct{}{}
+		}
+	}
+}
+
+func (trigrams searchIndexBuilder) build() searchIndex {
+	bits := min(max(len(trigrams)*indexBitsPerEntry, indexMinBits), indexMaxBits)
+	index := make(searchIndex, (bits+7)/8) //nolint:mnd
+	for hash := range trigrams {
+		index.add(hash)
+	}
+	return index
+}
+
+// mayContain returns false if the logs can't contain the text. Texts shorter
+// than a trigram may always be contained.
+func (index searchIndex) mayContain(text string) bool {
+	if len(index) == 0 {
+		return true
+	}
+	for i := 0; i+3 <= len(text); i++ {
+		if !index.has(trigramHash([]byte(text[i : i+3]))) {
+			return false
+		}
+	}
+	return true
+}
+
+func (index searchIndex) add(hash uint64) {
+	for _, bit := range index.bits(hash) {
+		index[bit/8] |= 1 << (bit % 8) //nolint:mnd
+	}
+}
+
+func (index searchIndex) has(hash uint64) bool {
+	for _, bit := range index.bits(hash) {
+		if index[bit/8]&(1<<(bit%8)) == 0 { //nolint:mnd
+			return false
+		}
+	}
+	return true
+}
+
+// bits returns the bits of the hash using double hashing.
+func (index searchIndex) bits(hash uint64) [indexHashes]uint64 {
+	size := uint64(len(index)) * 8 //nolint:mnd
+
+	// split the hash into two 32 bit hashes
+	h1, h2 := hash&0xffffffff, hash>>32 //nolint:mnd
+	var bits [indexHashes]uint64
+	for i := range bits {
+		bits[i] = (h1 + uint64(i)*h2) % size
+	}
+	return bits
+}
+
+func trigramHash(trigram []byte) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write(trigram)
+	return h.Sum64()
+}
@@ -0,0 +1,117 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package log
+
+import (
+	"context"
+	"fmt"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/store"
+)
+
+const migrateBatchSize = 100
+
+// MigrateOptions configures the migration of logs between services.
+type MigrateOptions struct {
+	// AfterStepID resumes a migration after the step with this id.
+	AfterStepID int64
+	// DeleteSource deletes the logs from the source once they are copied.
+	DeleteSource bool
+	// Progress is called after the logs of a step were migrated.
+	Progress func(step *model.Step, entries int)
+}
+
+// Migrate copies the logs of all steps from one service to another and
+// verifies the number of copied entries. Steps with as many entries at the
+// destination as at the source are skipped, so an interrupted migration can
+// be started again.
+func Migrate(ctx context.Context, s store.Store, from, to Service, opts MigrateOptions) error {
+	after := opts.AfterStepID
+	for {
+		steps, err := s.StepListAfter(after, migrateBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(steps) == 0 {
+			return nil
+		}
+
+		for _, step := range steps {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			entries, err := migrateStep(step, from, to, opts.DeleteSource)
+			if err != nil {
+				return fmt.Errorf("could not migrate logs of step %d: %w", step.ID, err)
+			}
+			if opts.Progress != nil {
+				opts.Progress(step, entries)
+			}
+			after = step.ID
+		}
+	}
+}
+
+func migrateStep(step *model.Step, from, to Service, deleteSource bool) (int, error) {
+	entries, err := from.LogFind(step)
+	if err != nil {
+		return 0, err
+	}
+	if len(entries) == 0 {
+		return 0, nil
+	}
+
+	existing, err := to.LogFind(step)
+	if err != nil {
+		return 0, err
+	}
+	if len(existing) != len(entries) {
+		// remove partially copied logs of an interrupted migration
+		if len(existing) > 0 {
+			if err := to.LogDelete(step); err != nil {
+				return 0, err
+			}
+		}
+
+		copied := make([]*model.LogEntry, len(entries))
+		for i, entry := range entries {
+			c := *entry
+			c.ID = 0
+			copied[i] = &c
+		}
+		if err := to.LogAppend(step, copied); err != nil {
+			return 0, err
+		}
+
+		if existing, err = to.LogFind(step); err != nil {
+			return 0, err
+		}
+		if len(existing) != len(entries) {
+			return 0, fmt.Errorf("copied %d of %d log entries", len(existing), len(entries))
+		}
+	}
+
+	if deleteSource {
+		if err := from.LogDelete(step); err != nil {
+			return 0, err
+		}
+	}
+	return len(entries), nil
+}
@@ -0,0 +1,91 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package log
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/store/mocks"
+)
+
+type memoryService struct {
+	logs     map[int64][]*model.LogEntry
+	failStep int64
+}
+
+func (m *memoryService) LogFind(step *model.Step) ([]*model.LogEntry, error) {
+	return m.logs[step.ID], nil
+}
+
+func (m *memoryService) LogAppend(step *model.Step, entries []*model.LogEntry) error {
+	if step.ID == m.failStep {
+		return errors.New("append failed")
+	}
+	m.logs[step.ID] = append(m.logs[step.ID], entries...)
+	return nil
+}
+
+func (m *memoryService) LogDelete(step *model.Step) error {
+	delete(m.logs, step.ID)
+	return nil
+}
+
+func TestMigrate(t *testing.T) {
+	steps := []*model.Step{{ID: 1}, {ID: 2}, {ID: 3}}
+	s := mocks.NewStore(t)
+	s.On("StepListAfter", mock.Anything, migrateBatchSize).Return(func(id int64, _ int) ([]*model.Step, error) {
+		var after []*model.Step
+		for _, step := range steps {
+			if step.ID > id {
+				after = append(after, step)
+			}
+		}
+		return after, nil
+	})
+
+	from := &memoryService{logs: map[int64][]*model.LogEntry{
+		1: {{ID: 10, StepID: 1, Line: 0}, {ID: 11, StepID: 1, Line: 1}},
+		3: {{ID: 12, StepID: 3, Line: 0}},
+	}}
+	// partially copied logs of an interrupted migration
+	to := &memoryService{failStep: 3, logs: map[int64][]*model.LogEntry{
+		1: {{StepID: 1, Line: 0}},
+	}}
+
+	var migrated []int64
+	progress := func(step *model.Step, _ int) { migrated = append(migrated, step.ID) }
+	err := Migrate(context.Background(), s, from, to, MigrateOptions{DeleteSource: true, Progress: progress})
+	assert.ErrorContains(t, err, "step 3")
+	assert.Equal(t, []int64{1, 2}, migrated)
+	assert.Equal(t, []*model.LogEntry{{StepID: 1, Line: 0}, {StepID: 1, Line: 1}}, to.logs[1])
+	assert.NotContains(t, from.logs, int64(1))
+	assert.Contains(t, from.logs, int64(3))
+
+	to.failStep = 0
+	err = Migrate(context.Background(), s, from, to, MigrateOptions{AfterStepID: 2, DeleteSource: true, Progress: progress})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, migrated)
+	assert.Len(t, to.logs[3], 1)
+	assert.Empty(t, from.logs)
+}
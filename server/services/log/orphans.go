@@ -0,0 +1,81 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package log
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/store"
+)
+
+const orphanBatchSize = 1000
+
+// DeleteOrphaned deletes the logs of steps which do not exist anymore and returns
+// the number of steps whose logs were deleted. Services which can't list the steps
+// they keep logs for are skipped.
+func DeleteOrphaned(ctx context.Context, s store.Store, service Service) (int, error) {
+	lister, ok := service.(StepLister)
+	if !ok {
+		return 0, nil
+	}
+	ids, err := lister.LogStepIDs()
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for len(ids) > 0 {
+		if err := ctx.Err(); err != nil {
+			return deleted, err
+		}
+
+		// both lists are ordered by id, so the existing steps can be read in batches
+		steps, err := s.StepListAfter(ids[0]-1, orphanBatchSize)
+		if err != nil {
+			return deleted, err
+		}
+		existing := make(map[int64]struct{}, len(steps))
+		for _, step := range steps {
+			existing[step.ID] = struct{}{}
+		}
+		// all steps up to the last listed one are known, if there are less steps
+		// than requested there are no further steps at all
+		last := ids[len(ids)-1]
+		if len(steps) == orphanBatchSize {
+			last = steps[len(steps)-1].ID
+		}
+
+		for len(ids) > 0 && ids[0] <= last {
+			id := ids[0]
+			ids = ids[1:]
+			if _, ok := existing[id]; ok {
+				continue
+			}
+			// stores can keep files besides the logs, e.g. search indexes, which are
+			// removed even if the logs themselves are gone already
+			if err := service.LogDelete(&model.Step{ID: id}); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return deleted, err
+			}
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,67 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package log
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/store/mocks"
+)
+
+func (m *memoryService) LogStepIDs() ([]int64, error) {
+	var ids []int64
+	for id := range m.logs {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+func TestDeleteOrphaned(t *testing.T) {
+	steps := []*model.Step{{ID: 2}, {ID: 4}}
+	s := mocks.NewStore(t)
+	s.On("StepListAfter", mock.Anything, orphanBatchSize).Return(func(id int64, _ int) ([]*model.Step, error) {
+		var after []*model.Step
+		for _, step := range steps {
+			if step.ID > id {
+				after = append(after, step)
+			}
+		}
+		return after, nil
+	})
+
+	service := &memoryService{logs: map[int64][]*model.LogEntry{
+		1: {{StepID: 1}},
+		2: {{StepID: 2}},
+		3: {{StepID: 3}},
+		4: {{StepID: 4}},
+		5: {{StepID: 5}},
+	}}
+
+	deleted, err := DeleteOrphaned(context.Background(), s, service)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, deleted)
+	ids, _ := service.LogStepIDs()
+	assert.Equal(t, []int64{2, 4}, ids)
+}
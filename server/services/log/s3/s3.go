@@ -0,0 +1,265 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	logger "github.com/rs/zerolog/log"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/services/log"
+)
+
+const (
+	// flush the buffered logs of a step once they reach this size or age.
+	chunkSize     = 1024 * 1024
+	flushInterval = 5 * time.Second
+)
+
+// Config configures the S3 log store.
+type Config struct {
+	Endpoint  string
+	Bucket    string
+	Prefix    string
+	Region    string
+	AccessKey string
+	SecretKey string
+	PathStyle bool
+}
+
+type logStore struct {
+	client *client
+	prefix string
+
+	sync.Mutex
+	buffers map[int64]*buffer
+}
+
+// buffer holds the log entries of a step which are not yet written.
+type buffer struct {
+	data    bytes.Buffer
+	created time.Time
+}
+
+// NewLogStore returns a log store saving the logs of each step as chunks of
+// JSON lines to an S3 compatible bucket. Appended logs are buffered and
+// written as a new object once the buffer is large or old enough.
+func NewLogStore(ctx context.Context, config Config) (log.Service, error) {
+	if config.Bucket == "" {
+		return nil, errors.New("s3 bucket is required")
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = "https://s3.amazonaws.com"
+	}
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint '%s'", config.Endpoint)
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+
+	prefix := strings.Trim(config.Prefix, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	l := &logStore{
+		client: &client{
+			endpoint:  endpoint,
+			bucket:    config.Bucket,
+			region:    config.Region,
+			accessKey: config.AccessKey,
+			secretKey: config.SecretKey,
+			pathStyle: config.PathStyle,
+			http:      http.DefaultClient,
+		},
+		prefix:  prefix,
+		buffers: make(map[int64]*buffer),
+	}
+	go l.flushLoop(ctx)
+	return l, nil
+}
+
+func (l *logStore) stepPrefix(id int64) string {
+	return fmt.Sprintf("%s%d/", l.prefix, id)
+}
+
+func (l *logStore) LogFind(step *model.Step) ([]*model.LogEntry, error) {
+	ctx := context.Background()
+	if err := l.flush(ctx, step.ID); err != nil {
+		return nil, err
+	}
+
+	keys, err := l.client.listObjects(ctx, l.stepPrefix(step.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*model.LogEntry
+	for _, key := range keys {
+		data, err := l.client.getObject(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range bytes.Split(data, []byte{'\n'}) {
+			if len(bytes.TrimSpace(line)) == 0 {
+				continue
+			}
+			entry := &model.LogEntry{}
+			if err := json.Unmarshal(line, entry); err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (l *logStore) LogAppend(step *model.Step, logEntries []*model.LogEntry) error {
+	l.Lock()
+	buf, ok := l.buffers[step.ID]
+	if !ok {
+		buf = &buffer{created: time.Now()}
+		l.buffers[step.ID] = buf
+	}
+	for _, logEntry := range logEntries {
+		if jsonLine, err := json.Marshal(logEntry); err == nil {
+			buf.data.Write(jsonLine)
+			buf.data.WriteByte('\n')
+		} else {
+			logger.Error().Err(err).Msg("could not convert log entry to JSON")
+		}
+	}
+	full := buf.data.Len() >= chunkSize
+	l.Unlock()
+
+	if full {
+		return l.flush(context.Background(), step.ID)
+	}
+	return nil
+}
+
+func (l *logStore) LogDelete(step *model.Step) error {
+	l.Lock()
+	delete(l.buffers, step.ID)
+	l.Unlock()
+
+	ctx := context.Background()
+	keys, err := l.client.listObjects(ctx, l.stepPrefix(step.ID))
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := l.client.deleteObject(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *logStore) LogStepIDs() ([]int64, error) {
+	prefixes, err := l.client.listPrefixes(context.Background(), l.prefix, "/")
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		id, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimPrefix(prefix, l.prefix), "/"), 10, 64)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+// flush writes the buffered logs of the step as a new chunk. Chunk names start
+// with the time of the flush, so listing them returns them in order.
+func (l *logStore) flush(ctx context.Context, stepID int64) error {
+	l.Lock()
+	buf, ok := l.buffers[stepID]
+	delete(l.buffers, stepID)
+	l.Unlock()
+	if !ok || buf.data.Len() == 0 {
+		return nil
+	}
+
+	suffix := make([]byte, 4) //nolint:mnd
+	_, _ = rand.Read(suffix)
+	key := fmt.Sprintf("%s%020d-%s.json", l.stepPrefix(stepID), time.Now().UnixNano(), hex.EncodeToString(suffix))
+	if err := l.client.putObject(ctx, key, buf.data.Bytes()); err != nil {
+		// keep the logs to retry with the next flush
+		l.Lock()
+		if newer, ok := l.buffers[stepID]; ok {
+			buf.data.Write(newer.data.Bytes())
+		}
+		l.buffers[stepID] = buf
+		l.Unlock()
+		return fmt.Errorf("could not write logs of step %d: %w", stepID, err)
+	}
+	return nil
+}
+
+func (l *logStore) flushLoop(ctx context.Context) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			l.flushAll(context.WithoutCancel(ctx), 0)
+			return
+		case <-ticker.C:
+			l.flushAll(ctx, flushInterval)
+		}
+	}
+}
+
+// flushAll flushes the buffers older than the given age.
+func (l *logStore) flushAll(ctx context.Context, age time.Duration) {
+	var stepIDs []int64
+	l.Lock()
+	for stepID, buf := range l.buffers {
+		if time.Since(buf.created) >= age {
+			stepIDs = append(stepIDs, stepID)
+		}
+	}
+	l.Unlock()
+
+	for _, stepID := range stepIDs {
+		if err := l.flush(ctx, stepID); err != nil {
+			logger.Error().Err(err).Msg("could not flush logs to s3")
+		}
+	}
+}
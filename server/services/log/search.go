@@ -0,0 +1,313 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	logger "github.com/rs/zerolog/log"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/store"
+)
+
+// SearchOptions configures a search of the logs of a repository.
+type SearchOptions struct {
+	// Query is the text or regular expression to search for.
+	Query string
+	Regex bool
+	// Since limits the search to pipelines created after this time.
+	Since time.Time
+	// Start continues a search at the pipeline with this number, see
+	// LogSearchResult.Next. Zero starts at the latest pipeline.
+	Start int64
+	// Context is the number of lines to return before and after a match.
+	Context int
+	// Limit is the maximum number of matches to return.
+	Limit int
+	// MaxBytes stops the search once this number of log bytes was scanned.
+	// The interrupted pipeline is searched again when continuing the search,
+	// unless it was the first one searched. Zero scans all logs.
+	MaxBytes int64
+	// Index uses and builds the search index if the service supports it.
+	Index bool
+}
+
+// errMaxBytes is returned when a search exceeded SearchOptions.MaxBytes.
+var errMaxBytes = errors.New("maximum of scanned log bytes exceeded")
+
+// Search returns the log lines of the pipelines of the repository matching
+// the query, grouped by pipeline, workflow and step. Pipelines are searched
+// from the latest to the oldest. If the search stops early, because MaxBytes
+// was exceeded or the deadline of the context expired, the partial results are
+// returned with the number of the pipeline to continue the search at.
+func Search(ctx context.Context, s store.Store, service Service, repo *model.Repo, opts SearchOptions) (*model.LogSearchResult, error) {
+	match, literal, err := searchMatcher(opts)
+	if err != nil {
+		return nil, err
+	}
+	indexStore, _ := service.(SearchIndexStore)
+	if !opts.Index {
+		indexStore = nil
+	}
+
+	filter := &model.PipelineFilter{}
+	if !opts.Since.IsZero() {
+		filter.After = opts.Since.Unix()
+	}
+	pipelines, err := s.GetPipelineList(repo, &model.ListOptions{All: true}, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	search := &logSearch{
+		ctx:        ctx,
+		store:      s,
+		service:    service,
+		indexStore: indexStore,
+		match:      match,
+		literal:    literal,
+		context:    opts.Context,
+		maxBytes:   opts.MaxBytes,
+		remaining:  opts.Limit,
+	}
+	result := &model.LogSearchResult{Pipelines: make([]*model.LogSearchPipeline, 0)}
+	searched := 0
+	for _, pipeline := range pipelines {
+		if opts.Start > 0 && pipeline.Number > opts.Start {
+			continue
+		}
+		if search.exceeded() {
+			result.Next = pipeline.Number
+			return result, nil
+		}
+
+		pipelineResult, err := search.pipeline(pipeline)
+		switch {
+		case errors.Is(err, context.DeadlineExceeded), errors.Is(err, errMaxBytes) && searched > 0:
+			// results of the interrupted pipeline are dropped, it is searched again
+			result.Next = pipeline.Number
+			return result, nil
+		case errors.Is(err, errMaxBytes):
+			// the first pipeline is not searched again, otherwise a pipeline
+			// with more logs than MaxBytes could never be searched past
+		case err != nil:
+			return nil, err
+		}
+		searched++
+		if pipelineResult != nil {
+			result.Pipelines = append(result.Pipelines, pipelineResult)
+		}
+		if search.remaining <= 0 {
+			break
+		}
+	}
+	return result, nil
+}
+
+// logSearch is the state of a search across pipelines.
+type logSearch struct {
+	ctx        context.Context
+	store      store.Store
+	service    Service
+	indexStore SearchIndexStore
+	match      func([]byte) bool
+	literal    string
+	context    int
+	maxBytes   int64
+
+	// remaining is the number of matches still to find
+	remaining int
+	// scanned is the number of log bytes read
+	scanned int64
+}
+
+func (s *logSearch) exceeded() bool {
+	return s.maxBytes > 0 && s.scanned >= s.maxBytes
+}
+
+// pipeline returns the matches in the logs of the pipeline, or nil if there
+// are none. If the search is interrupted, the matches found so far are
+// returned with the error.
+func (s *logSearch) pipeline(pipeline *model.Pipeline) (*model.LogSearchPipeline, error) {
+	workflows, err := s.store.WorkflowGetTree(pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var pipelineResult *model.LogSearchPipeline
+	for _, workflow := range workflows {
+		var workflowResult *model.LogSearchWorkflow
+		for _, step := range workflow.Children {
+			if s.remaining <= 0 {
+				return pipelineResult, nil
+			}
+
+			matches, err := s.step(step)
+			if len(matches) > 0 {
+				s.remaining -= len(matches)
+
+				if pipelineResult == nil {
+					pipelineResult = &model.LogSearchPipeline{
+						ID:      pipeline.ID,
+						Number:  pipeline.Number,
+						Created: pipeline.Created,
+						Status:  pipeline.Status,
+						Event:   pipeline.Event,
+						Branch:  pipeline.Branch,
+					}
+				}
+				if workflowResult == nil {
+					workflowResult = &model.LogSearchWorkflow{ID: workflow.ID, Name: workflow.Name}
+					pipelineResult.Workflows = append(pipelineResult.Workflows, workflowResult)
+				}
+				workflowResult.Steps = append(workflowResult.Steps, &model.LogSearchStep{
+					ID:      step.ID,
+					Name:    step.Name,
+					Matches: matches,
+				})
+			}
+			if err != nil {
+				return pipelineResult, err
+			}
+		}
+	}
+	return pipelineResult, nil
+}
+
+// step returns the matches in the logs of the step. The logs are read in
+// pages, skipped if the index shows that they don't contain the literal and
+// the index of finished steps is built if it does not exist yet.
+func (s *logSearch) step(step *model.Step) ([]*model.LogSearchMatch, error) {
+	var index searchIndexBuilder
+	if s.indexStore != nil && !step.Running() {
+		existing, err := s.indexStore.LogSearchIndexFind(step)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil && !searchIndex(existing).mayContain(s.literal) {
+			return nil, nil
+		}
+		if existing == nil {
+			index = make(searchIndexBuilder)
+		}
+	}
+
+	matcher := &stepMatcher{match: s.match, context: s.context, limit: s.remaining}
+	err := FindPages(s.service, step, func(entries []*model.LogEntry) (bool, error) {
+		if err := s.ctx.Err(); err != nil {
+			return false, err
+		}
+		for _, entry := range entries {
+			s.scanned += int64(len(entry.Data))
+			if entry.Type == model.LogEntryStdout || entry.Type == model.LogEntryStderr {
+				matcher.add(entry)
+			}
+		}
+		if index != nil {
+			index.add(entries)
+		}
+		if s.exceeded() {
+			return false, errMaxBytes
+		}
+		// the index needs all logs
+		return index != nil || !matcher.done(), nil
+	})
+	if err != nil {
+		return matcher.matches, err
+	}
+	if index != nil {
+		if err := s.indexStore.LogSearchIndexSave(step, index.build()); err != nil {
+			logger.Error().Err(err).Msgf("could not save search index of step %d", step.ID)
+		}
+	}
+	return matcher.matches, nil
+}
+
+// searchMatcher returns the function matching log lines and the text every
+// matching log must contain.
+func searchMatcher(opts SearchOptions) (func([]byte) bool, string, error) {
+	if !opts.Regex {
+		query := []byte(opts.Query)
+		return func(line []byte) bool { return bytes.Contains(line, query) }, opts.Query, nil
+	}
+
+	re, err := regexp.Compile(opts.Query)
+	if err != nil {
+		return nil, "", err
+	}
+	literal, _ := re.LiteralPrefix()
+	return re.Match, literal, nil
+}
+
+// stepMatcher collects up to limit matching lines with the given number of
+// lines before and after them from the lines of a step.
+type stepMatcher struct {
+	match   func([]byte) bool
+	context int
+	limit   int
+
+	matches []*model.LogSearchMatch
+	// before are the last lines, pending the matches still missing lines after
+	before  []string
+	pending []*model.LogSearchMatch
+}
+
+func (m *stepMatcher) add(line *model.LogEntry) {
+	text := lineText(line)
+	pending := m.pending[:0]
+	for _, match := range m.pending {
+		match.After = append(match.After, text)
+		if len(match.After) < m.context {
+			pending = append(pending, match)
+		}
+	}
+	m.pending = pending
+
+	if len(m.matches) < m.limit && m.match(line.Data) {
+		match := &model.LogSearchMatch{Line: line.Line, Text: text}
+		if len(m.before) > 0 {
+			match.Before = append([]string(nil), m.before...)
+		}
+		m.matches = append(m.matches, match)
+		if m.context > 0 {
+			m.pending = append(m.pending, match)
+		}
+	}
+
+	if m.context > 0 {
+		m.before = append(m.before, text)
+		if len(m.before) > m.context {
+			m.before = m.before[1:]
+		}
+	}
+}
+
+// done returns true if no more lines are needed.
+func (m *stepMatcher) done() bool {
+	return len(m.matches) >= m.limit && len(m.pending) == 0
+}
+
+func lineText(entry *model.LogEntry) string {
+	return strings.TrimRight(string(entry.Data), "\r\n")
+}
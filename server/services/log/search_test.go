@@ -0,0 +1,219 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package log
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/store/mocks"
+)
+
+type indexedService struct {
+	memoryService
+	indexes map[int64][]byte
+	finds   int
+}
+
+func (m *indexedService) LogFind(step *model.Step) ([]*model.LogEntry, error) {
+	m.finds++
+	return m.memoryService.LogFind(step)
+}
+
+func (m *indexedService) LogFindRange(step *model.Step, r Range) ([]*model.LogEntry, error) {
+	m.finds++
+	entries, err := m.memoryService.LogFind(step)
+	return r.Apply(entries), err
+}
+
+func (m *indexedService) LogSearchIndexFind(step *model.Step) ([]byte, error) {
+	return m.indexes[step.ID], nil
+}
+
+func (m *indexedService) LogSearchIndexSave(step *model.Step, index []byte) error {
+	m.indexes[step.ID] = index
+	return nil
+}
+
+func logLines(stepID int64, lines ...string) []*model.LogEntry {
+	entries := make([]*model.LogEntry, len(lines))
+	for i, line := range lines {
+		entries[i] = &model.LogEntry{StepID: stepID, Line: i, Data: []byte(line)}
+	}
+	return entries
+}
+
+func TestSearch(t *testing.T) {
+	repo := &model.Repo{ID: 1}
+	pipelines := []*model.Pipeline{{ID: 2, Number: 2}, {ID: 1, Number: 1}}
+	s := mocks.NewStore(t)
+	s.On("GetPipelineList", repo, mock.Anything, mock.Anything).Return(pipelines, nil)
+	s.On("WorkflowGetTree", pipelines[0]).Return([]*model.Workflow{
+		{ID: 1, Name: "build", Children: []*model.Step{{ID: 1, Name: "compile", State: model.StatusSuccess}}},
+		{ID: 2, Name: "test", Children: []*model.Step{
+			{ID: 2, Name: "unit", State: model.StatusFailure},
+			{ID: 3, Name: "e2e", State: model.StatusRunning},
+		}},
+	}, nil)
+	s.On("WorkflowGetTree", pipelines[1]).Return([]*model.Workflow{
+		{ID: 3, Name: "test", Children: []*model.Step{{ID: 4, Name: "unit", State: model.StatusSuccess}}},
+	}, nil)
+
+	service := &indexedService{
+		memoryService: memoryService{logs: map[int64][]*model.LogEntry{
+			1: logLines(1, "go build", "ok"),
+			2: logLines(2, "=== RUN TestA", "--- FAIL: TestA", "panic: connection refused", "FAIL"),
+			3: logLines(3, "connection refused"),
+			4: logLines(4, "=== RUN TestA", "--- PASS: TestA"),
+		}},
+		indexes: make(map[int64][]byte),
+	}
+
+	results, err := Search(context.Background(), s, service, repo, SearchOptions{Query: "connection refused", Context: 1, Limit: 10, Index: true})
+	assert.NoError(t, err)
+	assert.Zero(t, results.Next)
+	assert.Equal(t, []*model.LogSearchPipeline{{
+		ID:     2,
+		Number: 2,
+		Workflows: []*model.LogSearchWorkflow{{ID: 2, Name: "test", Steps: []*model.LogSearchStep{
+			{ID: 2, Name: "unit", Matches: []*model.LogSearchMatch{
+				{Line: 2, Text: "panic: connection refused", Before: []string{"--- FAIL: TestA"}, After: []string{"FAIL"}},
+			}},
+			{ID: 3, Name: "e2e", Matches: []*model.LogSearchMatch{{Line: 0, Text: "connection refused"}}},
+		}}},
+	}}, results.Pipelines)
+	assert.Equal(t, 4, service.finds)
+	// running steps are not indexed
+	assert.Len(t, service.indexes, 3)
+
+	// the index skips steps without the text
+	results, err = Search(context.Background(), s, service, repo, SearchOptions{Query: `FAIL: Test\w+`, Regex: true, Limit: 10, Index: true})
+	assert.NoError(t, err)
+	if assert.Len(t, results.Pipelines, 1) {
+		assert.Equal(t, "--- FAIL: TestA", results.Pipelines[0].Workflows[0].Steps[0].Matches[0].Text)
+	}
+	assert.Equal(t, 6, service.finds)
+
+	results, err = Search(context.Background(), s, service, repo, SearchOptions{Query: "TestA", Limit: 3})
+	assert.NoError(t, err)
+	if assert.Len(t, results.Pipelines, 2) {
+		assert.Len(t, results.Pipelines[0].Workflows[0].Steps[0].Matches, 2)
+		assert.Len(t, results.Pipelines[1].Workflows[0].Steps[0].Matches, 1)
+	}
+
+	// the search stops once the scanned bytes are exceeded, the partial
+	// results of the first pipeline are returned
+	results, err = Search(context.Background(), s, service, repo, SearchOptions{Query: "ok", Limit: 10, MaxBytes: 1})
+	assert.NoError(t, err)
+	if assert.Len(t, results.Pipelines, 1) {
+		assert.Len(t, results.Pipelines[0].Workflows, 1)
+	}
+	assert.EqualValues(t, 1, results.Next)
+
+	// later pipelines are searched again
+	results, err = Search(context.Background(), s, service, repo, SearchOptions{Query: "TestA", Limit: 10, MaxBytes: 86})
+	assert.NoError(t, err)
+	assert.Len(t, results.Pipelines, 1)
+	assert.EqualValues(t, 1, results.Next)
+
+	// and continues at the returned pipeline
+	results, err = Search(context.Background(), s, service, repo, SearchOptions{Query: "TestA", Limit: 10, MaxBytes: 1, Start: results.Next})
+	assert.NoError(t, err)
+	if assert.Len(t, results.Pipelines, 1) {
+		assert.EqualValues(t, 1, results.Pipelines[0].Number)
+	}
+	assert.Zero(t, results.Next)
+
+	// an expired deadline returns the results so far
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	results, err = Search(ctx, s, service, repo, SearchOptions{Query: "TestA", Limit: 10})
+	assert.NoError(t, err)
+	assert.Empty(t, results.Pipelines)
+	assert.EqualValues(t, 2, results.Next)
+
+	_, err = Search(context.Background(), s, service, repo, SearchOptions{Query: "(", Regex: true})
+	assert.Error(t, err)
+}
+
+func TestSearchPages(t *testing.T) {
+	repo := &model.Repo{ID: 1}
+	pipeline := &model.Pipeline{ID: 1, Number: 1}
+	s := mocks.NewStore(t)
+	s.On("GetPipelineList", repo, mock.Anything, mock.Anything).Return([]*model.Pipeline{pipeline}, nil)
+	s.On("WorkflowGetTree", pipeline).Return([]*model.Workflow{
+		{ID: 1, Name: "build", Children: []*model.Step{{ID: 1, Name: "compile", State: model.StatusSuccess}}},
+	}, nil)
+
+	var lines []string
+	for i := 0; i < 2500; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	service := &indexedService{
+		memoryService: memoryService{logs: map[int64][]*model.LogEntry{1: logLines(1, lines...)}},
+		indexes:       make(map[int64][]byte),
+	}
+
+	// the context of a match is continued on the next page
+	results, err := Search(context.Background(), s, service, repo, SearchOptions{Query: "line 999", Context: 1, Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []*model.LogSearchMatch{{Line: 999, Text: "line 999", Before: []string{"line 998"}, After: []string{"line 1000"}}},
+		results.Pipelines[0].Workflows[0].Steps[0].Matches)
+	assert.Equal(t, 3, service.finds)
+
+	// the search stops reading once enough matches were found
+	service.finds = 0
+	results, err = Search(context.Background(), s, service, repo, SearchOptions{Query: "line", Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, results.Pipelines[0].Workflows[0].Steps[0].Matches, 1)
+	assert.Equal(t, 1, service.finds)
+
+	// or the scanned bytes were exceeded
+	service.finds = 0
+	results, err = Search(context.Background(), s, service, repo, SearchOptions{Query: "line 2", Limit: 1000, MaxBytes: 1})
+	assert.NoError(t, err)
+	assert.Len(t, results.Pipelines[0].Workflows[0].Steps[0].Matches, 111)
+	assert.Equal(t, 1, service.finds)
+	assert.Empty(t, service.indexes)
+
+	// the index is built from all pages
+	service.finds = 0
+	_, err = Search(context.Background(), s, service, repo, SearchOptions{Query: "line", Limit: 1, Index: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, service.finds)
+	assert.True(t, searchIndex(service.indexes[1]).mayContain("line 2499"))
+}
+
+func TestSearchIndex(t *testing.T) {
+	var entries []*model.LogEntry
+	for i := 0; i < 1000; i++ {
+		entries = append(entries, &model.LogEntry{Data: []byte(fmt.Sprintf("step %d finished", i))})
+	}
+	index := newSearchIndex(entries)
+
+	assert.True(t, index.mayContain("step 999 finished"))
+	assert.True(t, index.mayContain("ab"))
+	assert.False(t, index.mayContain("connection refused"))
+	assert.True(t, searchIndex(nil).mayContain("connection refused"))
+}
@@ -7,3 +7,109 @@ type Service interface {
 	LogAppend(step *model.Step, logEntries []*model.LogEntry) error
 	LogDelete(step *model.Step) error
 }
+
+// StepLister is implemented by services which can list the steps they keep
+// logs for, so that the logs of deleted steps can be removed.
+type StepLister interface {
+	// LogStepIDs returns the ids of the steps with logs in ascending order.
+	LogStepIDs() ([]int64, error)
+}
+
+// RangeService is implemented by services which can read a part of the logs
+// of a step without loading all of them.
+type RangeService interface {
+	LogFindRange(step *model.Step, r Range) ([]*model.LogEntry, error)
+}
+
+// CompactService is implemented by services which can compact the logs of
+// finished steps.
+type CompactService interface {
+	LogCompact(step *model.Step) error
+}
+
+// Range selects a part of the log entries of a step.
+type Range struct {
+	// FromLine is the first line to return.
+	FromLine int
+	// ToLine is the last line to return, 0 returns all following lines.
+	ToLine int
+	// Offset is the number of entries in the line range to skip.
+	Offset int
+	// Limit is the maximum number of entries to return, 0 returns all.
+	Limit int
+}
+
+// IsZero returns true if the range selects all log entries.
+func (r Range) IsZero() bool {
+	return r == Range{}
+}
+
+// Contains returns true if the line is part of the line range.
+func (r Range) Contains(line int) bool {
+	return line >= r.FromLine && (r.ToLine == 0 || line <= r.ToLine)
+}
+
+// Apply returns the entries selected by the range.
+func (r Range) Apply(entries []*model.LogEntry) []*model.LogEntry {
+	selected := make([]*model.LogEntry, 0, len(entries))
+	skipped := 0
+	for _, entry := range entries {
+		if r.Limit > 0 && len(selected) == r.Limit {
+			break
+		}
+		if !r.Contains(entry.Line) {
+			continue
+		}
+		if skipped < r.Offset {
+			skipped++
+			continue
+		}
+		selected = append(selected, entry)
+	}
+	return selected
+}
+
+// FindRange returns a part of the logs of the step, reading only the selected
+// part if the service supports it.
+func FindRange(s Service, step *model.Step, r Range) ([]*model.LogEntry, error) {
+	if rs, ok := s.(RangeService); ok {
+		return rs.LogFindRange(step, r)
+	}
+
+	entries, err := s.LogFind(step)
+	if err != nil || r.IsZero() {
+		return entries, err
+	}
+	return r.Apply(entries), nil
+}
+
+// pageEntries is the number of log entries read at once by FindPages.
+const pageEntries = 1000
+
+// FindPages calls fn with the logs of the step in pages, until fn returns
+// false. Services which can't read a part of the logs return them in one page.
+func FindPages(s Service, step *model.Step, fn func([]*model.LogEntry) (bool, error)) error {
+	rs, ok := s.(RangeService)
+	if !ok {
+		entries, err := s.LogFind(step)
+		if err != nil {
+			return err
+		}
+		_, err = fn(entries)
+		return err
+	}
+
+	for offset := 0; ; offset += pageEntries {
+		entries, err := rs.LogFindRange(step, Range{Offset: offset, Limit: pageEntries})
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		more, err := fn(entries)
+		if err != nil || !more || len(entries) < pageEntries {
+			return err
+		}
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"go.woodpecker-ci.org/woodpecker/v2/server/forge"
 	"go.woodpecker-ci.org/woodpecker/v2/server/model"
 	"go.woodpecker-ci.org/woodpecker/v2/server/services/config"
+	"go.woodpecker-ci.org/woodpecker/v2/server/services/encryption"
 	"go.woodpecker-ci.org/woodpecker/v2/server/services/environment"
 	"go.woodpecker-ci.org/woodpecker/v2/server/services/registry"
 	"go.woodpecker-ci.org/woodpecker/v2/server/services/secret"
@@ -61,7 +62,9 @@ type manager struct {
 	setupForge          SetupForge
 }
 
-func NewManager(c *cli.Command, store store.Store, setupForge SetupForge) (Manager, error) {
+// NewManager returns the manager of the services. The secrets and registries
+// are encrypted with encSvc unless it is nil.
+func NewManager(c *cli.Command, store store.Store, encSvc encryption.Service, setupForge SetupForge) (Manager, error) {
 	signaturePrivateKey, signaturePublicKey, err := setupSignatureKeys(store)
 	if err != nil {
 		return nil, err
@@ -77,7 +80,12 @@ func NewManager(c *cli.Command, store store.Store, setupForge SetupForge) (Manag
 		return nil, err
 	}
 
-	secretService, err := setupSecretService(c, store)
+	secretService, err := setupSecretService(c, store, encSvc, signaturePrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	registryService, err := setupRegistryService(c, store, encSvc)
 	if err != nil {
 		return nil, err
 	}
@@ -87,7 +95,7 @@ func NewManager(c *cli.Command, store store.Store, setupForge SetupForge) (Manag
 		signaturePublicKey:  signaturePublicKey,
 		store:               store,
 		secret:              secretService,
-		registry:            setupRegistryService(store, c.String("docker-config")),
+		registry:            registryService,
 		config:              configService,
 		environment:         environment.Parse(c.StringSlice("environment")),
 		forgeCache:          ttlcache.New(ttlcache.WithDisableTouchOnHit[int64, forge.Forge]()),
@@ -0,0 +1,351 @@
+// Copyright 2025 Woodpecker Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/services/encryption"
+	"go.woodpecker-ci.org/woodpecker/v2/server/store"
+)
+
+type encryptedRegistryService struct {
+	registrySvc   Service
+	encryptionSvc encryption.Service
+	locked        bool
+}
+
+// NewEncrypted returns a registry service which stores the registry passwords
+// encrypted and decrypts them transparently on read.
+func NewEncrypted(registryService Service, encryptionService encryption.Service) *encryptedRegistryService {
+	return &encryptedRegistryService{
+		registrySvc:   registryService,
+		encryptionSvc: encryptionService,
+	}
+}
+
+func (ers *encryptedRegistryService) isLocked() bool {
+	return ers.locked
+}
+
+func (ers *encryptedRegistryService) lock() {
+	ers.locked = true
+}
+
+func (ers *encryptedRegistryService) unlock() {
+	ers.locked = false
+}
+
+func (ers *encryptedRegistryService) encryptRegistry(registry *model.Registry) error {
+	if err := encryption.CheckAlgo(ers.encryptionSvc, registry.Password); err != nil {
+		return fmt.Errorf("failed to encrypt registry id=%d: %w", registry.ID, err)
+	}
+	if ers.isEncoded(registry.Password) {
+		return nil
+	}
+	log.Debug().Int64("id", registry.ID).Str("address", registry.Address).Msg("encryption")
+
+	encryptedValue, err := ers.encryptionSvc.Encrypt(registry.Password, registry.Address)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt registry id=%d: %w", registry.ID, err)
+	}
+
+	registry.Password = ers.header() + encryptedValue
+	return nil
+}
+
+func (ers *encryptedRegistryService) decryptList(registries []*model.Registry) error {
+	for _, registry := range registries {
+		err := ers.decryptRegistry(registry)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ers *encryptedRegistryService) decryptRegistry(registry *model.Registry) error {
+	if err := encryption.CheckAlgo(ers.encryptionSvc, registry.Password); err != nil {
+		return fmt.Errorf("failed to decrypt registry id=%d: %w", registry.ID, err)
+	}
+	if !ers.isEncoded(registry.Password) {
+		return nil
+	}
+	log.Debug().Int64("id", registry.ID).Str("address", registry.Address).Msg("decryption")
+
+	decodedValue := strings.TrimPrefix(registry.Password, ers.header())
+	decryptedValue, err := ers.encryptionSvc.Decrypt(decodedValue, registry.Address)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt registry id=%d: %w", registry.ID, err)
+	}
+
+	registry.Password = decryptedValue
+	return nil
+}
+
+func (ers *encryptedRegistryService) isEncoded(value string) bool {
+	return encryption.IsEncrypted(ers.encryptionSvc.Algo(), value)
+}
+
+func (ers *encryptedRegistryService) header() string {
+	return encryption.Header(ers.encryptionSvc.Algo())
+}
+
+func (ers *encryptedRegistryService) find(registry *model.Registry, err error) (*model.Registry, error) {
+	if err != nil {
+		return nil, err
+	}
+	if err := ers.decryptRegistry(registry); err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
+func (ers *encryptedRegistryService) list(registries []*model.Registry, err error) ([]*model.Registry, error) {
+	if err != nil {
+		return nil, err
+	}
+	if err := ers.decryptList(registries); err != nil {
+		return nil, err
+	}
+	return registries, nil
+}
+
+// Service (server/services/registry/service.go) interface implementation
+
+func (ers *encryptedRegistryService) RegistryListPipeline(repo *model.Repo, pipeline *model.Pipeline) ([]*model.Registry, error) {
+	if ers.isLocked() {
+		return nil, newServiceLockedError()
+	}
+
+	return ers.list(ers.registrySvc.RegistryListPipeline(repo, pipeline))
+}
+
+func (ers *encryptedRegistryService) RegistryFind(repo *model.Repo, addr string) (*model.Registry, error) {
+	if ers.isLocked() {
+		return nil, newServiceLockedError()
+	}
+
+	return ers.find(ers.registrySvc.RegistryFind(repo, addr))
+}
+
+func (ers *encryptedRegistryService) RegistryList(repo *model.Repo, listOpt *model.ListOptions) ([]*model.Registry, error) {
+	if ers.isLocked() {
+		return nil, newServiceLockedError()
+	}
+
+	return ers.list(ers.registrySvc.RegistryList(repo, listOpt))
+}
+
+func (ers *encryptedRegistryService) RegistryCreate(repo *model.Repo, in *model.Registry) error {
+	if ers.isLocked() {
+		return newServiceLockedError()
+	}
+
+	if err := ers.encryptRegistry(in); err != nil {
+		return err
+	}
+	return ers.registrySvc.RegistryCreate(repo, in)
+}
+
+func (ers *encryptedRegistryService) RegistryUpdate(repo *model.Repo, in *model.Registry) error {
+	if ers.isLocked() {
+		return newServiceLockedError()
+	}
+
+	if err := ers.encryptRegistry(in); err != nil {
+		return err
+	}
+	return ers.registrySvc.RegistryUpdate(repo, in)
+}
+
+func (ers *encryptedRegistryService) RegistryDelete(repo *model.Repo, addr string) error {
+	if ers.isLocked() {
+		return newServiceLockedError()
+	}
+
+	return ers.registrySvc.RegistryDelete(repo, addr)
+}
+
+func (ers *encryptedRegistryService) OrgRegistryFind(owner int64, addr string) (*model.Registry, error) {
+	if ers.isLocked() {
+		return nil, newServiceLockedError()
+	}
+
+	return ers.find(ers.registrySvc.OrgRegistryFind(owner, addr))
+}
+
+func (ers *encryptedRegistryService) OrgRegistryList(owner int64, listOpt *model.ListOptions) ([]*model.Registry, error) {
+	if ers.isLocked() {
+		return nil, newServiceLockedError()
+	}
+
+	return ers.list(ers.registrySvc.OrgRegistryList(owner, listOpt))
+}
+
+func (ers *encryptedRegistryService) OrgRegistryCreate(owner int64, in *model.Registry) error {
+	if ers.isLocked() {
+		return newServiceLockedError()
+	}
+
+	if err := ers.encryptRegistry(in); err != nil {
+		return err
+	}
+	return ers.registrySvc.OrgRegistryCreate(owner, in)
+}
+
+func (ers *encryptedRegistryService) OrgRegistryUpdate(owner int64, in *model.Registry) error {
+	if ers.isLocked() {
+		return newServiceLockedError()
+	}
+
+	if err := ers.encryptRegistry(in); err != nil {
+		return err
+	}
+	return ers.registrySvc.OrgRegistryUpdate(owner, in)
+}
+
+func (ers *encryptedRegistryService) OrgRegistryDelete(owner int64, addr string) error {
+	if ers.isLocked() {
+		return newServiceLockedError()
+	}
+
+	return ers.registrySvc.OrgRegistryDelete(owner, addr)
+}
+
+func (ers *encryptedRegistryService) GlobalRegistryFind(addr string) (*model.Registry, error) {
+	if ers.isLocked() {
+		return nil, newServiceLockedError()
+	}
+
+	return ers.find(ers.registrySvc.GlobalRegistryFind(addr))
+}
+
+func (ers *encryptedRegistryService) GlobalRegistryList(listOpt *model.ListOptions) ([]*model.Registry, error) {
+	if ers.isLocked() {
+		return nil, newServiceLockedError()
+	}
+
+	return ers.list(ers.registrySvc.GlobalRegistryList(listOpt))
+}
+
+func (ers *encryptedRegistryService) GlobalRegistryCreate(in *model.Registry) error {
+	if ers.isLocked() {
+		return newServiceLockedError()
+	}
+
+	if err := ers.encryptRegistry(in); err != nil {
+		return err
+	}
+	return ers.registrySvc.GlobalRegistryCreate(in)
+}
+
+func (ers *encryptedRegistryService) GlobalRegistryUpdate(in *model.Registry) error {
+	if ers.isLocked() {
+		return newServiceLockedError()
+	}
+
+	if err := ers.encryptRegistry(in); err != nil {
+		return err
+	}
+	return ers.registrySvc.GlobalRegistryUpdate(in)
+}
+
+func (ers *encryptedRegistryService) GlobalRegistryDelete(addr string) error {
+	if ers.isLocked() {
+		return newServiceLockedError()
+	}
+
+	return ers.registrySvc.GlobalRegistryDelete(addr)
+}
+
+func newServiceLockedError() error {
+	return fmt.Errorf("service is locked")
+}
+
+type EncryptedRegistryMigrationAgent struct {
+	ers   *encryptedRegistryService
+	store store.Store
+}
+
+func NewMigration(ers *encryptedRegistryService, store store.Store) *EncryptedRegistryMigrationAgent {
+	ers.lock()
+
+	return &EncryptedRegistryMigrationAgent{
+		ers:   ers,
+		store: store,
+	}
+}
+
+// EncryptAll encrypts the passwords of all registries stored in plain text
+// and unlocks the service afterwards.
+func (erma *EncryptedRegistryMigrationAgent) EncryptAll() error {
+	log.Info().Msg("encrypting all registry passwords")
+
+	registries, err := erma.store.RegistryListAll()
+	if err != nil {
+		return newAllEncryptionError(err)
+	}
+
+	for _, registry := range registries {
+		if erma.ers.isEncoded(registry.Password) {
+			continue
+		}
+		if err := erma.ers.encryptRegistry(registry); err != nil {
+			return newAllEncryptionError(err)
+		}
+		if err := erma.store.RegistryUpdate(registry); err != nil {
+			return newAllEncryptionError(err)
+		}
+	}
+
+	log.Info().Msg("all registry passwords are encrypted")
+	erma.ers.unlock()
+	return nil
+}
+
+// ReencryptAll encrypts all registry passwords with the current encryption
+// key. It returns the number of re-encrypted registries.
+func (erma *EncryptedRegistryMigrationAgent) ReencryptAll() (int, error) {
+	log.Info().Msg("re-encrypting all registry passwords")
+
+	registries, err := erma.store.RegistryListAll()
+	if err != nil {
+		return 0, newAllEncryptionError(err)
+	}
+
+	for i, registry := range registries {
+		if err := erma.ers.decryptRegistry(registry); err != nil {
+			return i, newAllEncryptionError(err)
+		}
+		if err := erma.ers.encryptRegistry(registry); err != nil {
+			return i, newAllEncryptionError(err)
+		}
+		if err := erma.store.RegistryUpdate(registry); err != nil {
+			return i, newAllEncryptionError(err)
+		}
+	}
+
+	log.Info().Msg("all registry passwords are re-encrypted")
+	return len(registries), nil
+}
+
+func newAllEncryptionError(e error) error {
+	return fmt.Errorf("cannot encrypt registries: %w", e)
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 Woodpecker Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/services/registry/mocks"
+	mocks_store "go.woodpecker-ci.org/woodpecker/v2/server/store/mocks"
+)
+
+type testEncSvc struct{}
+
+func (e *testEncSvc) Algo() string {
+	return "enc"
+}
+
+func (e *testEncSvc) Encrypt(plaintext, associatedData string) (string, error) {
+	return strings.Join([]string{associatedData, plaintext}, "-"), nil
+}
+
+func (e *testEncSvc) Decrypt(ciphertext, associatedData string) (string, error) {
+	return strings.TrimPrefix(ciphertext, associatedData+"-"), nil
+}
+
+func TestEncryptedRegistryFind(t *testing.T) {
+	registrySvc := mocks.NewService(t)
+	registrySvc.On("RegistryFind", mock.Anything, "docker.io").Once().
+		Return(&model.Registry{Address: "docker.io", Password: "enc_docker.io-pass"}, nil)
+	ers := NewEncrypted(registrySvc, &testEncSvc{})
+
+	registry, err := ers.RegistryFind(nil, "docker.io")
+	assert.NoError(t, err)
+	assert.Equal(t, "pass", registry.Password)
+}
+
+func TestEncryptedRegistryListPipeline(t *testing.T) {
+	registrySvc := mocks.NewService(t)
+	registrySvc.On("RegistryListPipeline", mock.Anything, mock.Anything).Once().
+		Return([]*model.Registry{
+			{Address: "docker.io", Password: "enc_docker.io-pass"},
+			{Address: "quay.io", Password: "plain"},
+		}, nil)
+	ers := NewEncrypted(registrySvc, &testEncSvc{})
+
+	registries, err := ers.RegistryListPipeline(nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "pass", registries[0].Password)
+	assert.Equal(t, "plain", registries[1].Password)
+}
+
+func TestEncryptedRegistryCreate(t *testing.T) {
+	registrySvc := mocks.NewService(t)
+	registrySvc.On("OrgRegistryCreate", int64(1), mock.Anything).Once().Return(nil).
+		Run(func(args mock.Arguments) {
+			registry := args.Get(1).(*model.Registry)
+			assert.Equal(t, "enc_docker.io-pass", registry.Password)
+		})
+	ers := NewEncrypted(registrySvc, &testEncSvc{})
+
+	err := ers.OrgRegistryCreate(1, &model.Registry{Address: "docker.io", Password: "pass"})
+	assert.NoError(t, err)
+}
+
+func TestRegistryMigrationEncrypt(t *testing.T) {
+	store := mocks_store.NewStore(t)
+	ers := NewEncrypted(mocks.NewService(t), &testEncSvc{})
+
+	store.On("RegistryListAll").Once().
+		Return([]*model.Registry{
+			{Address: "docker.io", Password: "pass"},
+			{Address: "quay.io", Password: "enc_quay.io-done"},
+		}, nil)
+	store.On("RegistryUpdate", mock.Anything).Once().Return(nil).
+		Run(func(args mock.Arguments) {
+			registry := args.Get(0).(*model.Registry)
+			assert.Equal(t, "enc_docker.io-pass", registry.Password)
+		})
+
+	migrationAgent := NewMigration(ers, store)
+	assert.True(t, ers.isLocked())
+	_, err := ers.GlobalRegistryList(&model.ListOptions{})
+	assert.Error(t, err)
+
+	assert.NoError(t, migrationAgent.EncryptAll())
+	assert.False(t, ers.isLocked())
+}
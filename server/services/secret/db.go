@@ -36,14 +36,18 @@ func (d *db) SecretList(repo *model.Repo, p *model.ListOptions) ([]*model.Secret
 	return d.store.SecretList(repo, false, p)
 }
 
-func (d *db) SecretListPipeline(repo *model.Repo, _ *model.Pipeline) ([]*model.Secret, error) {
+func (d *db) SecretListPipeline(repo *model.Repo, _ *model.Pipeline, _ []string) ([]*model.Secret, error) {
 	s, err := d.store.SecretList(repo, true, &model.ListOptions{All: true})
 	if err != nil {
 		return nil, err
 	}
+	return uniqueSecrets(s), nil
+}
 
-	// Return only secrets with unique name
-	// Priority order in case of duplicate names are repository, user/organization, global
+// uniqueSecrets returns only secrets with unique name.
+// Priority order in case of duplicate names are repository, user/organization, global,
+// secrets of the same level keep their order.
+func uniqueSecrets(s []*model.Secret) []*model.Secret {
 	secrets := make([]*model.Secret, 0, len(s))
 	uniq := make(map[string]struct{})
 	for _, condition := range []struct {
@@ -66,7 +70,7 @@ func (d *db) SecretListPipeline(repo *model.Repo, _ *model.Pipeline) ([]*model.S
 			secrets = append(secrets, secret)
 		}
 	}
-	return secrets, nil
+	return secrets
 }
 
 func (d *db) SecretCreate(_ *model.Repo, in *model.Secret) error {
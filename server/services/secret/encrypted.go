@@ -23,10 +23,6 @@ import (
 	"go.woodpecker-ci.org/woodpecker/v2/server/services/encryption"
 )
 
-const (
-	secretValueTemplate = "%s_"
-)
-
 type encryptedSecretService struct {
 	secretSvc     Service
 	encryptionSvc encryption.Service
@@ -53,6 +49,9 @@ func (ess *encryptedSecretService) unlock() {
 }
 
 func (ess *encryptedSecretService) encryptSecret(secret *model.Secret) error {
+	if err := encryption.CheckAlgo(ess.encryptionSvc, secret.Value); err != nil {
+		return fmt.Errorf("failed to encrypt secret id=%d: %w", secret.ID, err)
+	}
 	if ess.isEncoded(secret.Value) {
 		return nil
 	}
@@ -83,6 +82,9 @@ func (ess *encryptedSecretService) decryptList(secrets []*model.Secret) error {
 }
 
 func (ess *encryptedSecretService) decryptSecret(secret *model.Secret) error {
+	if err := encryption.CheckAlgo(ess.encryptionSvc, secret.Value); err != nil {
+		return fmt.Errorf("failed to decrypt secret id=%d: %w", secret.ID, err)
+	}
 	if !ess.isEncoded(secret.Value) {
 		return nil
 	}
@@ -103,11 +105,11 @@ func (ess *encryptedSecretService) decodeSecretValue(value string) string {
 }
 
 func (ess *encryptedSecretService) isEncoded(value string) bool {
-	return strings.HasPrefix(value, ess.header())
+	return encryption.IsEncrypted(ess.encryptionSvc.Algo(), value)
 }
 
 func (ess *encryptedSecretService) header() string {
-	return fmt.Sprintf(secretValueTemplate, ess.encryptionSvc.Algo())
+	return encryption.Header(ess.encryptionSvc.Algo())
 }
 
 // Service (server/services/secret/service.go) interface implementation
@@ -144,12 +146,12 @@ func (ess *encryptedSecretService) SecretList(repo *model.Repo, listOpt *model.L
 	return secrets, nil
 }
 
-func (ess *encryptedSecretService) SecretListPipeline(repo *model.Repo, pipeline *model.Pipeline) ([]*model.Secret, error) {
+func (ess *encryptedSecretService) SecretListPipeline(repo *model.Repo, pipeline *model.Pipeline, names []string) ([]*model.Secret, error) {
 	if ess.isLocked() {
 		return nil, newServiceLockedError()
 	}
 
-	secrets, err := ess.secretSvc.SecretListPipeline(repo, pipeline)
+	secrets, err := ess.secretSvc.SecretListPipeline(repo, pipeline, names)
 	if err != nil {
 		return nil, err
 	}
@@ -406,6 +408,33 @@ func (esma *EncryptedSecretMigrationAgent) DecryptAll() error {
 	return nil
 }
 
+// ReencryptAll encrypts all secrets with the current encryption key, so that
+// old keys can be removed after a key rotation. It returns the number of
+// re-encrypted secrets.
+func (esma *EncryptedSecretMigrationAgent) ReencryptAll() (int, error) {
+	log.Info().Msg("re-encrypting all secrets")
+
+	secrets, err := esma.store.SecretListAll()
+	if err != nil {
+		return 0, newAllEncryptionError(err)
+	}
+
+	for i, secret := range secrets {
+		if err := esma.ess.decryptSecret(secret); err != nil {
+			return i, newAllEncryptionError(err)
+		}
+		if err := esma.ess.encryptSecret(secret); err != nil {
+			return i, newAllEncryptionError(err)
+		}
+		if err := esma.store.SecretUpdate(secret); err != nil {
+			return i, newAllEncryptionError(err)
+		}
+	}
+
+	log.Info().Msg("all secrets are re-encrypted, old keys can be removed")
+	return len(secrets), nil
+}
+
 func newAllEncryptionError(e error) error {
 	return fmt.Errorf("cannot encrypt secrets: %w", e)
 }
@@ -56,6 +56,27 @@ func TestSecretFind(t *testing.T) {
 	assert.Equal(t, "awsaccesskeyexample", secret.Value)
 }
 
+func TestSecretFindOtherAlgo(t *testing.T) {
+	secretSvc := mocks.NewService(t)
+	secretSvc.On("SecretFind", mock.Anything, mock.Anything).Once().
+		Return(&model.Secret{Value: "aes_0123abcd:" + strings.Repeat("A", 40)}, nil)
+	ess := NewEncrypted(secretSvc, newTestEncSvc())
+
+	_, err := ess.SecretFind(nil, "sec")
+	assert.ErrorContains(t, err, "value is encrypted with aes, but enc is configured")
+}
+
+func TestSecretCreatePrefixedPlainText(t *testing.T) {
+	secretSvc := mocks.NewService(t)
+	secretSvc.On("SecretCreate", mock.Anything, mock.Anything).Once().Return(nil)
+	ess := NewEncrypted(secretSvc, newTestEncSvc())
+
+	// a plain text value which only starts like an aes ciphertext is encrypted
+	secret := &model.Secret{Name: "sec", Value: "aes_password"}
+	assert.NoError(t, ess.SecretCreate(&model.Repo{}, secret))
+	assert.Equal(t, "enc_encrypted-aes_password-encrypted", secret.Value)
+}
+
 func TestSecretCreate(t *testing.T) {
 	secretSvc := mocks.NewService(t)
 	secretSvc.On("SecretCreate", mock.Anything, mock.Anything).Once().Return(nil).
@@ -115,3 +136,38 @@ func TestMigrationDecrypt(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, ess.isLocked())
 }
+
+func TestMigrationReencrypt(t *testing.T) {
+	oldSecret := &model.Secret{Name: "sec", Value: "supersec"}
+	assert.NoError(t, NewEncrypted(nil, mustAes(t, "old key")).encryptSecret(oldSecret))
+
+	encSvc, err := encryption.NewAes("new key", "old key")
+	assert.NoError(t, err)
+	ess := NewEncrypted(mocks.NewService(t), encSvc)
+
+	store := mocks_store.NewStore(t)
+	store.On("SecretListAll").Once().
+		Return([]*model.Secret{oldSecret, {Name: "plain", Value: "plainsec"}}, nil)
+	var updated []*model.Secret
+	store.On("SecretUpdate", mock.Anything).Twice().Return(nil).
+		Run(func(args mock.Arguments) {
+			updated = append(updated, args.Get(0).(*model.Secret))
+		})
+
+	n, err := NewMigration(ess, store).ReencryptAll()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	newOnly := NewEncrypted(nil, mustAes(t, "new key"))
+	for i, value := range []string{"supersec", "plainsec"} {
+		assert.NotEqual(t, value, updated[i].Value)
+		assert.NoError(t, newOnly.decryptSecret(updated[i]))
+		assert.Equal(t, value, updated[i].Value)
+	}
+}
+
+func mustAes(t *testing.T, key string) encryption.Service {
+	encSvc, err := encryption.NewAes(key)
+	assert.NoError(t, err)
+	return encSvc
+}
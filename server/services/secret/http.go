@@ -0,0 +1,127 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package secret
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	net_http "net/http"
+	"time"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/services/utils"
+)
+
+const httpTimeout = 30 * time.Second
+
+// Secret levels an extension can return secrets for, they follow the same
+// precedence as the secrets stored in the database.
+const (
+	levelRepo   = "repo"
+	levelOrg    = "org"
+	levelGlobal = "global"
+)
+
+type http struct {
+	Service
+	endpoint   string
+	privateKey crypto.PrivateKey
+}
+
+type secretData struct {
+	Name   string               `json:"name"`
+	Value  string               `json:"value"`
+	Images []string             `json:"images"`
+	Events []model.WebhookEvent `json:"events"`
+	// one of repo, org or global, defaults to repo
+	Level string `json:"level"`
+}
+
+type secretRequest struct {
+	Repo     *model.Repo     `json:"repo"`
+	Pipeline *model.Pipeline `json:"pipeline"`
+	Names    []string        `json:"names"`
+}
+
+type secretResponse struct {
+	Secrets []*secretData `json:"secrets"`
+}
+
+// NewHTTP returns a secret service resolving the secrets of pipelines by calling
+// the given extension endpoint, all other operations are passed to the given
+// service. Secrets returned by the extension take precedence over the ones of
+// the given service with the same name and level.
+func NewHTTP(endpoint string, privateKey crypto.PrivateKey, secretService Service) Service {
+	return &http{secretService, endpoint, privateKey}
+}
+
+func (h *http) SecretListPipeline(repo *model.Repo, pipeline *model.Pipeline, names []string) ([]*model.Secret, error) {
+	secrets, err := h.Service.SecretListPipeline(repo, pipeline, names)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), httpTimeout)
+	defer cancel()
+
+	response := new(secretResponse)
+	body := secretRequest{
+		Repo:     repo,
+		Pipeline: pipeline,
+		Names:    names,
+	}
+	status, err := utils.Send(ctx, net_http.MethodPost, h.endpoint, h.privateKey, body, response)
+	if err != nil && status != net_http.StatusNoContent {
+		return nil, fmt.Errorf("failed to fetch secrets via http (%d) %w", status, err)
+	}
+	if status != net_http.StatusOK {
+		return secrets, nil
+	}
+
+	external := make([]*model.Secret, 0, len(response.Secrets))
+	for _, data := range response.Secrets {
+		secret := &model.Secret{
+			Name:   data.Name,
+			Value:  data.Value,
+			Images: data.Images,
+			Events: data.Events,
+		}
+		switch data.Level {
+		case "", levelRepo:
+			secret.RepoID = repo.ID
+		case levelOrg:
+			secret.OrgID = repo.OrgID
+		case levelGlobal:
+		default:
+			return nil, fmt.Errorf("invalid level '%s' of secret '%s' returned via http", data.Level, data.Name)
+		}
+		if secret.Name == "" {
+			return nil, fmt.Errorf("secret without name returned via http")
+		}
+		// secrets without events are available for all events
+		for _, event := range secret.Events {
+			if err := event.Validate(); err != nil {
+				return nil, fmt.Errorf("invalid secret '%s' returned via http: %w", secret.Name, err)
+			}
+		}
+		external = append(external, secret)
+	}
+
+	return uniqueSecrets(append(external, secrets...)), nil
+}
@@ -0,0 +1,115 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package secret_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-ap/httpsig"
+	"github.com/stretchr/testify/assert"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/services/secret"
+	"go.woodpecker-ci.org/woodpecker/v2/server/services/secret/mocks"
+)
+
+func TestHTTPSecretListPipeline(t *testing.T) {
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	repo := &model.Repo{ID: 1, OrgID: 2, FullName: "octocat/hello-world"}
+	pipeline := &model.Pipeline{ID: 3, Number: 4}
+	names := []string{"deploy_key", "token"}
+
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keystore := httpsig.NewMemoryKeyStore()
+		keystore.SetKey("woodpecker-ci-plugins", pubKey)
+		verifier := httpsig.NewVerifier(keystore)
+		verifier.SetRequiredHeaders([]string{"(request-target)", "date"})
+		if _, err := verifier.Verify(r); !assert.NoError(t, err) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var body struct {
+			Repo     *model.Repo     `json:"repo"`
+			Pipeline *model.Pipeline `json:"pipeline"`
+			Names    []string        `json:"names"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, repo.FullName, body.Repo.FullName)
+		assert.Equal(t, pipeline.Number, body.Pipeline.Number)
+		assert.Equal(t, names, body.Names)
+
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			_, _ = w.Write([]byte(`{"secrets":[
+				{"name":"token","value":"vault-repo"},
+				{"name":"deploy_key","value":"vault-global","level":"global","events":["push"]}
+			]}`))
+		}
+	}))
+	defer server.Close()
+
+	dbSecrets := []*model.Secret{
+		{Name: "token", Value: "db-org", OrgID: 2},
+		{Name: "deploy_key", Value: "db-org", OrgID: 2},
+		{Name: "other", Value: "db-repo", RepoID: 1},
+	}
+	db := mocks.NewService(t)
+	db.On("SecretListPipeline", repo, pipeline, names).Return(dbSecrets, nil)
+	service := secret.NewHTTP(server.URL, privKey, db)
+
+	t.Run("merge", func(t *testing.T) {
+		secrets, err := service.SecretListPipeline(repo, pipeline, names)
+		assert.NoError(t, err)
+
+		values := make(map[string]string)
+		for _, s := range secrets {
+			values[s.Name] = s.Value
+		}
+		assert.Equal(t, map[string]string{
+			// the extension takes precedence on the same level
+			"token": "vault-repo",
+			// but not over a higher level
+			"deploy_key": "db-org",
+			"other":      "db-repo",
+		}, values)
+	})
+
+	t.Run("no content", func(t *testing.T) {
+		status = http.StatusNoContent
+		secrets, err := service.SecretListPipeline(repo, pipeline, names)
+		assert.NoError(t, err)
+		assert.Equal(t, dbSecrets, secrets)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		status = http.StatusInternalServerError
+		_, err := service.SecretListPipeline(repo, pipeline, names)
+		assert.Error(t, err)
+	})
+}
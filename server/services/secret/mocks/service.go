@@ -336,9 +336,9 @@ func (_m *Service) SecretList(_a0 *model.Repo, _a1 *model.ListOptions) ([]*model
 	return r0, r1
 }
 
-// SecretListPipeline provides a mock function with given fields: _a0, _a1
-func (_m *Service) SecretListPipeline(_a0 *model.Repo, _a1 *model.Pipeline) ([]*model.Secret, error) {
-	ret := _m.Called(_a0, _a1)
+// SecretListPipeline provides a mock function with given fields: repo, pipeline, names
+func (_m *Service) SecretListPipeline(repo *model.Repo, pipeline *model.Pipeline, names []string) ([]*model.Secret, error) {
+	ret := _m.Called(repo, pipeline, names)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SecretListPipeline")
@@ -346,19 +346,19 @@ func (_m *Service) SecretListPipeline(_a0 *model.Repo, _a1 *model.Pipeline) ([]*
 
 	var r0 []*model.Secret
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*model.Repo, *model.Pipeline) ([]*model.Secret, error)); ok {
-		return rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(*model.Repo, *model.Pipeline, []string) ([]*model.Secret, error)); ok {
+		return rf(repo, pipeline, names)
 	}
-	if rf, ok := ret.Get(0).(func(*model.Repo, *model.Pipeline) []*model.Secret); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(*model.Repo, *model.Pipeline, []string) []*model.Secret); ok {
+		r0 = rf(repo, pipeline, names)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*model.Secret)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*model.Repo, *model.Pipeline) error); ok {
-		r1 = rf(_a0, _a1)
+	if rf, ok := ret.Get(1).(func(*model.Repo, *model.Pipeline, []string) error); ok {
+		r1 = rf(repo, pipeline, names)
 	} else {
 		r1 = ret.Error(1)
 	}
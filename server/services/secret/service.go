@@ -20,7 +20,9 @@ import "go.woodpecker-ci.org/woodpecker/v2/server/model"
 
 // Service defines a service for managing secrets.
 type Service interface {
-	SecretListPipeline(*model.Repo, *model.Pipeline) ([]*model.Secret, error)
+	// SecretListPipeline returns the secrets available to the pipeline, names are
+	// the secrets requested by its configs.
+	SecretListPipeline(repo *model.Repo, pipeline *model.Pipeline, names []string) ([]*model.Secret, error)
 	// Repository secrets
 	SecretFind(*model.Repo, string) (*model.Secret, error)
 	SecretList(*model.Repo, *model.ListOptions) ([]*model.Secret, error)
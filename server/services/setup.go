@@ -35,34 +35,59 @@ import (
 	"go.woodpecker-ci.org/woodpecker/v2/server/store/types"
 )
 
-func setupRegistryService(store store.Store, dockerConfig string) registry.Service {
-	if dockerConfig != "" {
+func setupRegistryService(c *cli.Command, store store.Store, encSvc encryption.Service) (registry.Service, error) {
+	registrySvc, err := setupDBRegistryService(store, encSvc)
+	if err != nil {
+		return nil, err
+	}
+
+	if dockerConfig := c.String("docker-config"); dockerConfig != "" {
 		return registry.NewCombined(
-			registry.NewDB(store),
+			registrySvc,
 			registry.NewFilesystem(dockerConfig),
-		)
+		), nil
 	}
 
-	return registry.NewDB(store)
+	return registrySvc, nil
 }
 
-func setupSecretService(c *cli.Command, store store.Store) (secret.Service, error) {
-	secretSvc := secret.NewDB(store)
+func setupDBRegistryService(store store.Store, encSvc encryption.Service) (registry.Service, error) {
+	registrySvc := registry.NewDB(store)
+	if encSvc == nil {
+		return registrySvc, nil
+	}
 
-	aesKey := c.String("secrets-encryption-aes-key")
-	if aesKey == "" {
-		return secretSvc, nil
+	encryptedRegistryService := registry.NewEncrypted(registrySvc, encSvc)
+	err := registry.NewMigration(encryptedRegistryService, store).EncryptAll()
+	if err != nil {
+		return nil, err
 	}
 
-	encSvc, err := encryption.NewAes(aesKey)
+	return encryptedRegistryService, nil
+}
+
+func setupSecretService(c *cli.Command, store store.Store, encSvc encryption.Service, privateSignatureKey crypto.PrivateKey) (secret.Service, error) {
+	secretSvc, err := setupDBSecretService(store, encSvc)
 	if err != nil {
-		log.Error().Err(err).Msg("failed to set up secrets encryption service")
+		return nil, err
+	}
+
+	if endpoint := c.String("secret-service-endpoint"); endpoint != "" {
+		return secret.NewHTTP(endpoint, privateSignatureKey, secretSvc), nil
+	}
+
+	return secretSvc, nil
+}
+
+func setupDBSecretService(store store.Store, encSvc encryption.Service) (secret.Service, error) {
+	secretSvc := secret.NewDB(store)
+	if encSvc == nil {
 		return secretSvc, nil
 	}
 
 	encryptedSecretService := secret.NewEncrypted(secretSvc, encSvc)
 	migrationAgent := secret.NewMigration(encryptedSecretService, store)
-	err = migrationAgent.EncryptAll()
+	err := migrationAgent.EncryptAll()
 	if err != nil {
 		return nil, err
 	}
@@ -70,6 +95,44 @@ func setupSecretService(c *cli.Command, store store.Store) (secret.Service, erro
 	return encryptedSecretService, nil
 }
 
+// SetupSecretsEncryption returns the encryption service of the key provider
+// configured by the flags, or nil if secrets are not encrypted.
+func SetupSecretsEncryption(c *cli.Command, store store.Store) (encryption.Service, error) {
+	var providers []string
+	for _, flag := range []string{
+		"secrets-encryption-aes-key",
+		"secrets-encryption-tink-keyset-file",
+		"secrets-encryption-age-identity-file",
+		"secrets-encryption-kms-endpoint",
+	} {
+		if c.String(flag) != "" {
+			providers = append(providers, flag)
+		}
+	}
+
+	switch {
+	case len(providers) == 0:
+		return nil, nil
+	case len(providers) > 1:
+		return nil, fmt.Errorf("only one secrets encryption key provider can be configured, got: %s", strings.Join(providers, ", "))
+	}
+
+	switch providers[0] {
+	case "secrets-encryption-tink-keyset-file":
+		return encryption.NewTink(c.String("secrets-encryption-tink-keyset-file"))
+	case "secrets-encryption-age-identity-file":
+		return encryption.NewAge(c.String("secrets-encryption-age-identity-file"))
+	case "secrets-encryption-kms-endpoint":
+		privateKey, _, err := setupSignatureKeys(store)
+		if err != nil {
+			return nil, err
+		}
+		return encryption.NewKMS(c.String("secrets-encryption-kms-endpoint"), privateKey), nil
+	default:
+		return encryption.NewAes(c.String("secrets-encryption-aes-key"), c.StringSlice("secrets-encryption-aes-old-keys")...)
+	}
+}
+
 func setupConfigService(c *cli.Command, privateSignatureKey crypto.PrivateKey) (config.Service, error) {
 	timeout := c.Duration("forge-timeout")
 	retries := c.Uint("forge-retry")
@@ -52,6 +52,13 @@ func (s storage) AgentUpdate(agent *model.Agent) error {
 	return err
 }
 
+// AgentUpdateLabels only updates the labels of the agent, so changes made to
+// other columns in the meantime (e.g. no-schedule) are kept.
+func (s storage) AgentUpdateLabels(agent *model.Agent) error {
+	_, err := s.engine.ID(agent.ID).Cols("labels").Update(agent)
+	return err
+}
+
 func (s storage) AgentDelete(agent *model.Agent) error {
 	return wrapDelete(s.engine.ID(agent.ID).Delete(new(model.Agent)))
 }
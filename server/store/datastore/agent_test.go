@@ -106,3 +106,28 @@ func TestAgentUpdate(t *testing.T) {
 	err = store.AgentUpdate(agent)
 	assert.NoError(t, err)
 }
+
+func TestAgentUpdateLabels(t *testing.T) {
+	store, closer := newTestStore(t, new(model.Agent))
+	defer closer()
+
+	agent := &model.Agent{
+		ID:    int64(1),
+		Name:  "test",
+		Token: "secret-token",
+	}
+	assert.NoError(t, store.AgentCreate(agent))
+
+	// another request pauses the agent while the poll still holds the old copy
+	paused := *agent
+	paused.NoSchedule = true
+	assert.NoError(t, store.AgentUpdate(&paused))
+
+	agent.Labels = map[string]string{"arch": "arm64"}
+	assert.NoError(t, store.AgentUpdateLabels(agent))
+
+	_agent, err := store.AgentFind(agent.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"arch": "arm64"}, _agent.Labels)
+	assert.True(t, _agent.NoSchedule, "expect no-schedule to be kept")
+}
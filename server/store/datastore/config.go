@@ -18,6 +18,7 @@ import (
 	"crypto/sha256"
 	"errors"
 	"fmt"
+	"time"
 
 	"xorm.io/builder"
 	"xorm.io/xorm"
@@ -54,14 +55,21 @@ func (s storage) ConfigPersist(conf *model.Config) (*model.Config, error) {
 		return nil, err
 	}
 
+	now := time.Now().Unix()
 	existingConfig, err := s.configFindIdentical(sess, conf.RepoID, conf.Hash, conf.Name)
 	if err != nil && !errors.Is(err, types.RecordNotExist) {
 		return nil, err
 	}
 	if existingConfig != nil {
-		return existingConfig, nil
+		// mark the config as used, so it is not deleted as orphan before the pipeline links it
+		existingConfig.Used = now
+		if _, err := sess.ID(existingConfig.ID).Cols("used").Update(existingConfig); err != nil {
+			return nil, err
+		}
+		return existingConfig, sess.Commit()
 	}
 
+	conf.Used = now
 	if err := s.configCreate(sess, conf); err != nil {
 		return nil, err
 	}
@@ -88,3 +96,41 @@ func (s storage) PipelineConfigCreate(config *model.PipelineConfig) error {
 	_, err := s.engine.Insert(config)
 	return err
 }
+
+func (s storage) ConfigDeleteOrphaned(usedBefore int64) (int64, error) {
+	// the newest config could be about to be linked to a pipeline which is created right now
+	var newest []int64
+	if err := s.engine.Table(new(model.Config)).Select("MAX(id)").GroupBy("repo_id, name").Find(&newest); err != nil {
+		return 0, err
+	}
+
+	var orphaned []int64
+	if err := s.engine.Table(new(model.Config)).Select("id").Where(
+		builder.NotIn("id", builder.Select("config_id").From("pipeline_configs")).
+			And(builder.Lt{"used": usedBefore}),
+	).Find(&orphaned); err != nil {
+		return 0, err
+	}
+
+	keep := make(map[int64]struct{}, len(newest))
+	for _, id := range newest {
+		keep[id] = struct{}{}
+	}
+	ids := make([]int64, 0, len(orphaned))
+	for _, id := range orphaned {
+		if _, ok := keep[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+
+	var deleted int64
+	for start := 0; start < len(ids); start += perPage {
+		end := min(start+perPage, len(ids))
+		n, err := s.engine.In("id", ids[start:end]).Delete(new(model.Config))
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n
+	}
+	return deleted, nil
+}
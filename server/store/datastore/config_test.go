@@ -16,6 +16,7 @@ package datastore
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -118,3 +119,52 @@ func TestConfigPersist(t *testing.T) {
 	assert.NoError(t, err)
 	assert.EqualValues(t, 3, count)
 }
+
+func TestConfigDeleteOrphaned(t *testing.T) {
+	store, closer := newTestStore(t, new(model.Config), new(model.PipelineConfig))
+	defer closer()
+
+	var configs []*model.Config
+	for i, name := range []string{"build", "build", "build", "test"} {
+		config, err := store.ConfigPersist(&model.Config{RepoID: 1, Name: name, Data: []byte{byte(i)}})
+		assert.NoError(t, err)
+		configs = append(configs, config)
+	}
+	assert.NoError(t, store.PipelineConfigCreate(&model.PipelineConfig{ConfigID: configs[0].ID, PipelineID: 1}))
+
+	// configs persisted within the grace period could be about to be linked
+	deleted, err := store.ConfigDeleteOrphaned(time.Now().Add(-time.Hour).Unix())
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, deleted)
+
+	deleted, err = store.ConfigDeleteOrphaned(time.Now().Add(time.Hour).Unix())
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, deleted)
+
+	var ids []int64
+	assert.NoError(t, store.engine.Table(new(model.Config)).Select("id").Asc("id").Find(&ids))
+	// used by a pipeline, the newest build config and the newest test config
+	assert.Equal(t, []int64{configs[0].ID, configs[2].ID, configs[3].ID}, ids)
+}
+
+func TestConfigPersistMarksUsed(t *testing.T) {
+	store, closer := newTestStore(t, new(model.Config), new(model.PipelineConfig))
+	defer closer()
+
+	config, err := store.ConfigPersist(&model.Config{RepoID: 1, Name: "build", Data: []byte("old")})
+	assert.NoError(t, err)
+	// newer config of the same name, so the old one is an orphan
+	_, err = store.ConfigPersist(&model.Config{RepoID: 1, Name: "build", Data: []byte("new")})
+	assert.NoError(t, err)
+	_, err = store.engine.ID(config.ID).Cols("used").Update(&model.Config{Used: 1})
+	assert.NoError(t, err)
+
+	// reusing the identical config marks it as used again
+	reused, err := store.ConfigPersist(&model.Config{RepoID: 1, Name: "build", Data: []byte("old")})
+	assert.NoError(t, err)
+	assert.Equal(t, config.ID, reused.ID)
+
+	deleted, err := store.ConfigDeleteOrphaned(time.Now().Add(-time.Hour).Unix())
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, deleted, "expect reused config not to be deleted")
+}
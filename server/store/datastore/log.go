@@ -53,6 +53,34 @@ func (s storage) LogDelete(step *model.Step) error {
 }
 
 func logDelete(sess *xorm.Session, stepID int64) error {
+	if _, err := sess.Where("step_id = ?", stepID).Delete(new(model.LogSearchIndex)); err != nil {
+		return err
+	}
 	_, err := sess.Where("step_id = ?", stepID).Delete(new(model.LogEntry))
 	return err
 }
+
+func (s storage) LogSearchIndexFind(step *model.Step) ([]byte, error) {
+	index := new(model.LogSearchIndex)
+	found, err := s.engine.Where("step_id = ?", step.ID).Get(index)
+	if err != nil || !found {
+		return nil, err
+	}
+	return index.Data, nil
+}
+
+func (s storage) LogSearchIndexSave(step *model.Step, data []byte) error {
+	sess := s.engine.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if _, err := sess.Where("step_id = ?", step.ID).Delete(new(model.LogSearchIndex)); err != nil {
+		return err
+	}
+	if _, err := sess.Insert(&model.LogSearchIndex{StepID: step.ID, Data: data}); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
@@ -23,7 +23,7 @@ import (
 )
 
 func TestLogCreateFindDelete(t *testing.T) {
-	store, closer := newTestStore(t, new(model.Step), new(model.LogEntry))
+	store, closer := newTestStore(t, new(model.Step), new(model.LogEntry), new(model.LogSearchIndex))
 	defer closer()
 
 	step := model.Step{
@@ -96,3 +96,26 @@ func TestLogAppend(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, _logEntries, len(logEntries)+1)
 }
+
+func TestLogSearchIndex(t *testing.T) {
+	store, closer := newTestStore(t, new(model.Step), new(model.LogEntry), new(model.LogSearchIndex))
+	defer closer()
+
+	step := &model.Step{ID: 1}
+
+	index, err := store.LogSearchIndexFind(step)
+	assert.NoError(t, err)
+	assert.Nil(t, index)
+
+	assert.NoError(t, store.LogSearchIndexSave(step, []byte{1, 2}))
+	assert.NoError(t, store.LogSearchIndexSave(step, []byte{3}))
+	index, err = store.LogSearchIndexFind(step)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{3}, index)
+
+	// the index is deleted with the logs
+	assert.NoError(t, store.LogDelete(step))
+	index, err = store.LogSearchIndexFind(step)
+	assert.NoError(t, err)
+	assert.Nil(t, index)
+}
@@ -66,6 +66,7 @@ var migrationTasks = []*xormigrate.Migration{
 	&fixV31Registries,
 	&correctPotentialCorruptOrgsUsersRelation,
 	&gatedToRequireApproval,
+	&alterTableForgesUpdateColumnClientSecretType,
 }
 
 var allBeans = []any{
@@ -74,6 +75,7 @@ var allBeans = []any{
 	new(model.PipelineConfig),
 	new(model.Config),
 	new(model.LogEntry),
+	new(model.LogSearchIndex),
 	new(model.Perm),
 	new(model.Step),
 	new(model.Registry),
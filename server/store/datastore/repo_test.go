@@ -302,6 +302,7 @@ func TestRepoCrud(t *testing.T) {
 		new(model.Pipeline),
 		new(model.PipelineConfig),
 		new(model.LogEntry),
+		new(model.LogSearchIndex),
 		new(model.Step),
 		new(model.Secret),
 		new(model.Registry),
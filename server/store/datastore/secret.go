@@ -51,7 +51,25 @@ func (s storage) SecretCreate(secret *model.Secret) error {
 }
 
 func (s storage) SecretUpdate(secret *model.Secret) error {
-	_, err := s.engine.ID(secret.ID).AllCols().Update(secret)
+	// the usage is only set by SecretRecordUsage, so that updating a secret
+	// can not reset the usage recorded meanwhile
+	_, err := s.engine.ID(secret.ID).AllCols().Omit(secretUsageCols...).Update(secret)
+	return err
+}
+
+var secretUsageCols = []string{"usage_count", "last_used_at", "last_used_repo_id", "last_used_pipeline"}
+
+func (s storage) SecretRecordUsage(ids []int64, repoID, pipelineNumber, usedAt int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := s.engine.In("id", ids).Incr("usage_count").
+		Cols("last_used_at", "last_used_repo_id", "last_used_pipeline").
+		Update(&model.Secret{
+			LastUsedAt:       usedAt,
+			LastUsedRepoID:   repoID,
+			LastUsedPipeline: pipelineNumber,
+		})
 	return err
 }
 
@@ -47,6 +47,36 @@ func TestSecretFind(t *testing.T) {
 	assert.Equal(t, "node", secret.Images[1])
 }
 
+func TestSecretRecordUsage(t *testing.T) {
+	store, closer := newTestStore(t, new(model.Secret))
+	defer closer()
+
+	createTestSecrets(t, store)
+	list, err := store.SecretListAll()
+	assert.NoError(t, err)
+	assert.Len(t, list, 4)
+
+	assert.NoError(t, store.SecretRecordUsage([]int64{list[0].ID, list[1].ID}, 1, 5, 1000))
+	assert.NoError(t, store.SecretRecordUsage([]int64{list[0].ID}, 2, 7, 2000))
+	assert.NoError(t, store.SecretRecordUsage(nil, 2, 8, 3000))
+
+	// updating a secret keeps the usage
+	list[0].Value = "new-value"
+	assert.NoError(t, store.SecretUpdate(list[0]))
+
+	list, err = store.SecretListAll()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, list[0].UsageCount)
+	assert.EqualValues(t, 2000, list[0].LastUsedAt)
+	assert.EqualValues(t, 2, list[0].LastUsedRepoID)
+	assert.EqualValues(t, 7, list[0].LastUsedPipeline)
+	assert.Equal(t, "new-value", list[0].Value)
+	assert.EqualValues(t, 1, list[1].UsageCount)
+	assert.EqualValues(t, 1000, list[1].LastUsedAt)
+	assert.EqualValues(t, 0, list[2].UsageCount)
+	assert.EqualValues(t, 0, list[2].LastUsedAt)
+}
+
 func TestSecretList(t *testing.T) {
 	store, closer := newTestStore(t, new(model.Secret))
 	defer closer()
@@ -55,6 +55,15 @@ func (s storage) StepList(pipeline *model.Pipeline) ([]*model.Step, error) {
 		Find(&stepList)
 }
 
+func (s storage) StepListAfter(id int64, limit int) ([]*model.Step, error) {
+	stepList := make([]*model.Step, 0, limit)
+	return stepList, s.engine.
+		Where("id > ?", id).
+		OrderBy("id").
+		Limit(limit).
+		Find(&stepList)
+}
+
 func (s storage) StepListFromWorkflowFind(workflow *model.Workflow) ([]*model.Step, error) {
 	return s.stepListWorkflow(s.engine.NewSession(), workflow)
 }
@@ -79,7 +88,46 @@ func (s storage) stepCreate(sess *xorm.Session, steps []*model.Step) error {
 }
 
 func (s storage) StepUpdate(step *model.Step) error {
-	_, err := s.engine.ID(step.ID).AllCols().Update(step)
+	// log_truncated, log_size and log_lines are only set by their own methods, so
+	// that status updates running concurrently to the log upload can not reset them
+	_, err := s.engine.ID(step.ID).AllCols().Omit("log_truncated", "log_size", "log_lines").Update(step)
+	return err
+}
+
+func (s storage) StepAddLogSize(step *model.Step, size, lines int64) error {
+	_, err := s.engine.ID(step.ID).Incr("log_size", size).Incr("log_lines", lines).Update(new(model.Step))
+	return err
+}
+
+func (s storage) StepResetLogSize(step *model.Step) error {
+	step.LogSize = 0
+	step.LogLines = 0
+	_, err := s.engine.ID(step.ID).Cols("log_size", "log_lines").Update(step)
+	return err
+}
+
+func (s storage) RepoLogSize(repoID int64) (int64, error) {
+	return s.engine.Table("steps").
+		Join("INNER", "pipelines", "pipelines.id = steps.pipeline_id").
+		Where("pipelines.repo_id = ?", repoID).
+		SumInt(new(model.Step), "steps.log_size")
+}
+
+func (s storage) OrgLogSize(orgID int64) (int64, error) {
+	return s.engine.Table("steps").
+		Join("INNER", "pipelines", "pipelines.id = steps.pipeline_id").
+		Join("INNER", "repos", "repos.id = pipelines.repo_id").
+		Where("repos.org_id = ?", orgID).
+		SumInt(new(model.Step), "steps.log_size")
+}
+
+func (s storage) OrgLogQuotaExists() (bool, error) {
+	return s.engine.Where("log_quota > 0").Exist(new(model.Org))
+}
+
+func (s storage) StepSetLogTruncated(step *model.Step) error {
+	step.LogTruncated = true
+	_, err := s.engine.ID(step.ID).Cols("log_truncated").Update(step)
 	return err
 }
 
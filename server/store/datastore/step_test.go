@@ -16,6 +16,7 @@
 package datastore
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -121,6 +122,33 @@ func TestStepList(t *testing.T) {
 	assert.Len(t, steps, 2)
 }
 
+func TestStepListAfter(t *testing.T) {
+	store, closer := newTestStore(t, new(model.Step), new(model.Pipeline))
+	defer closer()
+
+	sess := store.engine.NewSession()
+	err := store.stepCreate(sess, []*model.Step{
+		{UUID: "2bf387f7-2913-4907-814c-c9ada88707c0", PipelineID: 1, PID: 1},
+		{UUID: "4b04073c-1827-4aa4-a5f5-c7b21c5e44a6", PipelineID: 1, PID: 2},
+		{UUID: "40aab045-970b-4892-b6df-6f825a7ec97a", PipelineID: 2, PID: 1},
+	})
+	assert.NoError(t, err)
+	_ = sess.Commit()
+
+	steps, err := store.StepListAfter(0, 2)
+	assert.NoError(t, err)
+	if assert.Len(t, steps, 2) {
+		assert.EqualValues(t, 1, steps[0].ID)
+		assert.EqualValues(t, 2, steps[1].ID)
+	}
+
+	steps, err = store.StepListAfter(2, 2)
+	assert.NoError(t, err)
+	if assert.Len(t, steps, 1) {
+		assert.EqualValues(t, 3, steps[0].ID)
+	}
+}
+
 func TestStepUpdate(t *testing.T) {
 	store, closer := newTestStore(t, new(model.Step), new(model.Pipeline))
 	defer closer()
@@ -145,6 +173,104 @@ func TestStepUpdate(t *testing.T) {
 	assert.Equal(t, model.StatusRunning, updated.State)
 }
 
+func TestStepSetLogTruncated(t *testing.T) {
+	store, closer := newTestStore(t, new(model.Step), new(model.Pipeline))
+	defer closer()
+
+	step := &model.Step{
+		UUID:       "0ac4b1a6-5e45-4a7b-b5e4-6d2e9c0f3a11",
+		PipelineID: 1,
+		PID:        1,
+		PPID:       1,
+		Name:       "build",
+		State:      "running",
+	}
+	sess := store.engine.NewSession()
+	assert.NoError(t, store.stepCreate(sess, []*model.Step{step}))
+	_ = sess.Commit()
+
+	assert.NoError(t, store.StepSetLogTruncated(&model.Step{ID: step.ID}))
+
+	// a status update of an outdated copy must not reset the flag
+	step.State = "success"
+	assert.NoError(t, store.StepUpdate(step))
+
+	updated, err := store.StepLoad(step.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.StatusSuccess, updated.State)
+	assert.True(t, updated.LogTruncated)
+}
+
+func TestStepLogSize(t *testing.T) {
+	store, closer := newTestStore(t, new(model.Step), new(model.Pipeline), new(model.Repo))
+	defer closer()
+
+	repos := []*model.Repo{
+		{UserID: 1, OrgID: 1, FullName: "bradrydzewski/test", Owner: "bradrydzewski", Name: "test", ForgeRemoteID: "1"},
+		{UserID: 1, OrgID: 1, FullName: "bradrydzewski/other", Owner: "bradrydzewski", Name: "other", ForgeRemoteID: "2"},
+		{UserID: 1, OrgID: 2, FullName: "octocat/test", Owner: "octocat", Name: "test", ForgeRemoteID: "3"},
+	}
+	var steps []*model.Step
+	for i, repo := range repos {
+		assert.NoError(t, store.CreateRepo(repo))
+		pipeline := &model.Pipeline{RepoID: repo.ID, Number: 1}
+		assert.NoError(t, store.CreatePipeline(pipeline))
+		step := &model.Step{UUID: fmt.Sprintf("step-%d", i), PipelineID: pipeline.ID, PID: 1, Name: "build"}
+		sess := store.engine.NewSession()
+		assert.NoError(t, store.stepCreate(sess, []*model.Step{step}))
+		_ = sess.Commit()
+		steps = append(steps, step)
+	}
+
+	assert.NoError(t, store.StepAddLogSize(steps[0], 100, 10))
+	assert.NoError(t, store.StepAddLogSize(steps[0], 20, 2))
+	assert.NoError(t, store.StepAddLogSize(steps[1], 3, 1))
+	assert.NoError(t, store.StepAddLogSize(steps[2], 7, 1))
+
+	// a status update of an outdated copy must not reset the size
+	steps[0].State = model.StatusSuccess
+	assert.NoError(t, store.StepUpdate(steps[0]))
+	step, err := store.StepLoad(steps[0].ID)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 120, step.LogSize)
+	assert.EqualValues(t, 12, step.LogLines)
+
+	size, err := store.RepoLogSize(repos[0].ID)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 120, size)
+	size, err = store.OrgLogSize(1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 123, size)
+
+	assert.NoError(t, store.StepResetLogSize(steps[0]))
+	step, err = store.StepLoad(steps[0].ID)
+	assert.NoError(t, err)
+	assert.Zero(t, step.LogLines)
+	size, err = store.OrgLogSize(1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, size)
+	size, err = store.OrgLogSize(3)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, size)
+}
+
+func TestOrgLogQuotaExists(t *testing.T) {
+	store, closer := newTestStore(t, new(model.Org))
+	defer closer()
+
+	org := &model.Org{Name: "octocat"}
+	assert.NoError(t, store.OrgCreate(org))
+	exists, err := store.OrgLogQuotaExists()
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	org.LogQuota = 1000
+	assert.NoError(t, store.OrgUpdate(org))
+	exists, err = store.OrgLogQuotaExists()
+	assert.NoError(t, err)
+	assert.True(t, exists)
+}
+
 func TestStepIndexes(t *testing.T) {
 	store, closer := newTestStore(t, new(model.Step), new(model.Pipeline))
 	defer closer()
@@ -15,6 +15,8 @@
 package datastore
 
 import (
+	"time"
+
 	"go.woodpecker-ci.org/woodpecker/v2/server/model"
 )
 
@@ -29,6 +31,28 @@ func (s storage) TaskInsert(task *model.Task) error {
 	return err
 }
 
+func (s storage) TaskLease(id, owner string, agentID int64, until time.Time) (bool, error) {
+	// the conditional update is atomic, so only one server can lease the task,
+	// tasks created before the column became NOT NULL may have no lease at all
+	count, err := s.engine.
+		Where("id = ? AND (lease_expires IS NULL OR lease_expires < ?)", id, time.Now().Unix()).
+		Cols("lease_owner", "lease_expires", "agent_id").
+		Update(&model.Task{
+			LeaseOwner:   owner,
+			LeaseExpires: until.Unix(),
+			AgentID:      agentID,
+		})
+	return count == 1, err
+}
+
+func (s storage) TaskExtendLease(id string, until time.Time) error {
+	_, err := s.engine.
+		Where("id = ? AND lease_expires > 0", id).
+		Cols("lease_expires").
+		Update(&model.Task{LeaseExpires: until.Unix()})
+	return err
+}
+
 func (s storage) TaskDelete(id string) error {
 	return wrapDelete(s.engine.Where("id = ?", id).Delete(new(model.Task)))
 }
@@ -16,6 +16,7 @@ package datastore
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -46,3 +47,62 @@ func TestTaskList(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, list, 0, "Want empty task list after delete")
 }
+
+func TestTaskLease(t *testing.T) {
+	store, closer := newTestStore(t, new(model.Task))
+	defer closer()
+
+	task := &model.Task{ID: "1"}
+	assert.NoError(t, store.TaskInsert(task))
+
+	leased, err := store.TaskLease(task.ID, "server-1", 1, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, leased)
+
+	leased, err = store.TaskLease(task.ID, "server-2", 1, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.False(t, leased, "expect task with a lease not to be leased again")
+
+	assert.NoError(t, store.TaskExtendLease(task.ID, time.Now().Add(-time.Minute)))
+	leased, err = store.TaskLease(task.ID, "server-2", 1, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, leased, "expect task with an expired lease to be leased again")
+
+	list, err := store.TaskList()
+	assert.NoError(t, err)
+	assert.Equal(t, "server-2", list[0].LeaseOwner)
+	assert.EqualValues(t, 1, list[0].AgentID)
+
+	leased, err = store.TaskLease("2", "server-1", 1, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.False(t, leased, "expect unknown task not to be leased")
+}
+
+// legacyTask is the tasks table as created before its lease_expires column
+// became NOT NULL.
+type legacyTask struct {
+	ID           string `xorm:"PK UNIQUE 'id'"`
+	LeaseExpires *int64 `xorm:"'lease_expires'"`
+}
+
+func (legacyTask) TableName() string {
+	return "tasks"
+}
+
+func TestTaskLeaseWithoutLease(t *testing.T) {
+	store, closer := newTestStore(t, new(legacyTask))
+	defer closer()
+
+	_, err := store.engine.Insert(&legacyTask{ID: "1"})
+	assert.NoError(t, err)
+	// syncing does not change existing columns, so the lease stays NULL
+	assert.NoError(t, store.engine.Sync(new(model.Task)))
+
+	leased, err := store.TaskLease("1", "server-1", 1, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, leased, "expect task without lease to be leased")
+
+	leased, err = store.TaskLease("1", "server-2", 1, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.False(t, leased)
+}
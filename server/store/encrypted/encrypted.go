@@ -0,0 +1,326 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+// Package encrypted provides a store wrapper which keeps the credentials of
+// users and forges encrypted at rest.
+package encrypted
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/services/encryption"
+	"go.woodpecker-ci.org/woodpecker/v2/server/store"
+)
+
+const (
+	// associated data bound to the encrypted values, so that a value can not
+	// be moved into another column
+	userTokenData         = "user_token"
+	userSecretData        = "user_secret"
+	forgeClientSecretData = "forge_client_secret"
+)
+
+// Store wraps a store and encrypts the oauth tokens of users and the client
+// secrets of forges on write and decrypts them transparently on read.
+type Store struct {
+	store.Store
+	encryptionSvc encryption.Service
+}
+
+// New returns a store which encrypts credentials using the given encryption service.
+func New(s store.Store, encryptionService encryption.Service) *Store {
+	return &Store{
+		Store:         s,
+		encryptionSvc: encryptionService,
+	}
+}
+
+func (s *Store) header() string {
+	return encryption.Header(s.encryptionSvc.Algo())
+}
+
+func (s *Store) isEncoded(value string) bool {
+	return encryption.IsEncrypted(s.encryptionSvc.Algo(), value)
+}
+
+func (s *Store) encrypt(value *string, associatedData string) error {
+	if err := encryption.CheckAlgo(s.encryptionSvc, *value); err != nil {
+		return err
+	}
+	if *value == "" || s.isEncoded(*value) {
+		return nil
+	}
+
+	encryptedValue, err := s.encryptionSvc.Encrypt(*value, associatedData)
+	if err != nil {
+		return err
+	}
+	*value = s.header() + encryptedValue
+	return nil
+}
+
+func (s *Store) decrypt(value *string, associatedData string) error {
+	if err := encryption.CheckAlgo(s.encryptionSvc, *value); err != nil {
+		return err
+	}
+	if !s.isEncoded(*value) {
+		return nil
+	}
+
+	decryptedValue, err := s.encryptionSvc.Decrypt(strings.TrimPrefix(*value, s.header()), associatedData)
+	if err != nil {
+		return err
+	}
+	*value = decryptedValue
+	return nil
+}
+
+func (s *Store) encryptUser(user *model.User) error {
+	if err := s.encrypt(&user.Token, userTokenData); err != nil {
+		return fmt.Errorf("failed to encrypt token of user id=%d: %w", user.ID, err)
+	}
+	if err := s.encrypt(&user.Secret, userSecretData); err != nil {
+		return fmt.Errorf("failed to encrypt secret of user id=%d: %w", user.ID, err)
+	}
+	return nil
+}
+
+func (s *Store) decryptUser(user *model.User) error {
+	if err := s.decrypt(&user.Token, userTokenData); err != nil {
+		return fmt.Errorf("failed to decrypt token of user id=%d: %w", user.ID, err)
+	}
+	if err := s.decrypt(&user.Secret, userSecretData); err != nil {
+		return fmt.Errorf("failed to decrypt secret of user id=%d: %w", user.ID, err)
+	}
+	return nil
+}
+
+func (s *Store) encryptForge(forge *model.Forge) error {
+	if err := s.encrypt(&forge.ClientSecret, forgeClientSecretData); err != nil {
+		return fmt.Errorf("failed to encrypt client secret of forge id=%d: %w", forge.ID, err)
+	}
+	return nil
+}
+
+func (s *Store) decryptForge(forge *model.Forge) error {
+	if err := s.decrypt(&forge.ClientSecret, forgeClientSecretData); err != nil {
+		return fmt.Errorf("failed to decrypt client secret of forge id=%d: %w", forge.ID, err)
+	}
+	return nil
+}
+
+func (s *Store) user(user *model.User, err error) (*model.User, error) {
+	if err != nil {
+		return user, err
+	}
+	if err := s.decryptUser(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *Store) forge(forge *model.Forge, err error) (*model.Forge, error) {
+	if err != nil {
+		return forge, err
+	}
+	if err := s.decryptForge(forge); err != nil {
+		return nil, err
+	}
+	return forge, nil
+}
+
+// Store (server/store/store.go) interface implementation
+
+func (s *Store) GetUser(id int64) (*model.User, error) {
+	return s.user(s.Store.GetUser(id))
+}
+
+func (s *Store) GetUserRemoteID(remoteID model.ForgeRemoteID, login string) (*model.User, error) {
+	return s.user(s.Store.GetUserRemoteID(remoteID, login))
+}
+
+func (s *Store) GetUserLogin(login string) (*model.User, error) {
+	return s.user(s.Store.GetUserLogin(login))
+}
+
+func (s *Store) GetUserList(p *model.ListOptions) ([]*model.User, error) {
+	users, err := s.Store.GetUserList(p)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		if err := s.decryptUser(user); err != nil {
+			return nil, err
+		}
+	}
+	return users, nil
+}
+
+// CreateUser stores the user with encrypted credentials, the passed user keeps
+// its plain text credentials.
+func (s *Store) CreateUser(user *model.User) error {
+	token, secret := user.Token, user.Secret
+	defer func() { user.Token, user.Secret = token, secret }()
+
+	if err := s.encryptUser(user); err != nil {
+		return err
+	}
+	return s.Store.CreateUser(user)
+}
+
+// UpdateUser stores the user with encrypted credentials, the passed user keeps
+// its plain text credentials.
+func (s *Store) UpdateUser(user *model.User) error {
+	token, secret := user.Token, user.Secret
+	defer func() { user.Token, user.Secret = token, secret }()
+
+	if err := s.encryptUser(user); err != nil {
+		return err
+	}
+	return s.Store.UpdateUser(user)
+}
+
+func (s *Store) ForgeGet(id int64) (*model.Forge, error) {
+	return s.forge(s.Store.ForgeGet(id))
+}
+
+func (s *Store) ForgeList(p *model.ListOptions) ([]*model.Forge, error) {
+	forges, err := s.Store.ForgeList(p)
+	if err != nil {
+		return nil, err
+	}
+	for _, forge := range forges {
+		if err := s.decryptForge(forge); err != nil {
+			return nil, err
+		}
+	}
+	return forges, nil
+}
+
+// ForgeCreate stores the forge with an encrypted client secret, the passed
+// forge keeps its plain text client secret.
+func (s *Store) ForgeCreate(forge *model.Forge) error {
+	clientSecret := forge.ClientSecret
+	defer func() { forge.ClientSecret = clientSecret }()
+
+	if err := s.encryptForge(forge); err != nil {
+		return err
+	}
+	return s.Store.ForgeCreate(forge)
+}
+
+// ForgeUpdate stores the forge with an encrypted client secret, the passed
+// forge keeps its plain text client secret.
+func (s *Store) ForgeUpdate(forge *model.Forge) error {
+	clientSecret := forge.ClientSecret
+	defer func() { forge.ClientSecret = clientSecret }()
+
+	if err := s.encryptForge(forge); err != nil {
+		return err
+	}
+	return s.Store.ForgeUpdate(forge)
+}
+
+// EncryptAll encrypts the credentials of all users and forges which are still
+// stored in plain text. It is meant to run once on startup.
+func (s *Store) EncryptAll() error {
+	log.Info().Msg("encrypting all user and forge credentials")
+
+	_, err := s.migrate(s.encryptUser, s.encryptForge)
+	if err != nil {
+		return fmt.Errorf("cannot encrypt credentials: %w", err)
+	}
+
+	log.Info().Msg("all user and forge credentials are encrypted")
+	return nil
+}
+
+// ReencryptAll encrypts the credentials of all users and forges with the
+// current encryption key, so that old keys can be removed after a key
+// rotation. It returns the number of re-encrypted users and forges.
+func (s *Store) ReencryptAll() (int, error) {
+	log.Info().Msg("re-encrypting all user and forge credentials")
+
+	n, err := s.migrate(
+		func(user *model.User) error {
+			if err := s.decryptUser(user); err != nil {
+				return err
+			}
+			return s.encryptUser(user)
+		},
+		func(forge *model.Forge) error {
+			if err := s.decryptForge(forge); err != nil {
+				return err
+			}
+			return s.encryptForge(forge)
+		},
+	)
+	if err != nil {
+		return n, fmt.Errorf("cannot re-encrypt credentials: %w", err)
+	}
+
+	log.Info().Msg("all user and forge credentials are re-encrypted")
+	return n, nil
+}
+
+// migrate applies the given functions to the raw users and forges and stores
+// the ones which have changed.
+func (s *Store) migrate(userFn func(*model.User) error, forgeFn func(*model.Forge) error) (int, error) {
+	n := 0
+
+	users, err := s.Store.GetUserList(&model.ListOptions{All: true})
+	if err != nil {
+		return n, err
+	}
+	for _, user := range users {
+		token, secret := user.Token, user.Secret
+		if err := userFn(user); err != nil {
+			return n, err
+		}
+		if user.Token == token && user.Secret == secret {
+			continue
+		}
+		if err := s.Store.UpdateUser(user); err != nil {
+			return n, err
+		}
+		n++
+	}
+
+	forges, err := s.Store.ForgeList(&model.ListOptions{All: true})
+	if err != nil {
+		return n, err
+	}
+	for _, forge := range forges {
+		clientSecret := forge.ClientSecret
+		if err := forgeFn(forge); err != nil {
+			return n, err
+		}
+		if forge.ClientSecret == clientSecret {
+			continue
+		}
+		if err := s.Store.ForgeUpdate(forge); err != nil {
+			return n, err
+		}
+		n++
+	}
+
+	return n, nil
+}
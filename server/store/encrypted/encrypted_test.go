@@ -0,0 +1,136 @@
+/*
+This file is part of Woodpecker CI.
+Copyright (c) 2025 Woodpecker Authors
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, version 3 of the License.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package encrypted
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"go.woodpecker-ci.org/woodpecker/v2/server/model"
+	"go.woodpecker-ci.org/woodpecker/v2/server/services/encryption"
+	mocks_store "go.woodpecker-ci.org/woodpecker/v2/server/store/mocks"
+)
+
+func TestUserCredentials(t *testing.T) {
+	mockStore := mocks_store.NewStore(t)
+	s := New(mockStore, mustAes(t, "key"))
+
+	var stored model.User
+	mockStore.On("UpdateUser", mock.Anything).Once().Return(nil).
+		Run(func(args mock.Arguments) {
+			stored = *args.Get(0).(*model.User)
+		})
+	user := &model.User{ID: 1, Login: "octocat", Token: "token", Secret: "secret"}
+	assert.NoError(t, s.UpdateUser(user))
+
+	// the caller keeps the plain text values
+	assert.Equal(t, "token", user.Token)
+	assert.Equal(t, "secret", user.Secret)
+	assert.True(t, s.isEncoded(stored.Token))
+	assert.True(t, s.isEncoded(stored.Secret))
+	encryptedSecret := stored.Secret
+
+	mockStore.On("GetUser", int64(1)).Once().Return(&stored, nil)
+	user, err := s.GetUser(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "token", user.Token)
+	assert.Equal(t, "secret", user.Secret)
+
+	// values can not be moved to other columns
+	mockStore.On("GetUserLogin", "octocat").Once().
+		Return(&model.User{ID: 1, Token: encryptedSecret}, nil)
+	_, err = s.GetUserLogin("octocat")
+	assert.Error(t, err)
+}
+
+func TestForgeCredentials(t *testing.T) {
+	mockStore := mocks_store.NewStore(t)
+	s := New(mockStore, mustAes(t, "key"))
+
+	var stored model.Forge
+	mockStore.On("ForgeCreate", mock.Anything).Once().Return(nil).
+		Run(func(args mock.Arguments) {
+			forge := args.Get(0).(*model.Forge)
+			forge.ID = 1
+			stored = *forge
+		})
+	forge := &model.Forge{ClientSecret: "client-secret"}
+	assert.NoError(t, s.ForgeCreate(forge))
+	assert.EqualValues(t, 1, forge.ID)
+	assert.Equal(t, "client-secret", forge.ClientSecret)
+	assert.True(t, s.isEncoded(stored.ClientSecret))
+
+	mockStore.On("ForgeList", mock.Anything).Once().Return([]*model.Forge{&stored}, nil)
+	forges, err := s.ForgeList(&model.ListOptions{All: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "client-secret", forges[0].ClientSecret)
+}
+
+func TestEncryptAll(t *testing.T) {
+	mockStore := mocks_store.NewStore(t)
+	s := New(mockStore, mustAes(t, "key"))
+
+	mockStore.On("GetUserList", mock.Anything).Once().Return([]*model.User{
+		{ID: 1, Token: "token"},
+		{ID: 2},
+	}, nil)
+	mockStore.On("UpdateUser", mock.Anything).Once().Return(nil).
+		Run(func(args mock.Arguments) {
+			user := args.Get(0).(*model.User)
+			assert.EqualValues(t, 1, user.ID)
+			assert.True(t, s.isEncoded(user.Token))
+			assert.Empty(t, user.Secret)
+		})
+	mockStore.On("ForgeList", mock.Anything).Once().Return([]*model.Forge{{ID: 1, ClientSecret: "client-secret"}}, nil)
+	mockStore.On("ForgeUpdate", mock.Anything).Once().Return(nil).
+		Run(func(args mock.Arguments) {
+			assert.True(t, s.isEncoded(args.Get(0).(*model.Forge).ClientSecret))
+		})
+
+	assert.NoError(t, s.EncryptAll())
+}
+
+func TestReencryptAll(t *testing.T) {
+	old := New(nil, mustAes(t, "old key"))
+	user := &model.User{ID: 1, Token: "token"}
+	assert.NoError(t, old.encryptUser(user))
+
+	encSvc, err := encryption.NewAes("new key", "old key")
+	assert.NoError(t, err)
+	mockStore := mocks_store.NewStore(t)
+	s := New(mockStore, encSvc)
+
+	mockStore.On("GetUserList", mock.Anything).Once().Return([]*model.User{user}, nil)
+	mockStore.On("UpdateUser", mock.Anything).Once().Return(nil)
+	mockStore.On("ForgeList", mock.Anything).Once().Return([]*model.Forge{}, nil)
+
+	n, err := s.ReencryptAll()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	assert.NoError(t, New(nil, mustAes(t, "new key")).decryptUser(user))
+	assert.Equal(t, "token", user.Token)
+}
+
+func mustAes(t *testing.T, key string) encryption.Service {
+	encSvc, err := encryption.NewAes(key)
+	assert.NoError(t, err)
+	return encSvc
+}
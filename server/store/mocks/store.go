@@ -10,6 +10,8 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 	model "go.woodpecker-ci.org/woodpecker/v2/server/model"
+
+	time "time"
 )
 
 // Store is an autogenerated mock type for the Store type
@@ -161,6 +163,24 @@ func (_m *Store) AgentUpdate(_a0 *model.Agent) error {
 	return r0
 }
 
+// AgentUpdateLabels provides a mock function with given fields: _a0
+func (_m *Store) AgentUpdateLabels(_a0 *model.Agent) error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AgentUpdateLabels")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Agent) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Close provides a mock function with given fields:
 func (_m *Store) Close() error {
 	ret := _m.Called()
@@ -179,6 +199,34 @@ func (_m *Store) Close() error {
 	return r0
 }
 
+// ConfigDeleteOrphaned provides a mock function with given fields: usedBefore
+func (_m *Store) ConfigDeleteOrphaned(usedBefore int64) (int64, error) {
+	ret := _m.Called(usedBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfigDeleteOrphaned")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (int64, error)); ok {
+		return rf(usedBefore)
+	}
+	if rf, ok := ret.Get(0).(func(int64) int64); ok {
+		r0 = rf(usedBefore)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(usedBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ConfigPersist provides a mock function with given fields: _a0
 func (_m *Store) ConfigPersist(_a0 *model.Config) (*model.Config, error) {
 	ret := _m.Called(_a0)
@@ -1406,6 +1454,54 @@ func (_m *Store) LogFind(_a0 *model.Step) ([]*model.LogEntry, error) {
 	return r0, r1
 }
 
+// LogSearchIndexFind provides a mock function with given fields: _a0
+func (_m *Store) LogSearchIndexFind(_a0 *model.Step) ([]byte, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LogSearchIndexFind")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*model.Step) ([]byte, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(*model.Step) []byte); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.Step) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LogSearchIndexSave provides a mock function with given fields: _a0, _a1
+func (_m *Store) LogSearchIndexSave(_a0 *model.Step, _a1 []byte) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LogSearchIndexSave")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Step, []byte) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Migrate provides a mock function with given fields: _a0, _a1
 func (_m *Store) Migrate(_a0 context.Context, _a1 bool) error {
 	ret := _m.Called(_a0, _a1)
@@ -1550,6 +1646,62 @@ func (_m *Store) OrgList(_a0 *model.ListOptions) ([]*model.Org, error) {
 	return r0, r1
 }
 
+// OrgLogQuotaExists provides a mock function with given fields:
+func (_m *Store) OrgLogQuotaExists() (bool, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for OrgLogQuotaExists")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (bool, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrgLogSize provides a mock function with given fields: orgID
+func (_m *Store) OrgLogSize(orgID int64) (int64, error) {
+	ret := _m.Called(orgID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OrgLogSize")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (int64, error)); ok {
+		return rf(orgID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) int64); ok {
+		r0 = rf(orgID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(orgID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // OrgRegistryFind provides a mock function with given fields: _a0, _a1
 func (_m *Store) OrgRegistryFind(_a0 int64, _a1 string) (*model.Registry, error) {
 	ret := _m.Called(_a0, _a1)
@@ -2036,6 +2188,34 @@ func (_m *Store) RepoListLatest(_a0 *model.User) ([]*model.Feed, error) {
 	return r0, r1
 }
 
+// RepoLogSize provides a mock function with given fields: repoID
+func (_m *Store) RepoLogSize(repoID int64) (int64, error) {
+	ret := _m.Called(repoID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RepoLogSize")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (int64, error)); ok {
+		return rf(repoID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) int64); ok {
+		r0 = rf(repoID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(repoID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SecretCreate provides a mock function with given fields: _a0
 func (_m *Store) SecretCreate(_a0 *model.Secret) error {
 	ret := _m.Called(_a0)
@@ -2162,6 +2342,24 @@ func (_m *Store) SecretListAll() ([]*model.Secret, error) {
 	return r0, r1
 }
 
+// SecretRecordUsage provides a mock function with given fields: ids, repoID, pipelineNumber, usedAt
+func (_m *Store) SecretRecordUsage(ids []int64, repoID int64, pipelineNumber int64, usedAt int64) error {
+	ret := _m.Called(ids, repoID, pipelineNumber, usedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SecretRecordUsage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]int64, int64, int64, int64) error); ok {
+		r0 = rf(ids, repoID, pipelineNumber, usedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SecretUpdate provides a mock function with given fields: _a0
 func (_m *Store) SecretUpdate(_a0 *model.Secret) error {
 	ret := _m.Called(_a0)
@@ -2244,6 +2442,24 @@ func (_m *Store) ServerConfigSet(_a0 string, _a1 string) error {
 	return r0
 }
 
+// StepAddLogSize provides a mock function with given fields: step, size, lines
+func (_m *Store) StepAddLogSize(step *model.Step, size int64, lines int64) error {
+	ret := _m.Called(step, size, lines)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StepAddLogSize")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Step, int64, int64) error); ok {
+		r0 = rf(step, size, lines)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // StepByUUID provides a mock function with given fields: _a0
 func (_m *Store) StepByUUID(_a0 string) (*model.Step, error) {
 	ret := _m.Called(_a0)
@@ -2364,6 +2580,36 @@ func (_m *Store) StepList(_a0 *model.Pipeline) ([]*model.Step, error) {
 	return r0, r1
 }
 
+// StepListAfter provides a mock function with given fields: id, limit
+func (_m *Store) StepListAfter(id int64, limit int) ([]*model.Step, error) {
+	ret := _m.Called(id, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StepListAfter")
+	}
+
+	var r0 []*model.Step
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, int) ([]*model.Step, error)); ok {
+		return rf(id, limit)
+	}
+	if rf, ok := ret.Get(0).(func(int64, int) []*model.Step); ok {
+		r0 = rf(id, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Step)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, int) error); ok {
+		r1 = rf(id, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // StepListFromWorkflowFind provides a mock function with given fields: _a0
 func (_m *Store) StepListFromWorkflowFind(_a0 *model.Workflow) ([]*model.Step, error) {
 	ret := _m.Called(_a0)
@@ -2424,6 +2670,42 @@ func (_m *Store) StepLoad(_a0 int64) (*model.Step, error) {
 	return r0, r1
 }
 
+// StepResetLogSize provides a mock function with given fields: _a0
+func (_m *Store) StepResetLogSize(_a0 *model.Step) error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StepResetLogSize")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Step) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StepSetLogTruncated provides a mock function with given fields: _a0
+func (_m *Store) StepSetLogTruncated(_a0 *model.Step) error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StepSetLogTruncated")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Step) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // StepUpdate provides a mock function with given fields: _a0
 func (_m *Store) StepUpdate(_a0 *model.Step) error {
 	ret := _m.Called(_a0)
@@ -2460,6 +2742,24 @@ func (_m *Store) TaskDelete(_a0 string) error {
 	return r0
 }
 
+// TaskExtendLease provides a mock function with given fields: id, until
+func (_m *Store) TaskExtendLease(id string, until time.Time) error {
+	ret := _m.Called(id, until)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TaskExtendLease")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, time.Time) error); ok {
+		r0 = rf(id, until)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // TaskInsert provides a mock function with given fields: _a0
 func (_m *Store) TaskInsert(_a0 *model.Task) error {
 	ret := _m.Called(_a0)
@@ -2478,6 +2778,34 @@ func (_m *Store) TaskInsert(_a0 *model.Task) error {
 	return r0
 }
 
+// TaskLease provides a mock function with given fields: id, owner, agentID, until
+func (_m *Store) TaskLease(id string, owner string, agentID int64, until time.Time) (bool, error) {
+	ret := _m.Called(id, owner, agentID, until)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TaskLease")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, int64, time.Time) (bool, error)); ok {
+		return rf(id, owner, agentID, until)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, int64, time.Time) bool); ok {
+		r0 = rf(id, owner, agentID, until)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, int64, time.Time) error); ok {
+		r1 = rf(id, owner, agentID, until)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // TaskList provides a mock function with given fields:
 func (_m *Store) TaskList() ([]*model.Task, error) {
 	ret := _m.Called()
@@ -18,6 +18,7 @@ package store
 
 import (
 	"context"
+	"time"
 
 	"go.woodpecker-ci.org/woodpecker/v2/server/model"
 )
@@ -107,11 +108,16 @@ type Store interface {
 	ConfigsForPipeline(pipelineID int64) ([]*model.Config, error)
 	ConfigPersist(*model.Config) (*model.Config, error)
 	PipelineConfigCreate(*model.PipelineConfig) error
+	// ConfigDeleteOrphaned deletes the configs no pipeline uses anymore and which
+	// were last persisted before usedBefore (unix time), except the newest config of
+	// each name of a repo, and returns the number of deleted configs.
+	ConfigDeleteOrphaned(usedBefore int64) (int64, error)
 
 	// Secrets
 	SecretFind(*model.Repo, string) (*model.Secret, error)
 	SecretList(*model.Repo, bool, *model.ListOptions) ([]*model.Secret, error)
 	SecretListAll() ([]*model.Secret, error)
+	SecretRecordUsage(ids []int64, repoID, pipelineNumber, usedAt int64) error
 	SecretCreate(*model.Secret) error
 	SecretUpdate(*model.Secret) error
 	SecretDelete(*model.Secret) error
@@ -139,18 +145,37 @@ type Store interface {
 	StepChild(*model.Pipeline, int, string) (*model.Step, error)
 	StepList(*model.Pipeline) ([]*model.Step, error)
 	StepUpdate(*model.Step) error
+	// StepSetLogTruncated marks the logs of the step as truncated.
+	StepSetLogTruncated(*model.Step) error
+	// StepAddLogSize adds the given number of bytes and lines to the log size of the step.
+	StepAddLogSize(step *model.Step, size, lines int64) error
+	// StepResetLogSize resets the log size and lines of the step after its logs were deleted.
+	StepResetLogSize(*model.Step) error
+	// RepoLogSize returns the log size of all steps of the repo.
+	RepoLogSize(repoID int64) (int64, error)
+	// OrgLogSize returns the log size of all steps of the repos of the org.
+	OrgLogSize(orgID int64) (int64, error)
+	// OrgLogQuotaExists returns whether any org has its own log quota.
+	OrgLogQuotaExists() (bool, error)
 	StepListFromWorkflowFind(*model.Workflow) ([]*model.Step, error)
+	// StepListAfter returns up to limit steps with an id greater than the given one ordered by id.
+	StepListAfter(id int64, limit int) ([]*model.Step, error)
 
 	// Logs
 	LogFind(*model.Step) ([]*model.LogEntry, error)
 	LogAppend(*model.Step, []*model.LogEntry) error
 	LogDelete(*model.Step) error
+	LogSearchIndexFind(*model.Step) ([]byte, error)
+	LogSearchIndexSave(*model.Step, []byte) error
 
 	// Tasks
 	// TaskList TODO: paginate & opt filter
 	TaskList() ([]*model.Task, error)
 	TaskInsert(*model.Task) error
 	TaskDelete(string) error
+	// TaskLease leases a task for an agent to the owner until the given time, unless it holds an unexpired lease
+	TaskLease(id, owner string, agentID int64, until time.Time) (bool, error)
+	TaskExtendLease(id string, until time.Time) error
 
 	// ServerConfig
 	ServerConfigGet(string) (string, error)
@@ -179,6 +204,7 @@ type Store interface {
 	AgentFindByToken(string) (*model.Agent, error)
 	AgentList(p *model.ListOptions) ([]*model.Agent, error)
 	AgentUpdate(*model.Agent) error
+	AgentUpdateLabels(*model.Agent) error
 	AgentDelete(*model.Agent) error
 
 	// Workflow
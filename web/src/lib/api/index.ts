@@ -144,8 +144,14 @@ export default class WoodpeckerClient extends ApiClient {
     return this._post(`/api/repos/${repoId}/pipelines/${pipeline}?${query}`) as Promise<Pipeline>;
   }
 
-  async getLogs(repoId: number, pipeline: number, step: number): Promise<PipelineLog[]> {
-    return this._get(`/api/repos/${repoId}/logs/${pipeline}/${step}`) as Promise<PipelineLog[]>;
+  async getLogs(
+    repoId: number,
+    pipeline: number,
+    step: number,
+    opts?: { from_line?: number; to_line?: number; offset?: number; limit?: number },
+  ): Promise<PipelineLog[]> {
+    const query = encodeQueryString(opts);
+    return this._get(`/api/repos/${repoId}/logs/${pipeline}/${step}?${query}`) as Promise<PipelineLog[]>;
   }
 
   async deleteLogs(repoId: number, pipeline: number, step: number): Promise<unknown> {
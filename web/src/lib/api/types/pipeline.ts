@@ -130,6 +130,7 @@ export interface PipelineStep {
   end_time?: number;
   error?: string;
   type?: StepType;
+  log_truncated?: boolean;
 }
 
 export interface PipelineLog {
@@ -153,4 +154,15 @@ export enum StepType {
   Commands = 'commands',
   Cache = 'cache',
 }
+
+export enum LogEntryType {
+  Stdout = 0,
+  Stderr = 1,
+  ExitCode = 2,
+  Metadata = 3,
+  Progress = 4,
+  Truncated = 5,
+  SectionStart = 6,
+  SectionEnd = 7,
+}
 /* eslint-enable */
@@ -47,6 +47,9 @@ const (
 	LogEntryExitCode
 	LogEntryMetadata
 	LogEntryProgress
+	LogEntryTruncated
+	LogEntrySectionStart
+	LogEntrySectionEnd
 )
 
 // StepType identifies the type of step.
@@ -15,6 +15,7 @@
 package woodpecker
 
 import (
+	"io"
 	"net/http"
 )
 
@@ -49,6 +50,9 @@ type Client interface {
 	// Repo returns a repository by name.
 	Repo(repoID int64) (*Repo, error)
 
+	// RepoUsage returns the log storage used by a repository.
+	RepoUsage(repoID int64) (*StorageUsage, error)
+
 	// RepoLookup returns a repository id by the owner and name.
 	RepoLookup(repoFullName string) (*Repo, error)
 
@@ -85,6 +89,9 @@ type Client interface {
 	// will result in the default branch.
 	PipelineLast(repoID int64, branch string) (*Pipeline, error)
 
+	// PipelineExplain returns why the workflows of a pipeline are still pending.
+	PipelineExplain(repoID, pipeline int64) ([]*QueueExplanation, error)
+
 	// PipelineList returns a list of recent pipelines for the
 	// the specified repository.
 	PipelineList(repoID int64) ([]*Pipeline, error)
@@ -113,10 +120,20 @@ type Client interface {
 	// StepLogEntries returns the LogEntries for the given pipeline step
 	StepLogEntries(repoID, pipeline, stepID int64) ([]*LogEntry, error)
 
+	// StepLogEntriesRange returns the selected LogEntries for the given pipeline step
+	StepLogEntriesRange(repoID, pipeline, stepID int64, r LogRange) ([]*LogEntry, error)
+
 	// Deploy triggers a deployment for an existing pipeline using the specified
 	// target environment.
 	Deploy(repoID, pipeline int64, env string, params map[string]string) (*Pipeline, error)
 
+	// PipelineLogArchive returns an archive in the given format (tar.gz or zip)
+	// with the logs of all steps of the pipeline.
+	PipelineLogArchive(repoID, pipeline int64, format string, stripANSI bool) (io.ReadCloser, error)
+
+	// LogSearch searches the pipeline logs of the specified repository.
+	LogSearch(repoID int64, opt LogSearchOptions) (*LogSearchResult, error)
+
 	// LogsPurge purges the pipeline logs for the specified pipeline.
 	LogsPurge(repoID, pipeline int64) error
 
@@ -189,6 +206,12 @@ type Client interface {
 	// OrgLookup returns an organization id by name.
 	OrgLookup(orgName string) (*Org, error)
 
+	// OrgUpdate updates an organization.
+	OrgUpdate(orgID int64, patch *OrgPatch) (*Org, error)
+
+	// OrgUsage returns the log storage used by all repositories of an organization.
+	OrgUsage(orgID int64) (*StorageUsage, error)
+
 	// OrgSecret returns an organization secret by name.
 	OrgSecret(orgID int64, secret string) (*Secret, error)
 
@@ -6,9 +6,11 @@
 package mocks
 
 import (
+	io "io"
 	http "net/http"
 
 	mock "github.com/stretchr/testify/mock"
+
 	woodpecker "go.woodpecker-ci.org/woodpecker/v2/woodpecker-go/woodpecker"
 )
 
@@ -659,6 +661,36 @@ func (_m *Client) LogLevel() (*woodpecker.LogLevel, error) {
 	return r0, r1
 }
 
+// LogSearch provides a mock function with given fields: repoID, opt
+func (_m *Client) LogSearch(repoID int64, opt woodpecker.LogSearchOptions) (*woodpecker.LogSearchResult, error) {
+	ret := _m.Called(repoID, opt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LogSearch")
+	}
+
+	var r0 *woodpecker.LogSearchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, woodpecker.LogSearchOptions) (*woodpecker.LogSearchResult, error)); ok {
+		return rf(repoID, opt)
+	}
+	if rf, ok := ret.Get(0).(func(int64, woodpecker.LogSearchOptions) *woodpecker.LogSearchResult); ok {
+		r0 = rf(repoID, opt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*woodpecker.LogSearchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, woodpecker.LogSearchOptions) error); ok {
+		r1 = rf(repoID, opt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // LogsPurge provides a mock function with given fields: repoID, pipeline
 func (_m *Client) LogsPurge(repoID int64, pipeline int64) error {
 	ret := _m.Called(repoID, pipeline)
@@ -1013,6 +1045,66 @@ func (_m *Client) OrgSecretUpdate(orgID int64, secret *woodpecker.Secret) (*wood
 	return r0, r1
 }
 
+// OrgUpdate provides a mock function with given fields: orgID, patch
+func (_m *Client) OrgUpdate(orgID int64, patch *woodpecker.OrgPatch) (*woodpecker.Org, error) {
+	ret := _m.Called(orgID, patch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OrgUpdate")
+	}
+
+	var r0 *woodpecker.Org
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, *woodpecker.OrgPatch) (*woodpecker.Org, error)); ok {
+		return rf(orgID, patch)
+	}
+	if rf, ok := ret.Get(0).(func(int64, *woodpecker.OrgPatch) *woodpecker.Org); ok {
+		r0 = rf(orgID, patch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*woodpecker.Org)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, *woodpecker.OrgPatch) error); ok {
+		r1 = rf(orgID, patch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrgUsage provides a mock function with given fields: orgID
+func (_m *Client) OrgUsage(orgID int64) (*woodpecker.StorageUsage, error) {
+	ret := _m.Called(orgID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OrgUsage")
+	}
+
+	var r0 *woodpecker.StorageUsage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (*woodpecker.StorageUsage, error)); ok {
+		return rf(orgID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) *woodpecker.StorageUsage); ok {
+		r0 = rf(orgID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*woodpecker.StorageUsage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(orgID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Pipeline provides a mock function with given fields: repoID, pipeline
 func (_m *Client) Pipeline(repoID int64, pipeline int64) (*woodpecker.Pipeline, error) {
 	ret := _m.Called(repoID, pipeline)
@@ -1133,6 +1225,36 @@ func (_m *Client) PipelineDecline(repoID int64, pipeline int64) (*woodpecker.Pip
 	return r0, r1
 }
 
+// PipelineExplain provides a mock function with given fields: repoID, pipeline
+func (_m *Client) PipelineExplain(repoID int64, pipeline int64) ([]*woodpecker.QueueExplanation, error) {
+	ret := _m.Called(repoID, pipeline)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PipelineExplain")
+	}
+
+	var r0 []*woodpecker.QueueExplanation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, int64) ([]*woodpecker.QueueExplanation, error)); ok {
+		return rf(repoID, pipeline)
+	}
+	if rf, ok := ret.Get(0).(func(int64, int64) []*woodpecker.QueueExplanation); ok {
+		r0 = rf(repoID, pipeline)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*woodpecker.QueueExplanation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, int64) error); ok {
+		r1 = rf(repoID, pipeline)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // PipelineKill provides a mock function with given fields: repoID, pipeline
 func (_m *Client) PipelineKill(repoID int64, pipeline int64) error {
 	ret := _m.Called(repoID, pipeline)
@@ -1211,6 +1333,36 @@ func (_m *Client) PipelineList(repoID int64) ([]*woodpecker.Pipeline, error) {
 	return r0, r1
 }
 
+// PipelineLogArchive provides a mock function with given fields: repoID, pipeline, format, stripANSI
+func (_m *Client) PipelineLogArchive(repoID int64, pipeline int64, format string, stripANSI bool) (io.ReadCloser, error) {
+	ret := _m.Called(repoID, pipeline, format, stripANSI)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PipelineLogArchive")
+	}
+
+	var r0 io.ReadCloser
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, int64, string, bool) (io.ReadCloser, error)); ok {
+		return rf(repoID, pipeline, format, stripANSI)
+	}
+	if rf, ok := ret.Get(0).(func(int64, int64, string, bool) io.ReadCloser); ok {
+		r0 = rf(repoID, pipeline, format, stripANSI)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, int64, string, bool) error); ok {
+		r1 = rf(repoID, pipeline, format, stripANSI)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // PipelineQueue provides a mock function with given fields:
 func (_m *Client) PipelineQueue() ([]*woodpecker.Feed, error) {
 	ret := _m.Called()
@@ -1721,6 +1873,36 @@ func (_m *Client) RepoRepair(repoID int64) error {
 	return r0
 }
 
+// RepoUsage provides a mock function with given fields: repoID
+func (_m *Client) RepoUsage(repoID int64) (*woodpecker.StorageUsage, error) {
+	ret := _m.Called(repoID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RepoUsage")
+	}
+
+	var r0 *woodpecker.StorageUsage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (*woodpecker.StorageUsage, error)); ok {
+		return rf(repoID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) *woodpecker.StorageUsage); ok {
+		r0 = rf(repoID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*woodpecker.StorageUsage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(repoID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Secret provides a mock function with given fields: repoID, secret
 func (_m *Client) Secret(repoID int64, secret string) (*woodpecker.Secret, error) {
 	ret := _m.Called(repoID, secret)
@@ -1959,6 +2141,36 @@ func (_m *Client) StepLogEntries(repoID int64, pipeline int64, stepID int64) ([]
 	return r0, r1
 }
 
+// StepLogEntriesRange provides a mock function with given fields: repoID, pipeline, stepID, r
+func (_m *Client) StepLogEntriesRange(repoID int64, pipeline int64, stepID int64, r woodpecker.LogRange) ([]*woodpecker.LogEntry, error) {
+	ret := _m.Called(repoID, pipeline, stepID, r)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StepLogEntriesRange")
+	}
+
+	var r0 []*woodpecker.LogEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, int64, int64, woodpecker.LogRange) ([]*woodpecker.LogEntry, error)); ok {
+		return rf(repoID, pipeline, stepID, r)
+	}
+	if rf, ok := ret.Get(0).(func(int64, int64, int64, woodpecker.LogRange) []*woodpecker.LogEntry); ok {
+		r0 = rf(repoID, pipeline, stepID, r)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*woodpecker.LogEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, int64, int64, woodpecker.LogRange) error); ok {
+		r1 = rf(repoID, pipeline, stepID, r)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // StepLogsPurge provides a mock function with given fields: repoID, pipelineNumber, stepID
 func (_m *Client) StepLogsPurge(repoID int64, pipelineNumber int64, stepID int64) error {
 	ret := _m.Called(repoID, pipelineNumber, stepID)
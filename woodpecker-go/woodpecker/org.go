@@ -5,6 +5,7 @@ import "fmt"
 const (
 	pathOrg           = "%s/api/orgs/%d"
 	pathOrgLookup     = "%s/api/orgs/lookup/%s"
+	pathOrgUsage      = "%s/api/orgs/%d/usage"
 	pathOrgSecrets    = "%s/api/orgs/%d/secrets"
 	pathOrgSecret     = "%s/api/orgs/%d/secrets/%s"
 	pathOrgRegistries = "%s/api/orgs/%d/registries"
@@ -27,6 +28,22 @@ func (c *client) OrgLookup(name string) (*Org, error) {
 	return out, err
 }
 
+// OrgUpdate updates an organization.
+func (c *client) OrgUpdate(orgID int64, in *OrgPatch) (*Org, error) {
+	out := new(Org)
+	uri := fmt.Sprintf(pathOrg, c.addr, orgID)
+	err := c.patch(uri, in, out)
+	return out, err
+}
+
+// OrgUsage returns the log storage used by all repositories of an organization.
+func (c *client) OrgUsage(orgID int64) (*StorageUsage, error) {
+	out := new(StorageUsage)
+	uri := fmt.Sprintf(pathOrgUsage, c.addr, orgID)
+	err := c.get(uri, out)
+	return out, err
+}
+
 // OrgSecret returns an organization secret by name.
 func (c *client) OrgSecret(orgID int64, secret string) (*Secret, error) {
 	out := new(Secret)
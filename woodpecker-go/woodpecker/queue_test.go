@@ -61,17 +61,19 @@ func TestClient_QueueInfo(t *testing.T) {
 					},
 				},
 				Stats: struct {
-					Workers       int `json:"worker_count"`
-					Pending       int `json:"pending_count"`
-					WaitingOnDeps int `json:"waiting_on_deps_count"`
-					Running       int `json:"running_count"`
-					Complete      int `json:"completed_count"`
+					Workers        int `json:"worker_count"`
+					Pending        int `json:"pending_count"`
+					WaitingOnDeps  int `json:"waiting_on_deps_count"`
+					WaitingOnLimit int `json:"waiting_on_limit_count"`
+					Running        int `json:"running_count"`
+					Complete       int `json:"completed_count"`
 				}{
-					Workers:       2,
-					Pending:       0,
-					WaitingOnDeps: 0,
-					Running:       0,
-					Complete:      0,
+					Workers:        2,
+					Pending:        0,
+					WaitingOnDeps:  0,
+					WaitingOnLimit: 0,
+					Running:        0,
+					Complete:       0,
 				},
 			},
 			wantErr: false,
@@ -1,11 +1,18 @@
 package woodpecker
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
 
 const (
 	pathRepoPost       = "%s/api/repos?forge_remote_id=%d"
 	pathRepo           = "%s/api/repos/%d"
 	pathRepoLookup     = "%s/api/repos/lookup/%s"
+	pathRepoUsage      = "%s/api/repos/%d/usage"
 	pathRepoMove       = "%s/api/repos/%d/move?to=%s"
 	pathChown          = "%s/api/repos/%d/chown"
 	pathRepair         = "%s/api/repos/%d/repair"
@@ -13,9 +20,12 @@ const (
 	pathPipeline       = "%s/api/repos/%d/pipelines/%v"
 	pathPipelineLogs   = "%s/api/repos/%d/logs/%d"
 	pathStepLogs       = "%s/api/repos/%d/logs/%d/%d"
+	pathLogSearch      = "%s/api/repos/%d/logs/search"
+	pathLogArchive     = "%s/api/repos/%d/logs/%d/archive"
 	pathApprove        = "%s/api/repos/%d/pipelines/%d/approve"
 	pathDecline        = "%s/api/repos/%d/pipelines/%d/decline"
 	pathStop           = "%s/api/repos/%d/pipelines/%d/cancel"
+	pathExplain        = "%s/api/repos/%d/pipelines/%d/explain"
 	pathRepoSecrets    = "%s/api/repos/%d/secrets"
 	pathRepoSecret     = "%s/api/repos/%d/secrets/%s"
 	pathRepoRegistries = "%s/api/repos/%d/registries"
@@ -32,6 +42,14 @@ func (c *client) Repo(repoID int64) (*Repo, error) {
 	return out, err
 }
 
+// RepoUsage returns the log storage used by a repository.
+func (c *client) RepoUsage(repoID int64) (*StorageUsage, error) {
+	out := new(StorageUsage)
+	uri := fmt.Sprintf(pathRepoUsage, c.addr, repoID)
+	err := c.get(uri, out)
+	return out, err
+}
+
 // RepoLookup returns a repository by name.
 func (c *client) RepoLookup(fullName string) (*Repo, error) {
 	out := new(Repo)
@@ -213,6 +231,14 @@ func (c *client) PipelineLast(repoID int64, branch string) (*Pipeline, error) {
 	return out, err
 }
 
+// PipelineExplain returns why the workflows of a pipeline are still pending.
+func (c *client) PipelineExplain(repoID, pipeline int64) ([]*QueueExplanation, error) {
+	var out []*QueueExplanation
+	uri := fmt.Sprintf(pathExplain, c.addr, repoID, pipeline)
+	err := c.get(uri, &out)
+	return out, err
+}
+
 // PipelineList returns a list of recent pipelines for the
 // the specified repository.
 func (c *client) PipelineList(repoID int64) ([]*Pipeline, error) {
@@ -296,6 +322,61 @@ func (c *client) StepLogEntries(repoID, num, step int64) ([]*LogEntry, error) {
 	return out, err
 }
 
+// StepLogEntriesRange returns the selected pipeline logs for the specified step.
+func (c *client) StepLogEntriesRange(repoID, num, step int64, r LogRange) ([]*LogEntry, error) {
+	val := url.Values{}
+	for name, value := range map[string]int{
+		"from_line": r.FromLine,
+		"to_line":   r.ToLine,
+		"offset":    r.Offset,
+		"limit":     r.Limit,
+	} {
+		if value != 0 {
+			val.Set(name, strconv.Itoa(value))
+		}
+	}
+	uri := fmt.Sprintf(pathStepLogs, c.addr, repoID, num, step)
+	var out []*LogEntry
+	err := c.get(uri+"?"+val.Encode(), &out)
+	return out, err
+}
+
+// PipelineLogArchive returns an archive with the logs of all steps of the pipeline.
+func (c *client) PipelineLogArchive(repoID, pipeline int64, format string, stripANSI bool) (io.ReadCloser, error) {
+	val := url.Values{}
+	val.Set("format", format)
+	if stripANSI {
+		val.Set("strip_ansi", "true")
+	}
+	uri := fmt.Sprintf(pathLogArchive, c.addr, repoID, pipeline)
+	return c.open(uri+"?"+val.Encode(), http.MethodGet, nil)
+}
+
+// LogSearch searches the pipeline logs of the specified repository.
+func (c *client) LogSearch(repoID int64, opt LogSearchOptions) (*LogSearchResult, error) {
+	val := url.Values{}
+	val.Set("q", opt.Query)
+	if opt.Regex {
+		val.Set("regex", "true")
+	}
+	for name, value := range map[string]int{
+		"days":    opt.Days,
+		"context": opt.Context,
+		"limit":   opt.Limit,
+	} {
+		if value != 0 {
+			val.Set(name, strconv.Itoa(value))
+		}
+	}
+	if opt.Start != 0 {
+		val.Set("start", strconv.FormatInt(opt.Start, 10))
+	}
+	uri := fmt.Sprintf(pathLogSearch, c.addr, repoID)
+	out := new(LogSearchResult)
+	err := c.get(uri+"?"+val.Encode(), out)
+	return out, err
+}
+
 // StepLogsPurge purges the pipeline logs for the specified step.
 func (c *client) StepLogsPurge(repoID, pipelineNumber, stepID int64) error {
 	uri := fmt.Sprintf(pathStepLogs, c.addr, repoID, pipelineNumber, stepID)
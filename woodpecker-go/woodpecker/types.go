@@ -68,19 +68,31 @@ type (
 		Config                       string       `json:"config_file"`
 		CancelPreviousPipelineEvents []string     `json:"cancel_previous_pipeline_events"`
 		NetrcOnlyTrusted             bool         `json:"netrc_only_trusted"`
+		Priority                     int          `json:"priority"`
+		MaxConcurrent                int          `json:"max_concurrent"`
+		MaxPendingAge                int64        `json:"max_pending_age"`
+		RetentionKeepPerBranch       int          `json:"retention_keep_per_branch"`
+		RetentionDays                int64        `json:"retention_days"`
+		LogQuota                     int64        `json:"log_quota"`
 		// Deprecated
 		IsGated bool `json:"gated,omitempty"` // TODO: remove in next major release
 	}
 
 	// RepoPatch defines a repository patch request.
 	RepoPatch struct {
-		Config          *string       `json:"config_file,omitempty"`
-		IsTrusted       *bool         `json:"trusted,omitempty"`
-		RequireApproval *ApprovalMode `json:"require_approval,omitempty"`
-		Timeout         *int64        `json:"timeout,omitempty"`
-		Visibility      *string       `json:"visibility"`
-		AllowPull       *bool         `json:"allow_pr,omitempty"`
-		PipelineCounter *int          `json:"pipeline_counter,omitempty"`
+		Config                 *string       `json:"config_file,omitempty"`
+		IsTrusted              *bool         `json:"trusted,omitempty"`
+		RequireApproval        *ApprovalMode `json:"require_approval,omitempty"`
+		Timeout                *int64        `json:"timeout,omitempty"`
+		Visibility             *string       `json:"visibility"`
+		AllowPull              *bool         `json:"allow_pr,omitempty"`
+		PipelineCounter        *int          `json:"pipeline_counter,omitempty"`
+		Priority               *int          `json:"priority,omitempty"`
+		MaxConcurrent          *int          `json:"max_concurrent,omitempty"`
+		MaxPendingAge          *int64        `json:"max_pending_age,omitempty"`
+		RetentionKeepPerBranch *int          `json:"retention_keep_per_branch,omitempty"`
+		RetentionDays          *int64        `json:"retention_days,omitempty"`
+		LogQuota               *int64        `json:"log_quota,omitempty"`
 		// Deprecated
 		IsGated *bool `json:"gated,omitempty"` // TODO: remove in next major release
 	}
@@ -127,31 +139,33 @@ type (
 
 	// Workflow represents a workflow in the pipeline.
 	Workflow struct {
-		ID       int64             `json:"id"`
-		PID      int               `json:"pid"`
-		Name     string            `json:"name"`
-		State    string            `json:"state"`
-		Error    string            `json:"error,omitempty"`
-		Started  int64             `json:"start_time,omitempty"`
-		Stopped  int64             `json:"end_time,omitempty"`
-		AgentID  int64             `json:"agent_id,omitempty"`
-		Platform string            `json:"platform,omitempty"`
-		Environ  map[string]string `json:"environ,omitempty"`
-		Children []*Step           `json:"children,omitempty"`
+		ID               int64             `json:"id"`
+		PID              int               `json:"pid"`
+		Name             string            `json:"name"`
+		State            string            `json:"state"`
+		Error            string            `json:"error,omitempty"`
+		Started          int64             `json:"start_time,omitempty"`
+		Stopped          int64             `json:"end_time,omitempty"`
+		AgentID          int64             `json:"agent_id,omitempty"`
+		Platform         string            `json:"platform,omitempty"`
+		Environ          map[string]string `json:"environ,omitempty"`
+		ConcurrencyGroup string            `json:"concurrency_group,omitempty"`
+		Children         []*Step           `json:"children,omitempty"`
 	}
 
 	// Step represents a process in the pipeline.
 	Step struct {
-		ID       int64    `json:"id"`
-		PID      int      `json:"pid"`
-		PPID     int      `json:"ppid"`
-		Name     string   `json:"name"`
-		State    string   `json:"state"`
-		Error    string   `json:"error,omitempty"`
-		ExitCode int      `json:"exit_code"`
-		Started  int64    `json:"start_time,omitempty"`
-		Stopped  int64    `json:"end_time,omitempty"`
-		Type     StepType `json:"type,omitempty"`
+		ID           int64    `json:"id"`
+		PID          int      `json:"pid"`
+		PPID         int      `json:"ppid"`
+		Name         string   `json:"name"`
+		State        string   `json:"state"`
+		Error        string   `json:"error,omitempty"`
+		ExitCode     int      `json:"exit_code"`
+		Started      int64    `json:"start_time,omitempty"`
+		Stopped      int64    `json:"end_time,omitempty"`
+		Type         StepType `json:"type,omitempty"`
+		LogTruncated bool     `json:"log_truncated,omitempty"`
 	}
 
 	// Registry represents a docker registry with credentials.
@@ -170,13 +184,17 @@ type (
 
 	// Secret represents a secret variable, such as a password or token.
 	Secret struct {
-		ID     int64    `json:"id"`
-		OrgID  int64    `json:"org_id"`
-		RepoID int64    `json:"repo_id"`
-		Name   string   `json:"name"`
-		Value  string   `json:"value,omitempty"`
-		Images []string `json:"images"`
-		Events []string `json:"events"`
+		ID               int64    `json:"id"`
+		OrgID            int64    `json:"org_id"`
+		RepoID           int64    `json:"repo_id"`
+		Name             string   `json:"name"`
+		Value            string   `json:"value,omitempty"`
+		Images           []string `json:"images"`
+		Events           []string `json:"events"`
+		UsageCount       int64    `json:"usage_count,omitempty"`
+		LastUsedAt       int64    `json:"last_used_at,omitempty"`
+		LastUsedRepoID   int64    `json:"last_used_repo_id,omitempty"`
+		LastUsedPipeline int64    `json:"last_used_pipeline,omitempty"`
 	}
 
 	// Feed represents an item in the user's feed or timeline.
@@ -220,19 +238,68 @@ type (
 
 	// Info provides queue stats.
 	Info struct {
-		Pending       []Task `json:"pending"`
-		WaitingOnDeps []Task `json:"waiting_on_deps"`
-		Running       []Task `json:"running"`
+		Pending        []Task `json:"pending"`
+		WaitingOnDeps  []Task `json:"waiting_on_deps"`
+		WaitingOnLimit []Task `json:"waiting_on_limit"`
+		Running        []Task `json:"running"`
 		// TODO: use dedicated struct in 3.x
 		// Stats         QueueStats `json:"stats"`
 		Stats struct {
-			Workers       int `json:"worker_count"`
-			Pending       int `json:"pending_count"`
-			WaitingOnDeps int `json:"waiting_on_deps_count"`
-			Running       int `json:"running_count"`
-			Complete      int `json:"completed_count"`
+			Workers        int `json:"worker_count"`
+			Pending        int `json:"pending_count"`
+			WaitingOnDeps  int `json:"waiting_on_deps_count"`
+			WaitingOnLimit int `json:"waiting_on_limit_count"`
+			Running        int `json:"running_count"`
+			Complete       int `json:"completed_count"`
 		} `json:"stats"`
-		Paused bool `json:"paused,omitempty"`
+		Priorities      map[string]int             `json:"priorities"`
+		Shares          []TenantShare              `json:"shares,omitempty"`
+		NoEligibleAgent map[string][]AgentMismatch `json:"no_eligible_agent,omitempty"`
+		IdleWorkers     map[int64]int              `json:"idle_workers,omitempty"`
+		Paused          bool                       `json:"paused,omitempty"`
+	}
+
+	// AgentMismatch explains why an agent can't run a task.
+	AgentMismatch struct {
+		AgentID   int64  `json:"agent_id"`
+		AgentName string `json:"agent_name"`
+		Reason    string `json:"reason"`
+	}
+
+	// QueueExplanation explains why a workflow is still pending.
+	QueueExplanation struct {
+		TaskID     string             `json:"task_id"`
+		Workflow   string             `json:"workflow"`
+		State      string             `json:"state"`
+		Reasons    []string           `json:"reasons,omitempty"`
+		WaitingFor []string           `json:"waiting_for,omitempty"`
+		Agents     []AgentExplanation `json:"agents"`
+	}
+
+	// AgentExplanation explains whether a connected agent can run a workflow.
+	AgentExplanation struct {
+		AgentID   int64  `json:"agent_id"`
+		AgentName string `json:"agent_name"`
+		Eligible  bool   `json:"eligible"`
+		Reason    string `json:"reason,omitempty"`
+	}
+
+	// TenantShare is the fair-share state of an organization in the queue.
+	TenantShare struct {
+		OrgID     int64       `json:"org_id"`
+		Weight    int         `json:"weight"`
+		Running   int         `json:"running"`
+		Pending   int         `json:"pending"`
+		Share     float64     `json:"share"`
+		FairShare float64     `json:"fair_share"`
+		Repos     []RepoShare `json:"repos"`
+	}
+
+	// RepoShare is the fair-share state of a repository in the queue.
+	RepoShare struct {
+		RepoID  int64 `json:"repo_id"`
+		Running int   `json:"running"`
+		Pending int   `json:"pending"`
 	}
 
 	// LogLevel is for checking/setting logging level.
@@ -250,6 +317,67 @@ type (
 		Type   LogEntryType `json:"type"`
 	}
 
+	// LogSearchOptions configures a search of the logs of a repository.
+	LogSearchOptions struct {
+		Query   string
+		Regex   bool
+		Days    int
+		Context int
+		Limit   int
+		// Start continues a search at this pipeline number, see LogSearchResult.Next.
+		Start int64
+	}
+
+	// LogSearchResult contains the pipelines with logs matching a search.
+	LogSearchResult struct {
+		Pipelines []*LogSearchPipeline `json:"pipelines"`
+		// Next is the pipeline number to continue the search at, if the search
+		// stopped before all pipelines were searched.
+		Next int64 `json:"next,omitempty"`
+	}
+
+	// LogSearchPipeline is a pipeline with logs matching a search.
+	LogSearchPipeline struct {
+		ID        int64                `json:"id"`
+		Number    int64                `json:"number"`
+		Created   int64                `json:"created"`
+		Status    string               `json:"status"`
+		Event     string               `json:"event"`
+		Branch    string               `json:"branch"`
+		Workflows []*LogSearchWorkflow `json:"workflows"`
+	}
+
+	// LogSearchWorkflow is a workflow with logs matching a search.
+	LogSearchWorkflow struct {
+		ID    int64            `json:"id"`
+		Name  string           `json:"name"`
+		Steps []*LogSearchStep `json:"steps"`
+	}
+
+	// LogSearchStep is a step with logs matching a search.
+	LogSearchStep struct {
+		ID      int64             `json:"id"`
+		Name    string            `json:"name"`
+		Matches []*LogSearchMatch `json:"matches"`
+	}
+
+	// LogSearchMatch is a log line matching a search with the lines around it.
+	LogSearchMatch struct {
+		Line   int      `json:"line"`
+		Text   string   `json:"text"`
+		Before []string `json:"before,omitempty"`
+		After  []string `json:"after,omitempty"`
+	}
+
+	// LogRange selects a part of the log entries of a step. Zero values
+	// select all entries.
+	LogRange struct {
+		FromLine int
+		ToLine   int
+		Offset   int
+		Limit    int
+	}
+
 	// Cron is the JSON data of a cron job.
 	Cron struct {
 		ID        int64  `json:"id"`
@@ -270,36 +398,71 @@ type (
 
 	// Agent is the JSON data for an agent.
 	Agent struct {
-		ID          int64  `json:"id"`
-		Created     int64  `json:"created"`
-		Updated     int64  `json:"updated"`
-		Name        string `json:"name"`
-		OwnerID     int64  `json:"owner_id"`
-		Token       string `json:"token"`
-		LastContact int64  `json:"last_contact"`
-		LastWork    int64  `json:"last_work"`
-		Platform    string `json:"platform"`
-		Backend     string `json:"backend"`
-		Capacity    int32  `json:"capacity"`
-		Version     string `json:"version"`
-		NoSchedule  bool   `json:"no_schedule"`
+		ID          int64             `json:"id"`
+		Created     int64             `json:"created"`
+		Updated     int64             `json:"updated"`
+		Name        string            `json:"name"`
+		OwnerID     int64             `json:"owner_id"`
+		Token       string            `json:"token"`
+		LastContact int64             `json:"last_contact"`
+		LastWork    int64             `json:"last_work"`
+		Platform    string            `json:"platform"`
+		Backend     string            `json:"backend"`
+		Capacity    int32             `json:"capacity"`
+		Version     string            `json:"version"`
+		NoSchedule  bool              `json:"no_schedule"`
+		Resources   Resources         `json:"resources"`
+		Labels      map[string]string `json:"labels"`
+	}
+
+	// Resources defines amounts of CPU in millicores, memory and disk in bytes.
+	Resources struct {
+		CPU    int64 `json:"cpu"`
+		Memory int64 `json:"memory"`
+		Disk   int64 `json:"disk"`
 	}
 
 	// Task is the JSON data for a task.
 	Task struct {
-		ID           string            `json:"id"`
-		Data         []byte            `json:"data"`
-		Labels       map[string]string `json:"labels"`
-		Dependencies []string          `json:"dependencies"`
-		RunOn        []string          `json:"run_on"`
-		DepStatus    map[string]string `json:"dep_status"`
-		AgentID      int64             `json:"agent_id"`
+		ID               string            `json:"id"`
+		Data             []byte            `json:"data"`
+		Labels           map[string]string `json:"labels"`
+		Dependencies     []string          `json:"dependencies"`
+		RunOn            []string          `json:"run_on"`
+		DepStatus        map[string]string `json:"dep_status"`
+		AgentID          int64             `json:"agent_id"`
+		Priority         int               `json:"priority"`
+		RepoID           int64             `json:"repo_id"`
+		OrgID            int64             `json:"org_id"`
+		ConcurrencyGroup string            `json:"concurrency_group,omitempty"`
+		Resources        Resources         `json:"resources"`
 	}
 
 	// Org is the JSON data for an organization.
 	Org struct {
-		ID     int64  `json:"id"`
-		Name   string `json:"name"`
-		IsUser bool   `json:"is_user"`
+		ID            int64  `json:"id"`
+		Name          string `json:"name"`
+		IsUser        bool   `json:"is_user"`
+		Priority      int    `json:"priority"`
+		Weight        int    `json:"weight"`
+		MaxConcurrent int    `json:"max_concurrent"`
+		LogQuota      int64  `json:"log_quota"`
+	}
+
+	// OrgPatch defines an organization patch request.
+	OrgPatch struct {
+		Priority      *int   `json:"priority,omitempty"`
+		Weight        *int   `json:"weight,omitempty"`
+		MaxConcurrent *int   `json:"max_concurrent,omitempty"`
+		LogQuota      *int64 `json:"log_quota,omitempty"`
+	}
+
+	// StorageUsage is the JSON data for the log storage used by a repository or
+	// organization.
+	StorageUsage struct {
+		LogBytes int64 `json:"log_bytes"`
+		LogQuota int64 `json:"log_quota"`
+		Warning  bool  `json:"warning"`
+		Exceeded bool  `json:"exceeded"`
 	}
 )